@@ -0,0 +1,50 @@
+package adapters
+
+import (
+	"context"
+	"encoding/json"
+)
+
+// ChatMessage is one turn of an OpenAI-style conversation.
+type ChatMessage struct {
+	Role    string `json:"role" example:"user"`
+	Content string `json:"content" example:"Hello, how are you?"`
+}
+
+// ChatRequest is the payload for adapters with native chat support.
+type ChatRequest struct {
+	Model       string        `json:"model"`
+	Messages    []ChatMessage `json:"messages"`
+	Temperature *float64      `json:"temperature,omitempty"`
+	TopP        *float64      `json:"top_p,omitempty"`
+	MaxTokens   int           `json:"max_tokens,omitempty"`
+	Stop        []string      `json:"stop,omitempty"`
+}
+
+// ChatChoice is a single chat completion choice.
+type ChatChoice struct {
+	Index        int         `json:"index"`
+	Message      ChatMessage `json:"message"`
+	FinishReason string      `json:"finish_reason"`
+}
+
+// ChatResponse is the OpenAI chat.completion object.
+type ChatResponse struct {
+	ID      string       `json:"id"`
+	Object  string       `json:"object"`
+	Created int64        `json:"created"`
+	Model   string       `json:"model"`
+	Choices []ChatChoice `json:"choices"`
+	Usage   Usage        `json:"usage"`
+}
+
+// Chat sends req to the adapter's /chat/completions endpoint under the
+// model's retry policy and circuit breaker.
+func Chat(ctx context.Context, auth, baseURL, failHeader string, req ChatRequest) (ChatResponse, error) {
+	body, _ := json.Marshal(req)
+	var res ChatResponse
+	if err := postWithPolicy(ctx, req.Model, auth, baseURL+"/chat/completions", failHeader, body, &res); err != nil {
+		return ChatResponse{}, err
+	}
+	return res, nil
+}
@@ -2,11 +2,11 @@ package adapters
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"io"
 	"net/http"
-	"os"
 	"strings"
 	"time"
 
@@ -14,45 +14,153 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
+// CompletionRequest is the payload forwarded to an adapter. Optional
+// generation parameters are omitted when unset so adapter defaults apply.
+type CompletionRequest struct {
+	Prompt        string   `json:"prompt" example:"Hello world"`
+	Model         string   `json:"model" example:"adapter-a"`
+	Temperature   *float64 `json:"temperature,omitempty" example:"0.7"`
+	MaxTokens     int      `json:"max_tokens,omitempty" example:"256"`
+	TopP          *float64 `json:"top_p,omitempty" example:"0.9"`
+	SystemPrompt  string   `json:"system_prompt,omitempty" example:"You are a helpful assistant"`
+	StopSequences []string `json:"stop_sequences,omitempty"`
+	Stream        bool     `json:"stream,omitempty"`
+}
+
+// Usage is the token accounting reported by an adapter.
+type Usage struct {
+	PromptTokens     int `json:"prompt_tokens" example:"10"`
+	CompletionTokens int `json:"completion_tokens" example:"25"`
+	TotalTokens      int `json:"total_tokens" example:"35"`
+}
+
+// Completion is an adapter's answer to a CompletionRequest.
+type Completion struct {
+	Text         string `json:"completion"`
+	Usage        Usage  `json:"usage"`
+	RequestID    string `json:"request_id"`
+	FinishReason string `json:"finish_reason"`
+}
+
 // CallAdapter wraps the adapter call and always returns a valid error if response is not valid JSON
 func CallAdapter(c *gin.Context, baseURL, prompt, model, failHeader string) (string, error) {
-	client := &http.Client{Timeout: 2 * time.Second}
-	payload := map[string]string{"prompt": prompt, "model": model}
-	body, _ := json.Marshal(payload)
+	return CallAdapterContext(c.Request.Context(), c.GetHeader("Authorization"), baseURL, prompt, model, failHeader)
+}
+
+// CallAdapterContext is CallAdapter without a gin request, for background work
+// such as batch jobs. auth is forwarded as the Authorization header when set.
+func CallAdapterContext(ctx context.Context, auth, baseURL, prompt, model, failHeader string) (string, error) {
+	res, err := Complete(ctx, auth, baseURL, failHeader, CompletionRequest{Prompt: prompt, Model: model})
+	return res.Text, err
+}
+
+// Complete sends req to the adapter's /complete endpoint. Retries, timeouts
+// and the circuit breaker follow the model's ResiliencePolicy.
+func Complete(ctx context.Context, auth, baseURL, failHeader string, req CompletionRequest) (Completion, error) {
+	req.Stream = false
+	body, _ := json.Marshal(req)
+	var res Completion
+	if err := postWithPolicy(ctx, req.Model, auth, baseURL+"/complete", failHeader, body, &res); err != nil {
+		return Completion{}, err
+	}
+	return res, nil
+}
+
+// postWithPolicy POSTs body to url on behalf of model and decodes the JSON
+// answer into out, applying the model's retry policy and circuit breaker.
+func postWithPolicy(ctx context.Context, model, auth, url, failHeader string, body []byte, out any) error {
+	p := PolicyFor(model)
+	if !allowCall(model, p) {
+		return ErrCircuitOpen
+	}
+	ctx, cancel := context.WithTimeout(ctx, time.Duration(p.DeadlineMs)*time.Millisecond)
+	defer cancel()
+	client := &http.Client{Timeout: time.Duration(p.AttemptTimeoutMs) * time.Millisecond}
 	var lastErr error
-	for attempt := 0; attempt < 3; attempt++ {
-		req, _ := http.NewRequestWithContext(c.Request.Context(), http.MethodPost, baseURL+"/complete", bytes.NewReader(body))
-		req.Header.Set("content-type", "application/json")
-		if auth := c.GetHeader("Authorization"); strings.TrimSpace(auth) != "" {
-			req.Header.Set("Authorization", auth)
-		} else {
-			req.Header.Set("Authorization", "Bearer internal-service")
+	for attempt := 1; attempt <= p.MaxAttempts; attempt++ {
+		wait, retry, err := adapterAttempt(ctx, client, auth, url, body, failHeader, p, out)
+		if err == nil {
+			adapterAttempts.WithLabelValues(model, "success").Inc()
+			recordResult(model, p, callSucceeded)
+			return nil
 		}
-		if failHeader != "" {
-			req.Header.Set("x-test-fail", failHeader)
+		lastErr = err
+		if !retry || attempt == p.MaxAttempts {
+			break
 		}
-		resp, err := client.Do(req)
-		if err != nil {
-			lastErr = err
-		} else {
-			defer resp.Body.Close()
-			if resp.StatusCode >= 200 && resp.StatusCode < 300 {
-				rb, _ := io.ReadAll(resp.Body)
-				var tmp struct {
-					Completion string `json:"completion"`
-				}
-				if err := json.Unmarshal(rb, &tmp); err != nil {
-					lastErr = errors.New("invalid adapter response: " + err.Error())
-				} else {
-					return tmp.Completion, nil
-				}
-			} else {
-				lastErr = errors.New("adapter status " + resp.Status)
-			}
+		adapterAttempts.WithLabelValues(model, "retry").Inc()
+		if backoff := p.backoff(attempt); wait < backoff {
+			wait = backoff
+		}
+		if dl, ok := ctx.Deadline(); ok && time.Until(dl) < wait {
+			break
+		}
+		select {
+		case <-ctx.Done():
+		case <-time.After(wait):
 		}
-		time.Sleep(time.Duration(100*(attempt+1)) * time.Millisecond)
+		if ctx.Err() != nil {
+			break
+		}
+	}
+	adapterAttempts.WithLabelValues(model, "failure").Inc()
+	var statusErr *AdapterStatusError
+	switch {
+	case errors.As(lastErr, &statusErr) && !p.Retryable(statusErr.Code):
+		// the adapter answered; a non-retryable status is not an outage
+		recordResult(model, p, callSucceeded)
+	case ctx.Err() == context.Canceled:
+		recordResult(model, p, callAbandoned)
+	default:
+		recordResult(model, p, callFailed)
+	}
+	return lastErr
+}
+
+// AdapterStatusError reports a non-2xx adapter response.
+type AdapterStatusError struct {
+	Code   int
+	Status string
+}
+
+func (e *AdapterStatusError) Error() string { return "adapter status " + e.Status }
+
+// adapterAttempt performs one POST. wait is the server's Retry-After hint,
+// retry whether another attempt may help.
+func adapterAttempt(ctx context.Context, client *http.Client, auth, url string, body []byte, failHeader string, p ResiliencePolicy, out any) (wait time.Duration, retry bool, err error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return 0, false, err
+	}
+	req.Header.Set("content-type", "application/json")
+	if strings.TrimSpace(auth) != "" {
+		req.Header.Set("Authorization", auth)
+	} else {
+		req.Header.Set("Authorization", "Bearer internal-service")
 	}
-	return "", lastErr
+	if failHeader != "" {
+		req.Header.Set("x-test-fail", failHeader)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return 0, ctx.Err() == nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		err = &AdapterStatusError{Code: resp.StatusCode, Status: resp.Status}
+		if !p.Retryable(resp.StatusCode) {
+			return 0, false, err
+		}
+		if resp.StatusCode == http.StatusTooManyRequests {
+			wait = retryAfter(resp.Header.Get("Retry-After"))
+		}
+		return wait, true, err
+	}
+	rb, _ := io.ReadAll(resp.Body)
+	if err := json.Unmarshal(rb, out); err != nil {
+		return 0, true, errors.New("invalid adapter response: " + err.Error())
+	}
+	return 0, false, nil
 }
 
 // Adapter proxy authentication middleware
@@ -79,22 +187,9 @@ func AdapterProxyAuth(tokenizer users.Tokenizer, serviceKey string, healthPath s
 	}
 }
 
-// Resolve adapter URL by model name
+// Resolve adapter URL by model name using the model registry
 func ResolveAdapterURL(model string) (string, error) {
-	switch model {
-	case "adapter-a":
-		if u := os.Getenv("ADAPTER_A_URL"); u != "" {
-			return u, nil
-		}
-		return "http://localhost:8081", nil
-	case "adapter-b":
-		if u := os.Getenv("ADAPTER_B_URL"); u != "" {
-			return u, nil
-		}
-		return "http://localhost:8082", nil
-	default:
-		return "", errors.New("unknown model")
-	}
+	return DefaultRegistry.PickURL(model)
 }
 
 // Proxy handler to forward requests to adapter
@@ -0,0 +1,339 @@
+package adapters
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	ErrUnknownModel     = errors.New("unknown model")
+	ErrModelUnavailable = errors.New("model unavailable")
+)
+
+// ModelEntry describes a model the gateway can route completions to.
+type ModelEntry struct {
+	Name         string         `json:"name" yaml:"name"`
+	Type         string         `json:"type" yaml:"type"`
+	Version      string         `json:"version" yaml:"version"`
+	URLs         []string       `json:"urls" yaml:"urls"`
+	Capabilities []string       `json:"capabilities" yaml:"capabilities"`
+	MaxTokens    int            `json:"max_tokens" yaml:"max_tokens"`
+	Status       string         `json:"status" yaml:"status"`
+	Config       map[string]any `json:"config,omitempty" yaml:"config,omitempty"`
+	// Fallback lists models tried in order when this one fails with a retryable error
+	Fallback []string `json:"fallback,omitempty" yaml:"fallback,omitempty"`
+	// Resilience overrides DefaultResilience for calls to this model
+	Resilience *ResiliencePolicy `json:"resilience,omitempty" yaml:"resilience,omitempty"`
+}
+
+// HasCapability reports whether the entry lists capability.
+func (e ModelEntry) HasCapability(capability string) bool {
+	for _, c := range e.Capabilities {
+		if c == capability {
+			return true
+		}
+	}
+	return false
+}
+
+// registryFile is the on-disk layout of a model registry (YAML or JSON).
+type registryFile struct {
+	Models []ModelEntry `json:"models" yaml:"models"`
+}
+
+// ModelRegistry maps model names to backend URLs and metadata.
+type ModelRegistry struct {
+	mu     sync.RWMutex
+	models map[string]ModelEntry
+	cursor map[string]int // round-robin position per model
+	source string
+}
+
+// DefaultRegistry is the registry used by ResolveAdapterURL and the AI routes.
+var DefaultRegistry = NewModelRegistry(defaultModels()...)
+
+var validModelStatuses = []string{"active", "degraded", "disabled", "deprecated"}
+
+// NewModelRegistry returns a registry seeded with the given entries.
+func NewModelRegistry(entries ...ModelEntry) *ModelRegistry {
+	r := &ModelRegistry{models: map[string]ModelEntry{}, cursor: map[string]int{}}
+	for _, e := range entries {
+		r.models[e.Name] = normalizeModel(e)
+	}
+	return r
+}
+
+// InitRegistry loads DefaultRegistry from path, or keeps the built-in
+// adapter-a/adapter-b entries when path is empty.
+func InitRegistry(path string) error {
+	if strings.TrimSpace(path) == "" {
+		return nil
+	}
+	return DefaultRegistry.LoadFile(path)
+}
+
+// LoadFile replaces the registry contents with the models in a YAML or JSON file.
+func (r *ModelRegistry) LoadFile(path string) error {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	var file registryFile
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		err = json.Unmarshal(raw, &file)
+	} else {
+		err = yaml.Unmarshal(raw, &file)
+	}
+	if err != nil {
+		return fmt.Errorf("parse model registry %s: %w", path, err)
+	}
+	models := map[string]ModelEntry{}
+	for i, e := range file.Models {
+		e = normalizeModel(e)
+		if err := ValidateModel(e); err != nil {
+			return fmt.Errorf("model registry %s: models[%d]: %w", path, i, err)
+		}
+		if _, dup := models[e.Name]; dup {
+			return fmt.Errorf("model registry %s: duplicate model %q", path, e.Name)
+		}
+		models[e.Name] = e
+	}
+	r.mu.Lock()
+	var removed []string
+	for name := range r.models {
+		if _, ok := models[name]; !ok {
+			removed = append(removed, name)
+		}
+	}
+	r.models = models
+	r.cursor = map[string]int{}
+	r.source = path
+	r.mu.Unlock()
+	forgetBreakers(removed...)
+	return nil
+}
+
+// Reload re-reads the file the registry was last loaded from.
+func (r *ModelRegistry) Reload() error {
+	r.mu.RLock()
+	src := r.source
+	r.mu.RUnlock()
+	if src == "" {
+		return errors.New("registry was not loaded from a file")
+	}
+	return r.LoadFile(src)
+}
+
+// Source returns the file path the registry was loaded from, if any.
+func (r *ModelRegistry) Source() string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.source
+}
+
+// Get returns a copy of the named model entry.
+func (r *ModelRegistry) Get(name string) (ModelEntry, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	e, ok := r.models[name]
+	if !ok {
+		return ModelEntry{}, false
+	}
+	return cloneModel(e), true
+}
+
+// List returns all model entries sorted by name.
+func (r *ModelRegistry) List() []ModelEntry {
+	r.mu.RLock()
+	out := make([]ModelEntry, 0, len(r.models))
+	for _, e := range r.models {
+		out = append(out, cloneModel(e))
+	}
+	r.mu.RUnlock()
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return out
+}
+
+// Upsert validates and stores a model entry, replacing any existing one.
+func (r *ModelRegistry) Upsert(e ModelEntry) (ModelEntry, error) {
+	e = normalizeModel(e)
+	if err := ValidateModel(e); err != nil {
+		return ModelEntry{}, err
+	}
+	r.mu.Lock()
+	r.models[e.Name] = e
+	delete(r.cursor, e.Name)
+	r.mu.Unlock()
+	return cloneModel(e), nil
+}
+
+// Update applies fn to a copy of the named entry and stores the result if it validates.
+func (r *ModelRegistry) Update(name string, fn func(*ModelEntry) error) (ModelEntry, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	old, ok := r.models[name]
+	if !ok {
+		return ModelEntry{}, ErrUnknownModel
+	}
+	e := cloneModel(old)
+	if err := fn(&e); err != nil {
+		return ModelEntry{}, err
+	}
+	e.Name = name
+	e = normalizeModel(e)
+	if err := ValidateModel(e); err != nil {
+		return ModelEntry{}, err
+	}
+	r.models[name] = e
+	return cloneModel(e), nil
+}
+
+// Delete removes a model entry, with its circuit breaker, and reports whether
+// it existed.
+func (r *ModelRegistry) Delete(name string) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.models[name]; !ok {
+		return false
+	}
+	delete(r.models, name)
+	delete(r.cursor, name)
+	forgetBreakers(name)
+	return true
+}
+
+// PickURL returns the next backend URL for a model, rotating across its URLs.
+func (r *ModelRegistry) PickURL(name string) (string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	e, ok := r.models[name]
+	if !ok {
+		return "", ErrUnknownModel
+	}
+	if e.Status == "disabled" || len(e.URLs) == 0 {
+		return "", ErrModelUnavailable
+	}
+	i := r.cursor[name] % len(e.URLs)
+	r.cursor[name] = i + 1
+	return e.URLs[i], nil
+}
+
+// ValidateModel checks that an entry is routable and uses known values.
+func ValidateModel(e ModelEntry) error {
+	if strings.TrimSpace(e.Name) == "" {
+		return errors.New("name is required")
+	}
+	if len(e.URLs) == 0 {
+		return errors.New("at least one url is required")
+	}
+	for _, u := range e.URLs {
+		pu, err := url.Parse(u)
+		if err != nil || (pu.Scheme != "http" && pu.Scheme != "https") || pu.Host == "" {
+			return fmt.Errorf("invalid url %q", u)
+		}
+	}
+	if e.MaxTokens < 0 {
+		return errors.New("max_tokens must not be negative")
+	}
+	for _, f := range e.Fallback {
+		if strings.TrimSpace(f) == "" || f == e.Name {
+			return fmt.Errorf("invalid fallback %q", f)
+		}
+	}
+	if e.Resilience != nil {
+		if err := e.Resilience.Validate(); err != nil {
+			return err
+		}
+	}
+	for _, s := range validModelStatuses {
+		if e.Status == s {
+			return nil
+		}
+	}
+	return fmt.Errorf("invalid status %q", e.Status)
+}
+
+func normalizeModel(e ModelEntry) ModelEntry {
+	e.Name = strings.TrimSpace(e.Name)
+	e.Status = strings.ToLower(strings.TrimSpace(e.Status))
+	if e.Status == "" {
+		e.Status = "active"
+	}
+	if e.Type == "" {
+		e.Type = "text-completion"
+	}
+	urls := make([]string, 0, len(e.URLs))
+	for _, u := range e.URLs {
+		if u = strings.TrimRight(strings.TrimSpace(expandEnv(u)), "/"); u != "" {
+			urls = append(urls, u)
+		}
+	}
+	e.URLs = urls
+	return e
+}
+
+func cloneModel(e ModelEntry) ModelEntry {
+	e.URLs = append([]string(nil), e.URLs...)
+	e.Capabilities = append([]string(nil), e.Capabilities...)
+	e.Fallback = append([]string(nil), e.Fallback...)
+	if e.Config != nil {
+		cfg := make(map[string]any, len(e.Config))
+		for k, v := range e.Config {
+			cfg[k] = v
+		}
+		e.Config = cfg
+	}
+	if e.Resilience != nil {
+		p := *e.Resilience
+		p.RetryOn = append([]int(nil), p.RetryOn...)
+		e.Resilience = &p
+	}
+	return e
+}
+
+// expandEnv expands $VAR, ${VAR} and ${VAR:-default} references.
+func expandEnv(s string) string {
+	return os.Expand(s, func(key string) string {
+		name, def, hasDef := strings.Cut(key, ":-")
+		if v := os.Getenv(name); v != "" {
+			return v
+		}
+		if hasDef {
+			return def
+		}
+		return ""
+	})
+}
+
+// defaultModels mirrors the adapters shipped with the playground.
+func defaultModels() []ModelEntry {
+	return []ModelEntry{
+		{
+			Name:         "adapter-a",
+			Type:         "text-completion",
+			Version:      "1.0.0",
+			URLs:         []string{"${ADAPTER_A_URL:-http://localhost:8081}"},
+			Capabilities: []string{"completion", "streaming"},
+			MaxTokens:    4096,
+			Status:       "active",
+		},
+		{
+			Name:         "adapter-b",
+			Type:         "text-completion",
+			Version:      "1.2.0",
+			URLs:         []string{"${ADAPTER_B_URL:-http://localhost:8082}"},
+			Capabilities: []string{"completion", "chat", "streaming"},
+			MaxTokens:    8192,
+			Status:       "active",
+		},
+	}
+}
@@ -0,0 +1,274 @@
+package adapters
+
+import (
+	"errors"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// ErrCircuitOpen is returned without calling the adapter while a model's
+// circuit breaker is open.
+var ErrCircuitOpen = errors.New("circuit breaker open")
+
+// ResiliencePolicy controls retries and the circuit breaker for one model.
+// Zero fields fall back to DefaultResilience.
+type ResiliencePolicy struct {
+	MaxAttempts       int   `json:"max_attempts,omitempty" yaml:"max_attempts,omitempty"`
+	BaseBackoffMs     int   `json:"base_backoff_ms,omitempty" yaml:"base_backoff_ms,omitempty"`
+	MaxBackoffMs      int   `json:"max_backoff_ms,omitempty" yaml:"max_backoff_ms,omitempty"`
+	RetryOn           []int `json:"retry_on,omitempty" yaml:"retry_on,omitempty"`
+	AttemptTimeoutMs  int   `json:"attempt_timeout_ms,omitempty" yaml:"attempt_timeout_ms,omitempty"`
+	DeadlineMs        int   `json:"deadline_ms,omitempty" yaml:"deadline_ms,omitempty"`
+	BreakerThreshold  int   `json:"breaker_threshold,omitempty" yaml:"breaker_threshold,omitempty"`
+	BreakerCooldownMs int   `json:"breaker_cooldown_ms,omitempty" yaml:"breaker_cooldown_ms,omitempty"`
+}
+
+// DefaultResilience applies to models without their own policy.
+var DefaultResilience = ResiliencePolicy{
+	MaxAttempts:       3,
+	BaseBackoffMs:     100,
+	MaxBackoffMs:      2000,
+	RetryOn:           []int{408, 429, 500, 502, 503, 504},
+	AttemptTimeoutMs:  2000,
+	DeadlineMs:        8000,
+	BreakerThreshold:  5,
+	BreakerCooldownMs: 30000,
+}
+
+// withDefaults fills unset fields from DefaultResilience.
+func (p ResiliencePolicy) withDefaults() ResiliencePolicy {
+	d := DefaultResilience
+	if p.MaxAttempts > 0 {
+		d.MaxAttempts = p.MaxAttempts
+	}
+	if p.BaseBackoffMs > 0 {
+		d.BaseBackoffMs = p.BaseBackoffMs
+	}
+	if p.MaxBackoffMs > 0 {
+		d.MaxBackoffMs = p.MaxBackoffMs
+	}
+	if len(p.RetryOn) > 0 {
+		d.RetryOn = append([]int(nil), p.RetryOn...)
+	}
+	if p.AttemptTimeoutMs > 0 {
+		d.AttemptTimeoutMs = p.AttemptTimeoutMs
+	}
+	if p.DeadlineMs > 0 {
+		d.DeadlineMs = p.DeadlineMs
+	}
+	if p.BreakerThreshold > 0 {
+		d.BreakerThreshold = p.BreakerThreshold
+	}
+	if p.BreakerCooldownMs > 0 {
+		d.BreakerCooldownMs = p.BreakerCooldownMs
+	}
+	return d
+}
+
+// Validate rejects negative values and status codes outside 400-599.
+func (p ResiliencePolicy) Validate() error {
+	for _, v := range []int{p.MaxAttempts, p.BaseBackoffMs, p.MaxBackoffMs, p.AttemptTimeoutMs, p.DeadlineMs, p.BreakerThreshold, p.BreakerCooldownMs} {
+		if v < 0 {
+			return errors.New("resilience values must not be negative")
+		}
+	}
+	for _, code := range p.RetryOn {
+		if code < 400 || code > 599 {
+			return errors.New("retry_on must list 4xx/5xx status codes")
+		}
+	}
+	return nil
+}
+
+// PolicyFor returns the effective policy for a model.
+func PolicyFor(model string) ResiliencePolicy {
+	if e, ok := DefaultRegistry.Get(model); ok && e.Resilience != nil {
+		return e.Resilience.withDefaults()
+	}
+	return DefaultResilience.withDefaults()
+}
+
+// Retryable reports whether an adapter status is worth another attempt.
+func (p ResiliencePolicy) Retryable(code int) bool {
+	for _, c := range p.RetryOn {
+		if c == code {
+			return true
+		}
+	}
+	return false
+}
+
+// backoff returns the exponential delay before the given retry (1-based) with
+// jitter in [d/2, d).
+func (p ResiliencePolicy) backoff(retry int) time.Duration {
+	d := time.Duration(p.BaseBackoffMs) * time.Millisecond << (retry - 1)
+	if max := time.Duration(p.MaxBackoffMs) * time.Millisecond; d > max || d <= 0 {
+		d = max
+	}
+	if d <= 1 {
+		return d
+	}
+	return d/2 + time.Duration(rand.Int63n(int64(d/2)))
+}
+
+// retryAfter parses a Retry-After header given in seconds or as an HTTP date.
+func retryAfter(h string) time.Duration {
+	if h == "" {
+		return 0
+	}
+	if secs, err := strconv.Atoi(h); err == nil && secs > 0 {
+		return time.Duration(secs) * time.Second
+	}
+	if t, err := http.ParseTime(h); err == nil {
+		return time.Until(t)
+	}
+	return 0
+}
+
+// Circuit breaker states, also the value of the state gauge.
+const (
+	BreakerClosed   = "closed"
+	BreakerHalfOpen = "half-open"
+	BreakerOpen     = "open"
+)
+
+var breakerStateValue = map[string]float64{BreakerClosed: 0, BreakerHalfOpen: 1, BreakerOpen: 2}
+
+var (
+	adapterAttempts = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "gateway_adapter_attempts_total",
+		Help: "Adapter call attempts by model and outcome (success, retry, failure).",
+	}, []string{"model", "outcome"})
+	breakerStateGauge = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "gateway_circuit_breaker_state",
+		Help: "Circuit breaker state per model: 0 closed, 1 half-open, 2 open.",
+	}, []string{"model"})
+	breakerShortCircuits = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "gateway_circuit_breaker_rejections_total",
+		Help: "Calls rejected because the model's circuit breaker was open.",
+	}, []string{"model"})
+)
+
+// BreakerStatus is a point-in-time view of a model's circuit breaker.
+type BreakerStatus struct {
+	State               string `json:"state"`
+	ConsecutiveFailures int    `json:"consecutive_failures"`
+	OpenedAt            string `json:"opened_at,omitempty"`
+	RetryAt             string `json:"retry_at,omitempty"`
+}
+
+type circuitBreaker struct {
+	state    string
+	failures int
+	openedAt time.Time
+	probing  bool // a half-open trial call is in flight
+}
+
+var (
+	breakersMu sync.Mutex
+	breakers   = map[string]*circuitBreaker{}
+)
+
+func breakerFor(model string) *circuitBreaker {
+	b, ok := breakers[model]
+	if !ok {
+		b = &circuitBreaker{state: BreakerClosed}
+		breakers[model] = b
+		breakerStateGauge.WithLabelValues(model).Set(0)
+	}
+	return b
+}
+
+// forgetBreakers drops the circuit breakers and their metrics of models that
+// left the registry.
+func forgetBreakers(models ...string) {
+	breakersMu.Lock()
+	defer breakersMu.Unlock()
+	for _, model := range models {
+		delete(breakers, model)
+		breakerStateGauge.DeleteLabelValues(model)
+		breakerShortCircuits.DeleteLabelValues(model)
+		adapterAttempts.DeletePartialMatch(prometheus.Labels{"model": model})
+	}
+}
+
+func (b *circuitBreaker) setState(model, state string) {
+	b.state = state
+	breakerStateGauge.WithLabelValues(model).Set(breakerStateValue[state])
+}
+
+// allowCall reports whether a call may go to the adapter. After the cooldown
+// an open breaker lets a single trial call through (half-open).
+func allowCall(model string, p ResiliencePolicy) bool {
+	breakersMu.Lock()
+	defer breakersMu.Unlock()
+	b := breakerFor(model)
+	switch b.state {
+	case BreakerOpen:
+		if time.Since(b.openedAt) < time.Duration(p.BreakerCooldownMs)*time.Millisecond {
+			breakerShortCircuits.WithLabelValues(model).Inc()
+			return false
+		}
+		b.setState(model, BreakerHalfOpen)
+		b.probing = true
+		return true
+	case BreakerHalfOpen:
+		if b.probing {
+			breakerShortCircuits.WithLabelValues(model).Inc()
+			return false
+		}
+		b.probing = true
+	}
+	return true
+}
+
+// Call outcomes as seen by the breaker.
+const (
+	callSucceeded = iota
+	callFailed    // upstream failure: network error or retryable status
+	callAbandoned // caller went away; says nothing about the adapter
+)
+
+// recordResult feeds a call outcome into the breaker. Only upstream failures
+// count towards opening it.
+func recordResult(model string, p ResiliencePolicy, outcome int) {
+	breakersMu.Lock()
+	defer breakersMu.Unlock()
+	b := breakerFor(model)
+	b.probing = false
+	if outcome == callAbandoned {
+		return
+	}
+	if outcome == callSucceeded {
+		b.failures = 0
+		if b.state != BreakerClosed {
+			b.setState(model, BreakerClosed)
+		}
+		return
+	}
+	b.failures++
+	if b.state == BreakerHalfOpen || b.failures >= p.BreakerThreshold {
+		b.openedAt = time.Now()
+		b.setState(model, BreakerOpen)
+	}
+}
+
+// BreakerState returns the circuit breaker status for a model.
+func BreakerState(model string) BreakerStatus {
+	p := PolicyFor(model)
+	breakersMu.Lock()
+	defer breakersMu.Unlock()
+	b := breakerFor(model)
+	st := BreakerStatus{State: b.state, ConsecutiveFailures: b.failures}
+	if b.state != BreakerClosed && !b.openedAt.IsZero() {
+		st.OpenedAt = b.openedAt.UTC().Format(time.RFC3339)
+		st.RetryAt = b.openedAt.Add(time.Duration(p.BreakerCooldownMs) * time.Millisecond).UTC().Format(time.RFC3339)
+	}
+	return st
+}
@@ -0,0 +1,63 @@
+package adapters
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// streamClient has no overall timeout: a stream lives as long as the adapter
+// keeps sending and is ended by cancelling the request context.
+var streamClient = &http.Client{}
+
+// OpenAdapterStream starts a streaming completion on the adapter's
+// /completions/stream endpoint. The caller must close the returned body;
+// cancelling ctx aborts the upstream request. Streams are not retried but do
+// go through the model's circuit breaker.
+func OpenAdapterStream(ctx context.Context, auth, baseURL, failHeader string, creq CompletionRequest) (io.ReadCloser, error) {
+	model := creq.Model
+	p := PolicyFor(model)
+	if !allowCall(model, p) {
+		return nil, ErrCircuitOpen
+	}
+	creq.Stream = true
+	body, _ := json.Marshal(creq)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, baseURL+"/completions/stream", bytes.NewReader(body))
+	if err != nil {
+		recordResult(model, p, callAbandoned)
+		return nil, err
+	}
+	req.Header.Set("content-type", "application/json")
+	req.Header.Set("accept", "text/event-stream")
+	if strings.TrimSpace(auth) != "" {
+		req.Header.Set("Authorization", auth)
+	} else {
+		req.Header.Set("Authorization", "Bearer internal-service")
+	}
+	if failHeader != "" {
+		req.Header.Set("x-test-fail", failHeader)
+	}
+	resp, err := streamClient.Do(req)
+	if err != nil {
+		if ctx.Err() != nil {
+			recordResult(model, p, callAbandoned)
+		} else {
+			recordResult(model, p, callFailed)
+		}
+		return nil, err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		resp.Body.Close()
+		if p.Retryable(resp.StatusCode) {
+			recordResult(model, p, callFailed)
+		} else {
+			recordResult(model, p, callSucceeded)
+		}
+		return nil, &AdapterStatusError{Code: resp.StatusCode, Status: resp.Status}
+	}
+	recordResult(model, p, callSucceeded)
+	return resp.Body, nil
+}
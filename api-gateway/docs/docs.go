@@ -98,8 +98,8 @@ const docTemplate = `{
                 }
             }
         },
-        "/v1/admin/system/backup": {
-            "post": {
+        "/v1/admin/cache": {
+            "get": {
                 "security": [
                     {
                         "BearerAuth": []
@@ -108,47 +108,42 @@ const docTemplate = `{
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Starts a new system backup and returns backup metadata",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Returns cache settings and the cached /v1/ai/complete responses (temperature 0 only), newest first. Expired entries are dropped.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "admin"
                 ],
-                "summary": "Create backup",
+                "summary": "Inspect completion cache",
                 "parameters": [
                     {
-                        "description": "Backup options",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/routes.AdminCreateBackupRequest"
-                        }
+                        "type": "string",
+                        "description": "Only entries for this model",
+                        "name": "model",
+                        "in": "query"
                     }
                 ],
                 "responses": {
-                    "202": {
-                        "description": "Accepted",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/routes.BackupEntry"
+                            "type": "object",
+                            "additionalProperties": true
                         }
                     },
-                    "400": {
-                        "description": "Bad Request",
+                    "403": {
+                        "description": "Forbidden",
                         "schema": {
                             "type": "object",
-                            "additionalProperties": true
+                            "additionalProperties": {
+                                "type": "string"
+                            }
                         }
                     }
                 }
-            }
-        },
-        "/v1/admin/system/backup/{backupId}": {
-            "get": {
+            },
+            "delete": {
                 "security": [
                     {
                         "BearerAuth": []
@@ -157,32 +152,32 @@ const docTemplate = `{
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Returns status for a given backup ID",
+                "description": "Removes all cached completions, or only those for a model",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "admin"
                 ],
-                "summary": "Get backup status",
+                "summary": "Purge completion cache",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Backup ID",
-                        "name": "backupId",
-                        "in": "path",
-                        "required": true
+                        "description": "Only purge entries for this model",
+                        "name": "model",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/routes.BackupEntry"
+                            "type": "object",
+                            "additionalProperties": true
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "403": {
+                        "description": "Forbidden",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -193,8 +188,8 @@ const docTemplate = `{
                 }
             }
         },
-        "/v1/admin/system/backups": {
-            "get": {
+        "/v1/admin/cache/{key}": {
+            "delete": {
                 "security": [
                     {
                         "BearerAuth": []
@@ -203,46 +198,52 @@ const docTemplate = `{
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Lists existing backups with optional filters",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "admin"
                 ],
-                "summary": "List backups",
+                "summary": "Delete a cache entry",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Backup type filter",
-                        "name": "type",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Status filter",
-                        "name": "status",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "default": "desc",
-                        "description": "Sort order (asc|desc)",
-                        "name": "order",
-                        "in": "query"
+                        "description": "Cache key",
+                        "name": "key",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/routes.BackupListResponse"
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
                         }
                     }
                 }
             }
         },
-        "/v1/admin/system/config": {
+        "/v1/admin/clock": {
             "get": {
                 "security": [
                     {
@@ -252,19 +253,20 @@ const docTemplate = `{
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Returns the current system configuration settings",
+                "description": "Current time of the clock that drives workflow triggers and scheduled broadcasts, and whether it is frozen or shifted from the wall clock",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "admin"
                 ],
-                "summary": "Get system configuration",
+                "summary": "Get scheduler clock",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/routes.AdminSystemConfig"
+                            "type": "object",
+                            "additionalProperties": true
                         }
                     }
                 }
@@ -278,7 +280,7 @@ const docTemplate = `{
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Partially update system configuration (performance, security, features)",
+                "description": "Sets the clock to ` + "`" + `now` + "`" + ` (RFC3339), moves it by ` + "`" + `advance_seconds` + "`" + `, and/or freezes or releases it with ` + "`" + `frozen` + "`" + `. Triggers and broadcasts that fall due are fired immediately. Only registered when the gateway runs with ENABLE_TEST_CLOCK=true.",
                 "consumes": [
                     "application/json"
                 ],
@@ -288,37 +290,36 @@ const docTemplate = `{
                 "tags": [
                     "admin"
                 ],
-                "summary": "Update system configuration",
-                "parameters": [
-                    {
-                        "description": "Configuration patch",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/routes.AdminConfigPatch"
-                        }
-                    }
-                ],
+                "summary": "Set scheduler clock",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/routes.AdminUpdateResponse"
+                            "type": "object",
+                            "additionalProperties": true
                         }
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
                             "type": "object",
-                            "additionalProperties": true
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
                         }
                     }
                 }
-            }
-        },
-        "/v1/admin/system/maintenance": {
-            "post": {
+            },
+            "delete": {
                 "security": [
                     {
                         "BearerAuth": []
@@ -327,28 +328,14 @@ const docTemplate = `{
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Enable or disable system maintenance with validation",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Only registered when the gateway runs with ENABLE_TEST_CLOCK=true.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "admin"
                 ],
-                "summary": "Set maintenance mode",
-                "parameters": [
-                    {
-                        "description": "Maintenance settings",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/routes.AdminMaintenanceRequest"
-                        }
-                    }
-                ],
+                "summary": "Reset scheduler clock",
                 "responses": {
                     "200": {
                         "description": "OK",
@@ -357,17 +344,19 @@ const docTemplate = `{
                             "additionalProperties": true
                         }
                     },
-                    "400": {
-                        "description": "Bad Request",
+                    "403": {
+                        "description": "Forbidden",
                         "schema": {
                             "type": "object",
-                            "additionalProperties": true
+                            "additionalProperties": {
+                                "type": "string"
+                            }
                         }
                     }
                 }
             }
         },
-        "/v1/admin/system/status": {
+        "/v1/admin/models": {
             "get": {
                 "security": [
                     {
@@ -377,25 +366,26 @@ const docTemplate = `{
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Returns overall system health, services, resources, and versions",
+                "description": "Returns every registered model with backend URLs, capabilities and status",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "admin"
                 ],
-                "summary": "Get system status",
+                "summary": "List model registry",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/routes.AdminSystemStatusResponse"
+                            "type": "object",
+                            "additionalProperties": true
                         }
                     }
                 }
             }
         },
-        "/v1/ai/batch": {
+        "/v1/admin/models/reload": {
             "post": {
                 "security": [
                     {
@@ -405,47 +395,54 @@ const docTemplate = `{
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Accepts a batch completion request and returns a job id",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Re-reads the registry file; fails with 409 when the registry was not loaded from a file",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "ai"
-                ],
-                "summary": "Batch AI completion",
-                "parameters": [
-                    {
-                        "description": "Batch request",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/routes.AiBatchRequest"
-                        }
-                    }
+                    "admin"
                 ],
+                "summary": "Reload model registry",
                 "responses": {
-                    "202": {
-                        "description": "Accepted",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/routes.AiBatchAccepted"
+                            "type": "object",
+                            "additionalProperties": true
                         }
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
                             "type": "object",
-                            "additionalProperties": true
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
                         }
                     }
                 }
             }
         },
-        "/v1/ai/complete": {
-            "post": {
+        "/v1/admin/models/{model}": {
+            "put": {
                 "security": [
                     {
                         "BearerAuth": []
@@ -454,7 +451,7 @@ const docTemplate = `{
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Generate text completion using specified AI model",
+                "description": "Creates or replaces a model entry; the path name wins over any name in the body",
                 "consumes": [
                     "application/json"
                 ],
@@ -462,25 +459,24 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "ai"
+                    "admin"
                 ],
-                "summary": "AI text completion",
+                "summary": "Register model",
                 "parameters": [
                     {
-                        "description": "Completion request",
+                        "type": "string",
+                        "description": "Model name",
+                        "name": "model",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Model entry",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "type": "object",
-                            "properties": {
-                                "model": {
-                                    "type": "string"
-                                },
-                                "prompt": {
-                                    "type": "string"
-                                }
-                            }
+                            "$ref": "#/definitions/adapters.ModelEntry"
                         }
                     }
                 ],
@@ -488,34 +484,17 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "object",
-                            "properties": {
-                                "completion": {
-                                    "type": "string"
-                                },
-                                "model": {
-                                    "type": "string"
-                                },
-                                "traceId": {
-                                    "type": "string"
-                                },
-                                "usage": {
-                                    "type": "object"
-                                }
-                            }
+                            "$ref": "#/definitions/adapters.ModelEntry"
                         }
                     },
-                    "400": {
-                        "description": "Bad Request",
+                    "201": {
+                        "description": "Created",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/adapters.ModelEntry"
                         }
                     },
-                    "401": {
-                        "description": "Unauthorized",
+                    "400": {
+                        "description": "Bad Request",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -523,8 +502,8 @@ const docTemplate = `{
                             }
                         }
                     },
-                    "502": {
-                        "description": "Bad Gateway",
+                    "403": {
+                        "description": "Forbidden",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -533,10 +512,8 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/v1/ai/jobs/{jobId}": {
-            "get": {
+            },
+            "delete": {
                 "security": [
                     {
                         "BearerAuth": []
@@ -545,90 +522,45 @@ const docTemplate = `{
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Returns the status and results for a batch completion job",
-                "produces": [
-                    "application/json"
-                ],
                 "tags": [
-                    "ai"
+                    "admin"
                 ],
-                "summary": "AI job status",
+                "summary": "Unregister model",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Job ID",
-                        "name": "jobId",
+                        "description": "Model name",
+                        "name": "model",
                         "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "403": {
+                        "description": "Forbidden",
                         "schema": {
-                            "$ref": "#/definitions/routes.AiJobStatusResponse"
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
                         }
-                    }
-                }
-            }
-        },
-        "/v1/ai/metrics": {
-            "get": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    },
-                    {
-                        "ApiKeyAuth": []
-                    }
-                ],
-                "description": "Returns overall metrics for AI models",
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "ai"
-                ],
-                "summary": "AI metrics",
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/routes.AiMetricsResponse"
-                        }
-                    }
-                }
-            }
-        },
-        "/v1/ai/models": {
-            "get": {
-                "security": [
-                    {
-                        "BearerAuth": []
                     },
-                    {
-                        "ApiKeyAuth": []
-                    }
-                ],
-                "description": "Returns available AI models and capabilities",
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "ai"
-                ],
-                "summary": "List AI models",
-                "responses": {
-                    "200": {
-                        "description": "OK",
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
-                            "$ref": "#/definitions/routes.AiModelsResponse"
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
                         }
                     }
                 }
             }
         },
-        "/v1/ai/models/{model}/configure": {
+        "/v1/admin/system/backup": {
             "post": {
                 "security": [
                     {
@@ -638,7 +570,7 @@ const docTemplate = `{
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Updates configuration settings for a specific AI model",
+                "description": "Starts a new system backup and returns backup metadata",
                 "consumes": [
                     "application/json"
                 ],
@@ -646,33 +578,25 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "ai"
+                    "admin"
                 ],
-                "summary": "Configure AI model",
+                "summary": "Create backup",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Model name",
-                        "name": "model",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "Configuration settings",
+                        "description": "Backup options",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/routes.AdminCreateBackupRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "202": {
+                        "description": "Accepted",
                         "schema": {
-                            "$ref": "#/definitions/routes.AiGenericMessageResponse"
+                            "$ref": "#/definitions/routes.BackupEntry"
                         }
                     },
                     "400": {
@@ -682,17 +606,19 @@ const docTemplate = `{
                             "additionalProperties": true
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "403": {
+                        "description": "Forbidden",
                         "schema": {
                             "type": "object",
-                            "additionalProperties": true
+                            "additionalProperties": {
+                                "type": "string"
+                            }
                         }
                     }
                 }
             }
         },
-        "/v1/ai/models/{model}/status": {
+        "/v1/admin/system/backup/{backupId}": {
             "get": {
                 "security": [
                     {
@@ -702,19 +628,19 @@ const docTemplate = `{
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Returns health and status for a specific AI model",
+                "description": "Returns status for a given backup ID",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "ai"
+                    "admin"
                 ],
-                "summary": "Get AI model status",
+                "summary": "Get backup status",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Model name",
-                        "name": "model",
+                        "description": "Backup ID",
+                        "name": "backupId",
                         "in": "path",
                         "required": true
                     }
@@ -723,20 +649,22 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/routes.AiModelStatusResponse"
+                            "$ref": "#/definitions/routes.BackupEntry"
                         }
                     },
                     "404": {
                         "description": "Not Found",
                         "schema": {
                             "type": "object",
-                            "additionalProperties": true
+                            "additionalProperties": {
+                                "type": "string"
+                            }
                         }
                     }
                 }
             }
         },
-        "/v1/analytics/errors": {
+        "/v1/admin/system/backups": {
             "get": {
                 "security": [
                     {
@@ -746,43 +674,32 @@ const docTemplate = `{
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Returns aggregated error stats, supports filters and trends",
+                "description": "Lists existing backups with optional filters",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "analytics"
+                    "admin"
                 ],
-                "summary": "Get error analytics",
+                "summary": "List backups",
                 "parameters": [
-                    {
-                        "type": "integer",
-                        "description": "Filter by status code",
-                        "name": "status_code",
-                        "in": "query"
-                    },
                     {
                         "type": "string",
-                        "description": "Endpoint filter",
-                        "name": "endpoint",
-                        "in": "query"
-                    },
-                    {
-                        "type": "boolean",
-                        "description": "Include trend series",
-                        "name": "include_trends",
+                        "description": "Backup type filter",
+                        "name": "type",
                         "in": "query"
                     },
                     {
                         "type": "string",
-                        "description": "Grouping: hour|day|week",
-                        "name": "group_by",
+                        "description": "Status filter",
+                        "name": "status",
                         "in": "query"
                     },
                     {
-                        "type": "integer",
-                        "description": "Limit recent errors",
-                        "name": "limit",
+                        "type": "string",
+                        "default": "desc",
+                        "description": "Sort order (asc|desc)",
+                        "name": "order",
                         "in": "query"
                     }
                 ],
@@ -790,24 +707,40 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/routes.BackupListResponse"
                         }
+                    }
+                }
+            }
+        },
+        "/v1/admin/system/config": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
                     },
-                    "400": {
-                        "description": "Bad Request",
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Returns the current system configuration settings",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Get system configuration",
+                "responses": {
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/routes.AdminSystemConfig"
                         }
                     }
                 }
-            }
-        },
-        "/v1/analytics/events/batch": {
-            "post": {
+            },
+            "put": {
                 "security": [
                     {
                         "BearerAuth": []
@@ -816,7 +749,7 @@ const docTemplate = `{
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Accepts multiple analytics events in a single request",
+                "description": "Partially update system configuration (performance, security, quotas, workflows, features). The patch is applied only if every value is valid.",
                 "consumes": [
                     "application/json"
                 ],
@@ -824,19 +757,36 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "analytics"
+                    "admin"
+                ],
+                "summary": "Update system configuration",
+                "parameters": [
+                    {
+                        "description": "Configuration patch",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/routes.AdminConfigPatch"
+                        }
+                    }
                 ],
-                "summary": "Track batch analytics events",
                 "responses": {
-                    "202": {
-                        "description": "Accepted",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/routes.AdminUpdateResponse"
                         }
                     },
                     "400": {
                         "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -847,8 +797,8 @@ const docTemplate = `{
                 }
             }
         },
-        "/v1/analytics/performance": {
-            "get": {
+        "/v1/admin/system/maintenance": {
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
@@ -857,32 +807,26 @@ const docTemplate = `{
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Returns response time distribution, throughput, and error rates",
+                "description": "Enable or disable system maintenance with validation",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "analytics"
+                    "admin"
                 ],
-                "summary": "Get performance metrics",
+                "summary": "Set maintenance mode",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Endpoint filter",
-                        "name": "endpoint",
-                        "in": "query"
-                    },
-                    {
-                        "type": "boolean",
-                        "description": "Include trend series",
-                        "name": "include_trends",
-                        "in": "query"
-                    },
-                    {
-                        "type": "boolean",
-                        "description": "Check thresholds",
-                        "name": "check_thresholds",
-                        "in": "query"
+                        "description": "Maintenance settings",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/routes.AdminMaintenanceRequest"
+                        }
                     }
                 ],
                 "responses": {
@@ -895,6 +839,13 @@ const docTemplate = `{
                     },
                     "400": {
                         "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -905,7 +856,7 @@ const docTemplate = `{
                 }
             }
         },
-        "/v1/analytics/usage": {
+        "/v1/admin/system/status": {
             "get": {
                 "security": [
                     {
@@ -915,15 +866,43 @@ const docTemplate = `{
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Returns usage analytics with optional date range, endpoint filter, and grouping",
+                "description": "Returns overall system health, services, resources, and versions",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "analytics"
+                    "admin"
                 ],
-                "summary": "Get usage analytics",
-                "parameters": [
+                "summary": "Get system status",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/routes.AdminSystemStatusResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/admin/usage": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Admin view (service API key or admin user) of the usage ledger: token usage per subject (\"user:\u003cid\u003e\" or \"api_key:\u003csha256 prefix\u003e\") and model, with each subject's quota.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Get token usage for all callers",
+                "parameters": [
                     {
                         "type": "string",
                         "description": "RFC3339 start date",
@@ -937,28 +916,2529 @@ const docTemplate = `{
                         "in": "query"
                     },
                     {
-                        "type": "string",
-                        "description": "Endpoint filter",
-                        "name": "endpoint",
-                        "in": "query"
+                        "type": "string",
+                        "description": "Only this subject",
+                        "name": "subject",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only this model",
+                        "name": "model",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/ai/batch": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Queues each entry of requests (or inputs, a list of prompts) for completion and returns a job id",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "ai"
+                ],
+                "summary": "Batch AI completion",
+                "parameters": [
+                    {
+                        "description": "Batch request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/routes.AiBatchRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "$ref": "#/definitions/routes.AiBatchAccepted"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/ai/complete": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Generate text completion using specified AI model",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "ai"
+                ],
+                "summary": "AI text completion",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "no-cache skips the cache lookup, no-store also skips storing the result",
+                        "name": "Cache-Control",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Completion request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/routes.AiCompleteRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/routes.AiCompleteResponse"
+                        },
+                        "headers": {
+                            "x-cache": {
+                                "type": "string",
+                                "description": "HIT or MISS for temperature-0 requests, BYPASS when the cache was not consulted (x-test-fail requests never are)"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "429": {
+                        "description": "Token quota exhausted",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "502": {
+                        "description": "Bad Gateway",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/ai/complete/stream": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Relays adapter chunks as text/event-stream. Each chunk is a data event; the stream ends with a usage event and data: [DONE]. Equivalent to POST /v1/ai/complete with stream=true.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "text/event-stream"
+                ],
+                "tags": [
+                    "ai"
+                ],
+                "summary": "Streaming AI text completion",
+                "parameters": [
+                    {
+                        "description": "Completion request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/routes.AiCompleteRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Server-sent events stream",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "502": {
+                        "description": "Bad Gateway",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/ai/jobs/{jobId}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Returns the status, progress and per-item results for a batch completion job; jobs of other callers answer 404",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "ai"
+                ],
+                "summary": "AI job status",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Job ID",
+                        "name": "jobId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/routes.AiJobStatusResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Cancels pending items of a batch job; finished items keep their results",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "ai"
+                ],
+                "summary": "Cancel AI job",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Job ID",
+                        "name": "jobId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/routes.AiJobStatusResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/ai/jobs/{jobId}/deliveries": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Returns every callback delivery attempt for a batch job",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "ai"
+                ],
+                "summary": "AI job webhook deliveries",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Job ID",
+                        "name": "jobId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/ai/jobs/{jobId}/redeliver": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Sends the stored completion payload to the job's callback_url again as a new delivery; 409 while a delivery is still in progress",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "ai"
+                ],
+                "summary": "Redeliver AI job webhook",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Job ID",
+                        "name": "jobId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/ai/metrics": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Returns overall metrics for AI models",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "ai"
+                ],
+                "summary": "AI metrics",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/routes.AiMetricsResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/ai/models": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Returns available AI models and capabilities",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "ai"
+                ],
+                "summary": "List AI models",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/routes.AiModelsResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/ai/models/{model}/configure": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Updates configuration settings for a specific AI model. Registry fields (status, max_tokens, urls, capabilities, version) are applied to the model registry; other keys are stored as model config.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "ai"
+                ],
+                "summary": "Configure AI model",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Model name",
+                        "name": "model",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Configuration settings",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/routes.AiGenericMessageResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/ai/models/{model}/status": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Returns health and status for a specific AI model, including its circuit breaker state and effective retry policy",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "ai"
+                ],
+                "summary": "Get AI model status",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Model name",
+                        "name": "model",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/routes.AiModelStatusResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/ai/usage": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Returns the calling user's (or API key's) token usage per model from the usage ledger, with the current daily/monthly quota. A quota of 0 means unlimited.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "ai"
+                ],
+                "summary": "Get own token usage",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "RFC3339 start date",
+                        "name": "start_date",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "RFC3339 end date",
+                        "name": "end_date",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/analytics/errors": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Returns aggregated error stats, supports filters and trends",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "analytics"
+                ],
+                "summary": "Get error analytics",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Filter by status code",
+                        "name": "status_code",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Endpoint filter",
+                        "name": "endpoint",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Include trend series",
+                        "name": "include_trends",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Grouping: hour|day|week",
+                        "name": "group_by",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Limit recent errors",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/analytics/events/batch": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Accepts multiple analytics events in a single request",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "analytics"
+                ],
+                "summary": "Track batch analytics events",
+                "responses": {
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/analytics/performance": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Returns response time distribution, throughput, and error rates",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "analytics"
+                ],
+                "summary": "Get performance metrics",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Endpoint filter",
+                        "name": "endpoint",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Include trend series",
+                        "name": "include_trends",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Check thresholds",
+                        "name": "check_thresholds",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/analytics/usage": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Returns request counts from the gateway request log and token usage from the usage ledger, with optional date range, endpoint prefix filter, and grouping",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "analytics"
+                ],
+                "summary": "Get usage analytics",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "RFC3339 start date",
+                        "name": "start_date",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "RFC3339 end date",
+                        "name": "end_date",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Endpoint filter",
+                        "name": "endpoint",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Grouping: hour|day|week",
+                        "name": "group_by",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/audit/logs": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "audit"
+                ],
+                "summary": "List audit logs",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Filter by user ID",
+                        "name": "user_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by action",
+                        "name": "action",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by resource type",
+                        "name": "resource_type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "RFC3339 start",
+                        "name": "start_date",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "RFC3339 end",
+                        "name": "end_date",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Sort field (timestamp)",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "asc|desc",
+                        "name": "order",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Items per page",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "audit"
+                ],
+                "summary": "Create audit log",
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/routes.AuditLog"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/audit/logs/{logId}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "audit"
+                ],
+                "summary": "Get audit log by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Audit Log ID",
+                        "name": "logId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/routes.AuditLog"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/chat/completions": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Accepts the OpenAI chat.completions request format and routes by model. Models with the chat capability are called natively; others are translated to /complete. With stream=true the response is a stream of chat.completion.chunk events ending with data: [DONE].",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "ai"
+                ],
+                "summary": "OpenAI-compatible chat completion",
+                "parameters": [
+                    {
+                        "description": "Chat completion request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/routes.ChatCompletionRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/adapters.ChatResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "502": {
+                        "description": "Bad Gateway",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/models": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "ai"
+                ],
+                "summary": "OpenAI-compatible model list",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/notifications": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Returns the tenant's notifications the caller sent or received (all of them for the service API key and admins) with filters, pagination, and sorting",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "notifications"
+                ],
+                "summary": "List notifications",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Filter by recipient",
+                        "name": "recipient",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by status",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by type",
+                        "name": "type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by priority",
+                        "name": "priority",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Search text",
+                        "name": "search",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "RFC3339 start date",
+                        "name": "start_date",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "RFC3339 end date",
+                        "name": "end_date",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Items per page",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Sort field",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "asc|desc",
+                        "name": "order",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "The recipient is an email or a users-core user ID; the caller is recorded as sender",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "notifications"
+                ],
+                "summary": "Create notification",
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/routes.Notification"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/notifications/broadcasts": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Broadcasts of the tenant the caller sent (all of them for the service API key and admins), newest first, with recipient, delivered and read counts",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "notifications"
+                ],
+                "summary": "List notification broadcasts",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "scheduled|sent|cancelled",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Items per page",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/notifications/broadcasts/{broadcastId}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Status, schedule and delivery counts of a broadcast",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "notifications"
+                ],
+                "summary": "Get notification broadcast",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Broadcast ID",
+                        "name": "broadcastId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/notifications/broadcasts/{broadcastId}/cancel": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Cancels a broadcast that has not been delivered yet",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "notifications"
+                ],
+                "summary": "Cancel notification broadcast",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Broadcast ID",
+                        "name": "broadcastId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/notifications/broadcasts/{broadcastId}/reschedule": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Moves a broadcast that has not been delivered yet to schedule_for (RFC3339)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "notifications"
+                ],
+                "summary": "Reschedule notification broadcast",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Broadcast ID",
+                        "name": "broadcastId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/notifications/me": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Notifications addressed to the calling user (by users-core user ID or email) in the tenant, newest first, with the unread count. Needs a user token.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "notifications"
+                ],
+                "summary": "My notifications",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Filter by status (read|unread)",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Items per page",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/notifications/stream": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Server-Sent Events of the caller's tenant: created, updated, read, unread and deleted events (data: {id, type, notification}) for notifications addressed to the caller; the service API key and admins get all of them. The stream opens with a ready event carrying the latest event id. Reconnect with Last-Event-ID (or last_event_id) to receive the events missed since; a resync event means some are no longer kept and the list should be reloaded. Idle streams get a heartbeat comment every heartbeat seconds (default 15).",
+                "produces": [
+                    "text/event-stream"
+                ],
+                "tags": [
+                    "notifications"
+                ],
+                "summary": "Notification event stream",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Resume after this event id",
+                        "name": "Last-Event-ID",
+                        "in": "header"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Resume after this event id",
+                        "name": "last_event_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Heartbeat interval in seconds (1-300)",
+                        "name": "heartbeat",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "event stream",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/notifications/ws": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "The events of /v1/notifications/stream over a WebSocket: each text message is a JSON object with a type (ready, resync, heartbeat, or the event type with its id and notification). Resume with the Last-Event-ID header or last_event_id. Messages from the client are ignored.",
+                "tags": [
+                    "notifications"
+                ],
+                "summary": "Notification WebSocket",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Resume after this event id",
+                        "name": "Last-Event-ID",
+                        "in": "header"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Resume after this event id",
+                        "name": "last_event_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Heartbeat interval in seconds (1-300)",
+                        "name": "heartbeat",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "101": {
+                        "description": "switching protocols",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/notifications/{notificationId}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "notifications"
+                ],
+                "summary": "Get notification by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Notification ID",
+                        "name": "notificationId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/routes.Notification"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Only the sender (or the service API key / an admin) can change a notification",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "notifications"
+                ],
+                "summary": "Update notification",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Notification ID",
+                        "name": "notificationId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/routes.Notification"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/notifications/{notificationId}/read": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Only the recipient (or the service API key / an admin) can change the read status",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "notifications"
+                ],
+                "summary": "Mark notification as read",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Notification ID",
+                        "name": "notificationId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/routes.Notification"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/notifications/{notificationId}/unread": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Clears read status for a notification; only the recipient (or the service API key / an admin) can change it",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "notifications"
+                ],
+                "summary": "Mark notification as unread",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Notification ID",
+                        "name": "notificationId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/routes.Notification"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/workflows": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "workflows"
+                ],
+                "summary": "List workflows",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Filter by name contains",
+                        "name": "name",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by status",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Include archived workflows (hidden unless status=archived)",
+                        "name": "include_archived",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Sort field: created_at|name",
+                        "name": "sort_by",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "asc|desc",
+                        "name": "order",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Items per page",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "workflows"
+                ],
+                "summary": "Create workflow",
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/routes.Workflow"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/workflows/import": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Validates a JSON or YAML definition document against the published schema (GET /v1/workflows/schema) and the step reference rules, reporting every error with its JSON pointer. Valid documents are saved as a new draft workflow unless dry_run is set.",
+                "consumes": [
+                    "application/json",
+                    "application/yaml"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "workflows"
+                ],
+                "summary": "Import workflow",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "json or yaml (default: from Content-Type)",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Validate only",
+                        "name": "dry_run",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/routes.Workflow"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/workflows/purge": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Deletes every workflow matching all given selectors (ids, name contains, status), or every workflow with all=true, like DELETE /v1/workflows/{id}. Workflows with running executions are skipped unless force is set.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "workflows"
+                ],
+                "summary": "Purge workflows",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/workflows/schema": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "JSON Schema of the documents accepted by /v1/workflows/import and produced by /export",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "workflows"
+                ],
+                "summary": "Workflow definition JSON Schema",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/workflows/{workflowId}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "workflows"
+                ],
+                "summary": "Get workflow by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Workflow ID",
+                        "name": "workflowId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/routes.Workflow"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Every update creates a new version (see /versions); running executions keep the version they started on. Edits to an active workflow take effect once published.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "workflows"
+                ],
+                "summary": "Update workflow",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Workflow ID",
+                        "name": "workflowId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/routes.Workflow"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Deletes the workflow with its versions and triggers. Its executions are deleted too, or kept readable for workflows.execution_retention seconds (system config). A workflow with running executions is refused with 409 unless force=true, which cancels them.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "workflows"
+                ],
+                "summary": "Delete workflow",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Workflow ID",
+                        "name": "workflowId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Cancel running executions",
+                        "name": "force",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/workflows/{workflowId}/approve": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Records an approval for the waiting step given by step_id, or the first step waiting for a decision, of the execution given by execution_id (default: the latest) (approval steps, and manual/task/automated steps that are signed off this way). When the step lists approvers only they may decide; the step resumes once its quorum (requires_all: every approver) of distinct approvals is reached.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "workflows"
+                ],
+                "summary": "Approve workflow step",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Workflow ID",
+                        "name": "workflowId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/workflows/{workflowId}/archive": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Archived workflows are hidden from GET /v1/workflows (unless status=archived or include_archived=true), cannot be executed or changed, and their triggers do not fire. Running executions continue.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "workflows"
+                ],
+                "summary": "Archive workflow",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Workflow ID",
+                        "name": "workflowId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/routes.Workflow"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/workflows/{workflowId}/execute": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
                     },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Starts running the step DAG in the background: steps start once all their depends_on steps completed, independent steps run in parallel. Executions of the same workflow run independently; see /executions to list or cancel them.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "workflows"
+                ],
+                "summary": "Execute workflow",
+                "parameters": [
                     {
                         "type": "string",
-                        "description": "Grouping: hour|day|week",
-                        "name": "group_by",
-                        "in": "query"
+                        "description": "Workflow ID",
+                        "name": "workflowId",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "202": {
+                        "description": "Accepted",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
                         }
                     },
-                    "400": {
-                        "description": "Bad Request",
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -969,7 +3449,7 @@ const docTemplate = `{
                 }
             }
         },
-        "/v1/audit/logs": {
+        "/v1/workflows/{workflowId}/executions": {
             "get": {
                 "security": [
                     {
@@ -979,54 +3459,26 @@ const docTemplate = `{
                         "ApiKeyAuth": []
                     }
                 ],
+                "description": "Executions of the workflow, newest first",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "audit"
+                    "workflows"
                 ],
-                "summary": "List audit logs",
+                "summary": "List workflow executions",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Filter by user ID",
-                        "name": "user_id",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Filter by action",
-                        "name": "action",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Filter by resource type",
-                        "name": "resource_type",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "RFC3339 start",
-                        "name": "start_date",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "RFC3339 end",
-                        "name": "end_date",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Sort field (timestamp)",
-                        "name": "sort",
-                        "in": "query"
+                        "description": "Workflow ID",
+                        "name": "workflowId",
+                        "in": "path",
+                        "required": true
                     },
                     {
                         "type": "string",
-                        "description": "asc|desc",
-                        "name": "order",
+                        "description": "Filter by status",
+                        "name": "status",
                         "in": "query"
                     },
                     {
@@ -1050,8 +3502,8 @@ const docTemplate = `{
                             "additionalProperties": true
                         }
                     },
-                    "400": {
-                        "description": "Bad Request",
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -1060,8 +3512,10 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "post": {
+            }
+        },
+        "/v1/workflows/{workflowId}/executions/{execId}": {
+            "get": {
                 "security": [
                     {
                         "BearerAuth": []
@@ -1070,25 +3524,40 @@ const docTemplate = `{
                         "ApiKeyAuth": []
                     }
                 ],
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Full state of one execution: per-step state and outputs, decisions and history",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "audit"
+                    "workflows"
+                ],
+                "summary": "Get workflow execution",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Workflow ID",
+                        "name": "workflowId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Execution ID",
+                        "name": "execId",
+                        "in": "path",
+                        "required": true
+                    }
                 ],
-                "summary": "Create audit log",
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/routes.AuditLog"
+                            "type": "object",
+                            "additionalProperties": true
                         }
                     },
-                    "400": {
-                        "description": "Bad Request",
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -1099,8 +3568,8 @@ const docTemplate = `{
                 }
             }
         },
-        "/v1/audit/logs/{logId}": {
-            "get": {
+        "/v1/workflows/{workflowId}/executions/{execId}/cancel": {
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
@@ -1109,18 +3578,26 @@ const docTemplate = `{
                         "ApiKeyAuth": []
                     }
                 ],
+                "description": "Cancels a running execution: running and waiting steps are stopped and the execution ends as cancelled.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "audit"
+                    "workflows"
                 ],
-                "summary": "Get audit log by ID",
+                "summary": "Cancel workflow execution",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Audit Log ID",
-                        "name": "logId",
+                        "description": "Workflow ID",
+                        "name": "workflowId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Execution ID",
+                        "name": "execId",
                         "in": "path",
                         "required": true
                     }
@@ -1129,7 +3606,8 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/routes.AuditLog"
+                            "type": "object",
+                            "additionalProperties": true
                         }
                     },
                     "404": {
@@ -1140,12 +3618,21 @@ const docTemplate = `{
                                 "type": "string"
                             }
                         }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
                     }
                 }
             }
         },
-        "/v1/notifications": {
-            "get": {
+        "/v1/workflows/{workflowId}/executions/{execId}/retry": {
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
@@ -1154,79 +3641,99 @@ const docTemplate = `{
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Returns notifications with filters, pagination, and sorting",
+                "description": "Starts a new execution with the workflow version, parameters and context of a failed, timed out or cancelled one. The new execution records retry_of.",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "notifications"
+                    "workflows"
                 ],
-                "summary": "List notifications",
+                "summary": "Retry workflow execution",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Filter by recipient",
-                        "name": "recipient",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Filter by status",
-                        "name": "status",
-                        "in": "query"
+                        "description": "Workflow ID",
+                        "name": "workflowId",
+                        "in": "path",
+                        "required": true
                     },
                     {
                         "type": "string",
-                        "description": "Filter by type",
-                        "name": "type",
-                        "in": "query"
+                        "description": "Execution ID",
+                        "name": "execId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
                     },
-                    {
-                        "type": "string",
-                        "description": "Filter by priority",
-                        "name": "priority",
-                        "in": "query"
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
                     },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/workflows/{workflowId}/export": {
+            "get": {
+                "security": [
                     {
-                        "type": "string",
-                        "description": "Search text",
-                        "name": "search",
-                        "in": "query"
+                        "BearerAuth": []
                     },
                     {
-                        "type": "string",
-                        "description": "RFC3339 start date",
-                        "name": "start_date",
-                        "in": "query"
-                    },
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "The workflow definition (current version, or ?version=n) as a versioned document that /v1/workflows/import accepts",
+                "produces": [
+                    "application/json",
+                    "application/yaml"
+                ],
+                "tags": [
+                    "workflows"
+                ],
+                "summary": "Export workflow",
+                "parameters": [
                     {
                         "type": "string",
-                        "description": "RFC3339 end date",
-                        "name": "end_date",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Page number",
-                        "name": "page",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Items per page",
-                        "name": "limit",
-                        "in": "query"
+                        "description": "Workflow ID",
+                        "name": "workflowId",
+                        "in": "path",
+                        "required": true
                     },
                     {
                         "type": "string",
-                        "description": "Sort field",
-                        "name": "sort",
+                        "description": "json (default) or yaml",
+                        "name": "format",
                         "in": "query"
                     },
                     {
-                        "type": "string",
-                        "description": "asc|desc",
-                        "name": "order",
+                        "type": "integer",
+                        "description": "Version to export",
+                        "name": "version",
                         "in": "query"
                     }
                 ],
@@ -1237,10 +3744,30 @@ const docTemplate = `{
                             "type": "object",
                             "additionalProperties": true
                         }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
                     }
                 }
-            },
-            "post": {
+            }
+        },
+        "/v1/workflows/{workflowId}/graph": {
+            "get": {
                 "security": [
                     {
                         "BearerAuth": []
@@ -1249,21 +3776,43 @@ const docTemplate = `{
                         "ApiKeyAuth": []
                     }
                 ],
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "The workflow's steps and their depends_on edges (on_reject/on_failure branches dashed) as JSON, Graphviz DOT or a Mermaid flowchart. With execution_id (or \"latest\") the graph shows the version that execution runs, with nodes colored by step status.",
                 "produces": [
-                    "application/json"
+                    "application/json",
+                    "text/vnd.graphviz",
+                    "text/plain"
                 ],
                 "tags": [
-                    "notifications"
+                    "workflows"
+                ],
+                "summary": "Workflow graph",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Workflow ID",
+                        "name": "workflowId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "json (default), dot or mermaid",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Execution ID, or latest",
+                        "name": "execution_id",
+                        "in": "query"
+                    }
                 ],
-                "summary": "Create notification",
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/routes.Notification"
+                            "type": "object",
+                            "additionalProperties": true
                         }
                     },
                     "400": {
@@ -1274,12 +3823,21 @@ const docTemplate = `{
                                 "type": "string"
                             }
                         }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
                     }
                 }
             }
         },
-        "/v1/notifications/{notificationId}": {
-            "get": {
+        "/v1/workflows/{workflowId}/publish": {
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
@@ -1288,18 +3846,19 @@ const docTemplate = `{
                         "ApiKeyAuth": []
                     }
                 ],
+                "description": "Publishes the current version: a draft workflow becomes active, an active one promotes its edits made since the last publish. New executions of an active workflow run the published version.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "notifications"
+                    "workflows"
                 ],
-                "summary": "Get notification by ID",
+                "summary": "Publish workflow",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Notification ID",
-                        "name": "notificationId",
+                        "description": "Workflow ID",
+                        "name": "workflowId",
                         "in": "path",
                         "required": true
                     }
@@ -1308,7 +3867,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/routes.Notification"
+                            "$ref": "#/definitions/routes.Workflow"
                         }
                     },
                     "404": {
@@ -1319,10 +3878,21 @@ const docTemplate = `{
                                 "type": "string"
                             }
                         }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
                     }
                 }
-            },
-            "put": {
+            }
+        },
+        "/v1/workflows/{workflowId}/reject": {
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
@@ -1331,6 +3901,7 @@ const docTemplate = `{
                         "ApiKeyAuth": []
                     }
                 ],
+                "description": "Rejects the waiting step given by step_id, or the first step waiting for a decision, of the execution given by execution_id (default: the latest). A single rejection decides the step. With on_reject the execution continues with that correction branch, otherwise it fails.",
                 "consumes": [
                     "application/json"
                 ],
@@ -1338,14 +3909,14 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "notifications"
+                    "workflows"
                 ],
-                "summary": "Update notification",
+                "summary": "Reject workflow step",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Notification ID",
-                        "name": "notificationId",
+                        "description": "Workflow ID",
+                        "name": "workflowId",
                         "in": "path",
                         "required": true
                     }
@@ -1354,7 +3925,8 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/routes.Notification"
+                            "type": "object",
+                            "additionalProperties": true
                         }
                     },
                     "400": {
@@ -1366,6 +3938,15 @@ const docTemplate = `{
                             }
                         }
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
                     "404": {
                         "description": "Not Found",
                         "schema": {
@@ -1374,12 +3955,21 @@ const docTemplate = `{
                                 "type": "string"
                             }
                         }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
                     }
                 }
             }
         },
-        "/v1/notifications/{notificationId}/read": {
-            "put": {
+        "/v1/workflows/{workflowId}/rollback": {
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
@@ -1388,6 +3978,7 @@ const docTemplate = `{
                         "ApiKeyAuth": []
                     }
                 ],
+                "description": "Creates a new version with the definition of an earlier one. Running executions keep the version they started on.",
                 "consumes": [
                     "application/json"
                 ],
@@ -1395,14 +3986,14 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "notifications"
+                    "workflows"
                 ],
-                "summary": "Mark notification as read",
+                "summary": "Roll back workflow",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Notification ID",
-                        "name": "notificationId",
+                        "description": "Workflow ID",
+                        "name": "workflowId",
                         "in": "path",
                         "required": true
                     }
@@ -1411,7 +4002,16 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/routes.Notification"
+                            "$ref": "#/definitions/routes.Workflow"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
                         }
                     },
                     "404": {
@@ -1422,12 +4022,21 @@ const docTemplate = `{
                                 "type": "string"
                             }
                         }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
                     }
                 }
             }
         },
-        "/v1/notifications/{notificationId}/unread": {
-            "put": {
+        "/v1/workflows/{workflowId}/status": {
+            "get": {
                 "security": [
                     {
                         "BearerAuth": []
@@ -1436,22 +4045,19 @@ const docTemplate = `{
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Clears read status for a notification",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Status of the workflow's latest execution (see /executions for all of them): overall status (started|in_progress|waiting_approval|completed|failed|timeout|cancelled), progress as the share of completed steps, per-step state and the execution history.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "notifications"
+                    "workflows"
                 ],
-                "summary": "Mark notification as unread",
+                "summary": "Get workflow status",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Notification ID",
-                        "name": "notificationId",
+                        "description": "Workflow ID",
+                        "name": "workflowId",
                         "in": "path",
                         "required": true
                     }
@@ -1460,7 +4066,8 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/routes.Notification"
+                            "type": "object",
+                            "additionalProperties": true
                         }
                     },
                     "404": {
@@ -1475,7 +4082,7 @@ const docTemplate = `{
                 }
             }
         },
-        "/v1/workflows": {
+        "/v1/workflows/{workflowId}/triggers": {
             "get": {
                 "security": [
                     {
@@ -1485,49 +4092,21 @@ const docTemplate = `{
                         "ApiKeyAuth": []
                     }
                 ],
+                "description": "Cron, run_at and event triggers of the workflow with their next fire time and fire counts",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "workflows"
                 ],
-                "summary": "List workflows",
+                "summary": "List workflow triggers",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Filter by name contains",
-                        "name": "name",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Filter by status",
-                        "name": "status",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Sort field: created_at|name",
-                        "name": "sort_by",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "asc|desc",
-                        "name": "order",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Page number",
-                        "name": "page",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Items per page",
-                        "name": "limit",
-                        "in": "query"
+                        "description": "Workflow ID",
+                        "name": "workflowId",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
@@ -1537,6 +4116,15 @@ const docTemplate = `{
                             "type": "object",
                             "additionalProperties": true
                         }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
                     }
                 }
             },
@@ -1549,6 +4137,7 @@ const docTemplate = `{
                         "ApiKeyAuth": []
                     }
                 ],
+                "description": "Starts executions on a five-field UTC ` + "`" + `cron` + "`" + ` expression (or @hourly, @daily, ...), once at ` + "`" + `run_at` + "`" + ` (RFC3339), or for each analytics event or audit log matching ` + "`" + `event` + "`" + `, with optional parameters and context. ` + "`" + `missed_run_policy` + "`" + ` (skip|run_once|run_all, default run_once) decides what happens to scheduled fire times that were missed by more than a minute while the scheduler was paused or the clock moved forward (triggers are kept in memory and do not survive a restart); ` + "`" + `dedup_key` + "`" + ` names the event field whose value dedups event runs (the Idempotency-Key header is used otherwise).",
                 "consumes": [
                     "application/json"
                 ],
@@ -1558,12 +4147,22 @@ const docTemplate = `{
                 "tags": [
                     "workflows"
                 ],
-                "summary": "Create workflow",
+                "summary": "Create workflow trigger",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Workflow ID",
+                        "name": "workflowId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
                 "responses": {
                     "201": {
                         "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/routes.Workflow"
+                            "type": "object",
+                            "additionalProperties": true
                         }
                     },
                     "400": {
@@ -1574,12 +4173,19 @@ const docTemplate = `{
                                 "type": "string"
                             }
                         }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
                     }
                 }
-            }
-        },
-        "/v1/workflows/{workflowId}": {
-            "get": {
+            },
+            "delete": {
                 "security": [
                     {
                         "BearerAuth": []
@@ -1594,7 +4200,7 @@ const docTemplate = `{
                 "tags": [
                     "workflows"
                 ],
-                "summary": "Get workflow by ID",
+                "summary": "Delete all workflow triggers",
                 "parameters": [
                     {
                         "type": "string",
@@ -1608,7 +4214,8 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/routes.Workflow"
+                            "type": "object",
+                            "additionalProperties": true
                         }
                     },
                     "404": {
@@ -1621,8 +4228,10 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "put": {
+            }
+        },
+        "/v1/workflows/{workflowId}/triggers/{triggerId}": {
+            "delete": {
                 "security": [
                     {
                         "BearerAuth": []
@@ -1631,21 +4240,25 @@ const docTemplate = `{
                         "ApiKeyAuth": []
                     }
                 ],
-                "consumes": [
-                    "application/json"
-                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "workflows"
                 ],
-                "summary": "Update workflow",
+                "summary": "Delete workflow trigger",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Workflow ID",
-                        "name": "workflowId",
+                        "description": "Workflow ID",
+                        "name": "workflowId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Trigger ID",
+                        "name": "triggerId",
                         "in": "path",
                         "required": true
                     }
@@ -1653,17 +4266,9 @@ const docTemplate = `{
                 "responses": {
                     "200": {
                         "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/routes.Workflow"
-                        }
-                    },
-                    "400": {
-                        "description": "Bad Request",
                         "schema": {
                             "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "additionalProperties": true
                         }
                     },
                     "404": {
@@ -1678,7 +4283,7 @@ const docTemplate = `{
                 }
             }
         },
-        "/v1/workflows/{workflowId}/approve": {
+        "/v1/workflows/{workflowId}/unarchive": {
             "post": {
                 "security": [
                     {
@@ -1688,16 +4293,14 @@ const docTemplate = `{
                         "ApiKeyAuth": []
                     }
                 ],
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Returns an archived workflow to active when it has a published version, to draft otherwise",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "workflows"
                 ],
-                "summary": "Approve workflow step",
+                "summary": "Unarchive workflow",
                 "parameters": [
                     {
                         "type": "string",
@@ -1711,12 +4314,11 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/routes.Workflow"
                         }
                     },
-                    "400": {
-                        "description": "Bad Request",
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -1724,8 +4326,8 @@ const docTemplate = `{
                             }
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "409": {
+                        "description": "Conflict",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -1736,8 +4338,8 @@ const docTemplate = `{
                 }
             }
         },
-        "/v1/workflows/{workflowId}/execute": {
-            "post": {
+        "/v1/workflows/{workflowId}/versions": {
+            "get": {
                 "security": [
                     {
                         "BearerAuth": []
@@ -1746,16 +4348,14 @@ const docTemplate = `{
                         "ApiKeyAuth": []
                     }
                 ],
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Every version of the workflow definition, oldest first",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "workflows"
                 ],
-                "summary": "Execute workflow",
+                "summary": "List workflow versions",
                 "parameters": [
                     {
                         "type": "string",
@@ -1766,8 +4366,8 @@ const docTemplate = `{
                     }
                 ],
                 "responses": {
-                    "202": {
-                        "description": "Accepted",
+                    "200": {
+                        "description": "OK",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
@@ -1785,8 +4385,8 @@ const docTemplate = `{
                 }
             }
         },
-        "/v1/workflows/{workflowId}/reject": {
-            "post": {
+        "/v1/workflows/{workflowId}/versions/diff": {
+            "get": {
                 "security": [
                     {
                         "BearerAuth": []
@@ -1795,16 +4395,14 @@ const docTemplate = `{
                         "ApiKeyAuth": []
                     }
                 ],
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Steps added, removed and changed (per field) and metadata keys added, removed and changed between two versions",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "workflows"
                 ],
-                "summary": "Reject workflow step",
+                "summary": "Diff workflow versions",
                 "parameters": [
                     {
                         "type": "string",
@@ -1812,6 +4410,20 @@ const docTemplate = `{
                         "name": "workflowId",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Base version",
+                        "name": "from",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Compared version",
+                        "name": "to",
+                        "in": "query",
+                        "required": true
                     }
                 ],
                 "responses": {
@@ -1843,7 +4455,7 @@ const docTemplate = `{
                 }
             }
         },
-        "/v1/workflows/{workflowId}/status": {
+        "/v1/workflows/{workflowId}/versions/{version}": {
             "get": {
                 "security": [
                     {
@@ -1859,7 +4471,7 @@ const docTemplate = `{
                 "tags": [
                     "workflows"
                 ],
-                "summary": "Get workflow status",
+                "summary": "Get workflow version",
                 "parameters": [
                     {
                         "type": "string",
@@ -1867,6 +4479,13 @@ const docTemplate = `{
                         "name": "workflowId",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Version",
+                        "name": "version",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
@@ -1877,6 +4496,15 @@ const docTemplate = `{
                             "additionalProperties": true
                         }
                     },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
                     "404": {
                         "description": "Not Found",
                         "schema": {
@@ -1891,6 +4519,176 @@ const docTemplate = `{
         }
     },
     "definitions": {
+        "adapters.BreakerStatus": {
+            "type": "object",
+            "properties": {
+                "consecutive_failures": {
+                    "type": "integer"
+                },
+                "opened_at": {
+                    "type": "string"
+                },
+                "retry_at": {
+                    "type": "string"
+                },
+                "state": {
+                    "type": "string"
+                }
+            }
+        },
+        "adapters.ChatChoice": {
+            "type": "object",
+            "properties": {
+                "finish_reason": {
+                    "type": "string"
+                },
+                "index": {
+                    "type": "integer"
+                },
+                "message": {
+                    "$ref": "#/definitions/adapters.ChatMessage"
+                }
+            }
+        },
+        "adapters.ChatMessage": {
+            "type": "object",
+            "properties": {
+                "content": {
+                    "type": "string",
+                    "example": "Hello, how are you?"
+                },
+                "role": {
+                    "type": "string",
+                    "example": "user"
+                }
+            }
+        },
+        "adapters.ChatResponse": {
+            "type": "object",
+            "properties": {
+                "choices": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/adapters.ChatChoice"
+                    }
+                },
+                "created": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "model": {
+                    "type": "string"
+                },
+                "object": {
+                    "type": "string"
+                },
+                "usage": {
+                    "$ref": "#/definitions/adapters.Usage"
+                }
+            }
+        },
+        "adapters.ModelEntry": {
+            "type": "object",
+            "properties": {
+                "capabilities": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "config": {
+                    "type": "object",
+                    "additionalProperties": {}
+                },
+                "fallback": {
+                    "description": "Fallback lists models tried in order when this one fails with a retryable error",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "max_tokens": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "resilience": {
+                    "description": "Resilience overrides DefaultResilience for calls to this model",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/adapters.ResiliencePolicy"
+                        }
+                    ]
+                },
+                "status": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string"
+                },
+                "urls": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "version": {
+                    "type": "string"
+                }
+            }
+        },
+        "adapters.ResiliencePolicy": {
+            "type": "object",
+            "properties": {
+                "attempt_timeout_ms": {
+                    "type": "integer"
+                },
+                "base_backoff_ms": {
+                    "type": "integer"
+                },
+                "breaker_cooldown_ms": {
+                    "type": "integer"
+                },
+                "breaker_threshold": {
+                    "type": "integer"
+                },
+                "deadline_ms": {
+                    "type": "integer"
+                },
+                "max_attempts": {
+                    "type": "integer"
+                },
+                "max_backoff_ms": {
+                    "type": "integer"
+                },
+                "retry_on": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                }
+            }
+        },
+        "adapters.Usage": {
+            "type": "object",
+            "properties": {
+                "completion_tokens": {
+                    "type": "integer",
+                    "example": 25
+                },
+                "prompt_tokens": {
+                    "type": "integer",
+                    "example": 10
+                },
+                "total_tokens": {
+                    "type": "integer",
+                    "example": 35
+                }
+            }
+        },
         "routes.AdminApplicationConfig": {
             "type": "object",
             "properties": {
@@ -1917,8 +4715,14 @@ const docTemplate = `{
                 "performance": {
                     "$ref": "#/definitions/routes.AdminConfigPatchPerformance"
                 },
+                "quotas": {
+                    "$ref": "#/definitions/routes.AdminConfigPatchQuotas"
+                },
                 "security": {
                     "$ref": "#/definitions/routes.AdminConfigPatchSecurity"
+                },
+                "workflows": {
+                    "$ref": "#/definitions/routes.AdminConfigPatchWorkflows"
                 }
             }
         },
@@ -1939,6 +4743,9 @@ const docTemplate = `{
         "routes.AdminConfigPatchPerformance": {
             "type": "object",
             "properties": {
+                "cache_enabled": {
+                    "type": "boolean"
+                },
                 "cache_ttl": {
                     "type": "integer",
                     "example": 1200
@@ -1953,9 +4760,46 @@ const docTemplate = `{
                 }
             }
         },
+        "routes.AdminConfigPatchQuotas": {
+            "type": "object",
+            "properties": {
+                "daily_tokens": {
+                    "type": "integer",
+                    "example": 50000
+                },
+                "enabled": {
+                    "type": "boolean"
+                },
+                "monthly_tokens": {
+                    "type": "integer",
+                    "example": 1000000
+                },
+                "overrides": {
+                    "description": "Set a subject's quota; a null value removes the override",
+                    "type": "object",
+                    "additionalProperties": {
+                        "$ref": "#/definitions/routes.AdminQuotaOverride"
+                    }
+                }
+            }
+        },
         "routes.AdminConfigPatchSecurity": {
             "type": "object",
             "properties": {
+                "api_key_requests": {
+                    "type": "integer",
+                    "example": 1000
+                },
+                "rate_limit_enabled": {
+                    "type": "boolean"
+                },
+                "rate_limit_overrides": {
+                    "description": "Set a subject's limit; a null value removes the override",
+                    "type": "object",
+                    "additionalProperties": {
+                        "$ref": "#/definitions/routes.AdminRateLimitOverride"
+                    }
+                },
                 "rate_limit_requests": {
                     "type": "integer",
                     "example": 300
@@ -1966,6 +4810,15 @@ const docTemplate = `{
                 }
             }
         },
+        "routes.AdminConfigPatchWorkflows": {
+            "type": "object",
+            "properties": {
+                "execution_retention": {
+                    "type": "integer",
+                    "example": 3600
+                }
+            }
+        },
         "routes.AdminCreateBackupRequest": {
             "type": "object",
             "properties": {
@@ -2095,6 +4948,10 @@ const docTemplate = `{
                 "cache_ttl": {
                     "type": "integer",
                     "example": 600
+                },
+                "enabled": {
+                    "type": "boolean",
+                    "example": true
                 }
             }
         },
@@ -2118,7 +4975,56 @@ const docTemplate = `{
             "properties": {
                 "request_timeout": {
                     "type": "integer",
-                    "example": 15000
+                    "example": 15000
+                }
+            }
+        },
+        "routes.AdminQuotaOverride": {
+            "type": "object",
+            "properties": {
+                "daily_tokens": {
+                    "type": "integer",
+                    "example": 10000
+                },
+                "monthly_tokens": {
+                    "type": "integer",
+                    "example": 200000
+                }
+            }
+        },
+        "routes.AdminQuotasConfig": {
+            "type": "object",
+            "properties": {
+                "daily_tokens": {
+                    "type": "integer",
+                    "example": 0
+                },
+                "enabled": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "monthly_tokens": {
+                    "type": "integer",
+                    "example": 0
+                },
+                "overrides": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "$ref": "#/definitions/routes.AdminQuotaOverride"
+                    }
+                }
+            }
+        },
+        "routes.AdminRateLimitOverride": {
+            "type": "object",
+            "properties": {
+                "rate_limit_requests": {
+                    "type": "integer",
+                    "example": 10
+                },
+                "rate_limit_window": {
+                    "type": "integer",
+                    "example": 60
                 }
             }
         },
@@ -2150,10 +5056,22 @@ const docTemplate = `{
         "routes.AdminSecurityRateLimiting": {
             "type": "object",
             "properties": {
+                "api_key_requests": {
+                    "description": "Per-window budget for each API key; 0 means unlimited",
+                    "type": "integer",
+                    "example": 0
+                },
                 "enabled": {
                     "type": "boolean",
                     "example": true
                 },
+                "overrides": {
+                    "description": "Subject (\"user:\u003cid\u003e\" or \"api_key:\u003csha256 prefix\u003e\") -\u003e limit",
+                    "type": "object",
+                    "additionalProperties": {
+                        "$ref": "#/definitions/routes.AdminRateLimitOverride"
+                    }
+                },
                 "rate_limit_requests": {
                     "type": "integer",
                     "example": 200
@@ -2182,8 +5100,14 @@ const docTemplate = `{
                 "performance": {
                     "$ref": "#/definitions/routes.AdminPerformanceConfig"
                 },
+                "quotas": {
+                    "$ref": "#/definitions/routes.AdminQuotasConfig"
+                },
                 "security": {
                     "$ref": "#/definitions/routes.AdminSecurityConfig"
+                },
+                "workflows": {
+                    "$ref": "#/definitions/routes.AdminWorkflowsConfig"
                 }
             }
         },
@@ -2300,6 +5224,15 @@ const docTemplate = `{
                 }
             }
         },
+        "routes.AdminWorkflowsConfig": {
+            "type": "object",
+            "properties": {
+                "execution_retention": {
+                    "type": "integer",
+                    "example": 0
+                }
+            }
+        },
         "routes.AiBatchAccepted": {
             "type": "object",
             "properties": {
@@ -2309,11 +5242,15 @@ const docTemplate = `{
                 },
                 "job_id": {
                     "type": "string",
-                    "example": "job_abc123"
+                    "example": "job-abc123"
                 },
                 "status": {
                     "type": "string",
                     "example": "queued"
+                },
+                "total_requests": {
+                    "type": "integer",
+                    "example": 2
                 }
             }
         },
@@ -2324,6 +5261,13 @@ const docTemplate = `{
                     "type": "string",
                     "example": "https://example.com/callback"
                 },
+                "inputs": {
+                    "description": "Shorthand: plain prompts, completed with the batch-level model",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
                 "model": {
                     "type": "string",
                     "example": "adapter-a"
@@ -2331,11 +5275,136 @@ const docTemplate = `{
                 "requests": {
                     "type": "array",
                     "items": {
-                        "type": "object",
-                        "additionalProperties": {
-                            "type": "string"
-                        }
+                        "$ref": "#/definitions/routes.AiBatchRequestItem"
+                    }
+                }
+            }
+        },
+        "routes.AiBatchRequestItem": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "string",
+                    "example": "item-1"
+                },
+                "model": {
+                    "type": "string",
+                    "example": "adapter-b"
+                },
+                "prompt": {
+                    "type": "string",
+                    "example": "Hello"
+                }
+            }
+        },
+        "routes.AiCompleteAttempt": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string"
+                },
+                "model": {
+                    "type": "string",
+                    "example": "adapter-a"
+                },
+                "outcome": {
+                    "type": "string",
+                    "example": "success"
+                },
+                "status_code": {
+                    "type": "integer"
+                }
+            }
+        },
+        "routes.AiCompleteRequest": {
+            "type": "object",
+            "properties": {
+                "fallback": {
+                    "description": "Models tried in order on a retryable failure; defaults to the model's\nregistry fallback list",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "max_tokens": {
+                    "type": "integer",
+                    "example": 256
+                },
+                "model": {
+                    "type": "string",
+                    "example": "adapter-a"
+                },
+                "prompt": {
+                    "type": "string",
+                    "example": "Hello world"
+                },
+                "stop_sequences": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "stream": {
+                    "description": "Answer with a text/event-stream, as /v1/ai/complete/stream does",
+                    "type": "boolean",
+                    "example": false
+                },
+                "system_prompt": {
+                    "type": "string",
+                    "example": "You are a helpful assistant"
+                },
+                "temperature": {
+                    "description": "0 makes the request cacheable",
+                    "type": "number",
+                    "example": 0.7
+                },
+                "top_p": {
+                    "type": "number",
+                    "example": 0.9
+                }
+            }
+        },
+        "routes.AiCompleteResponse": {
+            "type": "object",
+            "properties": {
+                "attempts": {
+                    "description": "Every model tried, in order",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/routes.AiCompleteAttempt"
                     }
+                },
+                "completion": {
+                    "type": "string",
+                    "example": "A: Hello world"
+                },
+                "fallback_used": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "finish_reason": {
+                    "type": "string",
+                    "example": "stop"
+                },
+                "model": {
+                    "description": "The model that served the request",
+                    "type": "string",
+                    "example": "adapter-a"
+                },
+                "request_id": {
+                    "type": "string",
+                    "example": "req-1726482600-42"
+                },
+                "requested_model": {
+                    "type": "string",
+                    "example": "adapter-a"
+                },
+                "traceId": {
+                    "type": "string",
+                    "example": "3f9c2a7e5b1d4c8f"
+                },
+                "usage": {
+                    "$ref": "#/definitions/adapters.Usage"
                 }
             }
         },
@@ -2352,17 +5421,65 @@ const docTemplate = `{
                 }
             }
         },
+        "routes.AiJobProgress": {
+            "type": "object",
+            "properties": {
+                "completed": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "failed": {
+                    "type": "integer",
+                    "example": 0
+                },
+                "percentage": {
+                    "type": "integer",
+                    "example": 50
+                },
+                "succeeded": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "total": {
+                    "type": "integer",
+                    "example": 2
+                }
+            }
+        },
         "routes.AiJobResult": {
             "type": "object",
             "properties": {
+                "completed_at": {
+                    "type": "string",
+                    "example": "2025-09-17T12:00:02Z"
+                },
                 "completion": {
                     "type": "string",
-                    "example": "Sample completion for first prompt"
+                    "example": "A: Hello"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string",
+                    "example": "item-1"
                 },
                 "index": {
                     "type": "integer",
                     "example": 0
                 },
+                "model": {
+                    "type": "string",
+                    "example": "adapter-a"
+                },
+                "prompt": {
+                    "type": "string",
+                    "example": "Hello"
+                },
+                "started_at": {
+                    "type": "string",
+                    "example": "2025-09-17T12:00:01Z"
+                },
                 "status": {
                     "type": "string",
                     "example": "success"
@@ -2372,6 +5489,11 @@ const docTemplate = `{
         "routes.AiJobStatusResponse": {
             "type": "object",
             "properties": {
+                "callback_url": {
+                    "description": "Present when the batch was submitted with a callback_url",
+                    "type": "string",
+                    "example": "https://example.com/callback"
+                },
                 "completed_at": {
                     "type": "string",
                     "example": "2025-09-17T12:03:00Z"
@@ -2382,7 +5504,14 @@ const docTemplate = `{
                 },
                 "job_id": {
                     "type": "string",
-                    "example": "job_abc123"
+                    "example": "job-abc123"
+                },
+                "model": {
+                    "type": "string",
+                    "example": "adapter-a"
+                },
+                "progress": {
+                    "$ref": "#/definitions/routes.AiJobProgress"
                 },
                 "results": {
                     "type": "array",
@@ -2390,9 +5519,17 @@ const docTemplate = `{
                         "$ref": "#/definitions/routes.AiJobResult"
                     }
                 },
+                "started_at": {
+                    "type": "string",
+                    "example": "2025-09-17T12:00:01Z"
+                },
                 "status": {
                     "type": "string",
-                    "example": "completed"
+                    "example": "running"
+                },
+                "webhook_state": {
+                    "type": "string",
+                    "example": "delivered"
                 }
             }
         },
@@ -2444,6 +5581,13 @@ const docTemplate = `{
         "routes.AiModel": {
             "type": "object",
             "properties": {
+                "capabilities": {
+                    "description": "Capabilities as declared in the model registry",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
                 "max_tokens": {
                     "type": "integer",
                     "example": 4096
@@ -2486,6 +5630,14 @@ const docTemplate = `{
         "routes.AiModelStatusInfo": {
             "type": "object",
             "properties": {
+                "backends": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "registry_state": {
+                    "type": "string",
+                    "example": "active"
+                },
                 "status": {
                     "type": "string",
                     "example": "online"
@@ -2499,6 +5651,9 @@ const docTemplate = `{
         "routes.AiModelStatusResponse": {
             "type": "object",
             "properties": {
+                "circuit_breaker": {
+                    "$ref": "#/definitions/adapters.BreakerStatus"
+                },
                 "health": {
                     "$ref": "#/definitions/routes.AiModelHealth"
                 },
@@ -2506,6 +5661,9 @@ const docTemplate = `{
                     "type": "string",
                     "example": "adapter-a"
                 },
+                "resilience": {
+                    "$ref": "#/definitions/adapters.ResiliencePolicy"
+                },
                 "status": {
                     "$ref": "#/definitions/routes.AiModelStatusInfo"
                 }
@@ -2556,6 +5714,13 @@ const docTemplate = `{
                 "timestamp": {
                     "type": "string"
                 },
+                "triggered_executions": {
+                    "description": "Workflow executions this entry started, only in the create response",
+                    "type": "array",
+                    "items": {
+                        "type": "object"
+                    }
+                },
                 "user_agent": {
                     "type": "string"
                 },
@@ -2632,6 +5797,52 @@ const docTemplate = `{
                 }
             }
         },
+        "routes.ChatCompletionRequest": {
+            "type": "object",
+            "properties": {
+                "max_tokens": {
+                    "type": "integer",
+                    "example": 256
+                },
+                "messages": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/adapters.ChatMessage"
+                    }
+                },
+                "model": {
+                    "type": "string",
+                    "example": "adapter-a"
+                },
+                "stop": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "stream": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "stream_options": {
+                    "type": "object",
+                    "properties": {
+                        "include_usage": {
+                            "type": "boolean",
+                            "example": true
+                        }
+                    }
+                },
+                "temperature": {
+                    "type": "number",
+                    "example": 0.7
+                },
+                "top_p": {
+                    "type": "number",
+                    "example": 0.9
+                }
+            }
+        },
         "routes.Notification": {
             "type": "object",
             "properties": {
@@ -2660,6 +5871,14 @@ const docTemplate = `{
                 "recipient": {
                     "type": "string"
                 },
+                "recipient_id": {
+                    "description": "users-core ID of the recipient, when known",
+                    "type": "string"
+                },
+                "sender": {
+                    "description": "user:\u003cid\u003e, service or workflow:\u003cid\u003e",
+                    "type": "string"
+                },
                 "status": {
                     "type": "string"
                 },
@@ -2674,9 +5893,27 @@ const docTemplate = `{
                 }
             }
         },
+        "routes.StepRetry": {
+            "type": "object",
+            "properties": {
+                "backoff": {
+                    "description": "fixed (default)|exponential",
+                    "type": "string"
+                },
+                "backoff_ms": {
+                    "type": "integer"
+                },
+                "count": {
+                    "type": "integer"
+                }
+            }
+        },
         "routes.Workflow": {
             "type": "object",
             "properties": {
+                "archived_at": {
+                    "type": "string"
+                },
                 "created_at": {
                     "type": "string"
                 },
@@ -2703,7 +5940,18 @@ const docTemplate = `{
                 "name": {
                     "type": "string"
                 },
+                "next_fire_times": {
+                    "description": "NextFireTimes are the upcoming fire times of the workflow's triggers,\nkept up to date by the trigger scheduler.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "published_version": {
+                    "type": "integer"
+                },
                 "status": {
+                    "description": "draft|active|archived",
                     "type": "string"
                 },
                 "steps": {
@@ -2714,12 +5962,29 @@ const docTemplate = `{
                 },
                 "updated_at": {
                     "type": "string"
+                },
+                "version": {
+                    "type": "integer"
                 }
             }
         },
         "routes.WorkflowStep": {
             "type": "object",
             "properties": {
+                "adapter": {
+                    "type": "string"
+                },
+                "approvers": {
+                    "description": "Decision steps (approval, manual, task, automated): who may decide, how\nmany approvals are needed and which step to route to on rejection.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "config": {
+                    "type": "object",
+                    "additionalProperties": {}
+                },
                 "depends_on": {
                     "type": "array",
                     "items": {
@@ -2732,6 +5997,31 @@ const docTemplate = `{
                 "name": {
                     "type": "string"
                 },
+                "on_failure": {
+                    "description": "fail (default)|continue|\u003cstep id\u003e",
+                    "type": "string"
+                },
+                "on_reject": {
+                    "type": "string"
+                },
+                "quorum": {
+                    "type": "integer"
+                },
+                "requires_all": {
+                    "type": "boolean"
+                },
+                "retry": {
+                    "description": "Failure handling: failed attempts are retried per Retry; once they are\nused up, OnFailure decides what happens next.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/routes.StepRetry"
+                        }
+                    ]
+                },
+                "timeout": {
+                    "description": "seconds per attempt, 0 = none",
+                    "type": "integer"
+                },
                 "type": {
                     "type": "string"
                 }
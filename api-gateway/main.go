@@ -39,6 +39,8 @@ func main() {
 	r.Use(gin.Recovery())
 	r.Use(gwmiddleware.RequestID())
 	r.Use(gwmiddleware.AccessLog())
+	// Request log behind /v1/analytics/usage
+	r.Use(gwmiddleware.RecordRequests(routes.RecordRequest))
 	// Enforce stricter request validation aligned with test expectations
 	r.Use(gwmiddleware.StrictAuthValidation())
 
@@ -92,19 +94,45 @@ func main() {
 	// Initialize default admin user for testing compatibility
 	initializeDefaultAdmin(svc)
 
+	// Admin checks and notification senders/recipients resolve through users-core
+	routes.SetUserDirectory(svc, userRepo)
+
 	// Mount users routes via gin adapter
 	ginadapter.RegisterRoutes(r, svc, tokenizer)
 
+	// Model registry: built-in adapter-a/adapter-b unless a YAML/JSON file is configured
+	if err := adapters.InitRegistry(os.Getenv("MODEL_REGISTRY_FILE")); err != nil {
+		panic(err)
+	}
+
+	// Per-user / per-API-key limits, read live from the admin system config.
+	// One limiter is shared so a caller has a single budget across groups.
+	rateLimit := gwmiddleware.RateLimit(routes.RateLimitFor)
+
 	// Protected AI route (JWT or service API key)
 	ai := r.Group("/v1/ai")
 	ai.Use(gwmiddleware.JwtOrAPIKeyMiddleware(tokenizer, getenv("SERVICE_API_KEY", "service-secret")))
+	ai.Use(rateLimit)
 	ai.POST("/complete", routes.AiComplete)
+	ai.POST("/complete/stream", routes.AiCompleteStream)
 	ai.GET("/models", routes.AiListModels)
 	ai.GET("/models/:model/status", routes.AiModelStatus)
-	ai.POST("/models/:model/configure", routes.AiConfigureModel)
+	ai.POST("/models/:model/configure", routes.RequireAdmin, routes.AiConfigureModel)
 	ai.GET("/metrics", routes.AiMetrics)
+	ai.GET("/usage", routes.GetMyUsage)
 	ai.POST("/batch", routes.AiBatchComplete)
 	ai.GET("/jobs/:jobId", routes.AiJobStatus)
+	ai.DELETE("/jobs/:jobId", routes.AiCancelJob)
+	ai.GET("/jobs/:jobId/deliveries", routes.AiJobDeliveries)
+	ai.POST("/jobs/:jobId/redeliver", routes.AiRedeliverJobWebhook)
+
+	// OpenAI-compatible surface; also accepts the service key as a bearer token
+	openai := r.Group("/v1")
+	openai.Use(gwmiddleware.BearerAPIKey(getenv("SERVICE_API_KEY", "service-secret")))
+	openai.Use(gwmiddleware.JwtOrAPIKeyMiddleware(tokenizer, getenv("SERVICE_API_KEY", "service-secret")))
+	openai.Use(rateLimit)
+	openai.POST("/chat/completions", routes.ChatCompletions)
+	openai.GET("/models", routes.ListOpenAIModels)
 
 	// Adapter A proxy endpoints
 	adapterAURL := getenv("ADAPTER_A_URL", "http://localhost:8081")
@@ -121,6 +149,7 @@ func main() {
 	// Analytics and monitoring endpoints
 	analytics := r.Group("/v1/analytics")
 	analytics.Use(gwmiddleware.JwtOrAPIKeyMiddleware(tokenizer, getenv("SERVICE_API_KEY", "service-secret")))
+	analytics.Use(rateLimit)
 	analytics.GET("/usage", routes.GetUsageAnalytics)
 	analytics.GET("/performance", routes.GetPerformanceMetrics)
 	analytics.GET("/errors", routes.GetErrorAnalytics)
@@ -130,19 +159,45 @@ func main() {
 	// User workflow endpoints
 	workflows := r.Group("/v1/workflows")
 	workflows.Use(gwmiddleware.JwtOrAPIKeyMiddleware(tokenizer, getenv("SERVICE_API_KEY", "service-secret")))
+	workflows.Use(rateLimit)
 	workflows.GET("/", routes.ListWorkflows)
 	workflows.POST("/", routes.CreateWorkflow)
+	workflows.GET("/schema", routes.GetWorkflowSchema)
+	workflows.POST("/import", routes.ImportWorkflow)
+	workflows.POST("/purge", routes.PurgeWorkflows)
 	workflows.GET("/:workflowId", routes.GetWorkflow)
 	workflows.PUT("/:workflowId", routes.UpdateWorkflow)
+	workflows.DELETE("/:workflowId", routes.DeleteWorkflow)
+	workflows.POST("/:workflowId/archive", routes.ArchiveWorkflow)
+	workflows.POST("/:workflowId/unarchive", routes.UnarchiveWorkflow)
+	workflows.GET("/:workflowId/export", routes.ExportWorkflow)
+	workflows.GET("/:workflowId/graph", routes.GetWorkflowGraph)
 	workflows.POST("/:workflowId/execute", routes.ExecuteWorkflow)
 	workflows.GET("/:workflowId/status", routes.GetWorkflowStatus)
+	workflows.POST("/:workflowId/publish", routes.PublishWorkflow)
+	workflows.POST("/:workflowId/rollback", routes.RollbackWorkflow)
+	workflows.GET("/:workflowId/versions", routes.ListWorkflowVersions)
+	workflows.GET("/:workflowId/versions/diff", routes.DiffWorkflowVersions)
+	workflows.GET("/:workflowId/versions/:version", routes.GetWorkflowVersion)
+	workflows.GET("/:workflowId/executions", routes.ListWorkflowExecutions)
+	workflows.GET("/:workflowId/executions/:execId", routes.GetWorkflowExecution)
+	workflows.POST("/:workflowId/executions/:execId/cancel", routes.CancelWorkflowExecution)
+	workflows.POST("/:workflowId/executions/:execId/retry", routes.RetryWorkflowExecution)
+	workflows.GET("/:workflowId/triggers", routes.ListWorkflowTriggers)
+	workflows.POST("/:workflowId/triggers", routes.CreateWorkflowTrigger)
+	workflows.DELETE("/:workflowId/triggers", routes.DeleteWorkflowTriggers)
+	workflows.DELETE("/:workflowId/triggers/:triggerId", routes.DeleteWorkflowTrigger)
 	workflows.POST("/:workflowId/approve", routes.ApproveWorkflow)
 	workflows.POST("/:workflowId/reject", routes.RejectWorkflow)
 
 	// Notification system
 	notifications := r.Group("/v1/notifications")
 	notifications.Use(gwmiddleware.JwtOrAPIKeyMiddleware(tokenizer, getenv("SERVICE_API_KEY", "service-secret")))
+	notifications.Use(rateLimit)
 	notifications.GET("/", routes.ListNotifications)
+	notifications.GET("/me", routes.GetMyNotifications)
+	notifications.GET("/stream", routes.StreamNotifications)
+	notifications.GET("/ws", routes.NotificationSocket)
 	notifications.POST("/", routes.CreateNotification)
 	notifications.GET("/:notificationId", routes.GetNotificationByID)
 	notifications.PUT("/:notificationId", routes.UpdateNotification)
@@ -150,10 +205,15 @@ func main() {
 	notifications.PUT("/:notificationId/unread", routes.MarkNotificationUnread)
 	notifications.DELETE("/:notificationId", routes.DeleteNotification)
 	notifications.POST("/broadcast", routes.BroadcastNotification)
+	notifications.GET("/broadcasts", routes.ListBroadcasts)
+	notifications.GET("/broadcasts/:broadcastId", routes.GetBroadcast)
+	notifications.POST("/broadcasts/:broadcastId/cancel", routes.CancelBroadcast)
+	notifications.POST("/broadcasts/:broadcastId/reschedule", routes.RescheduleBroadcast)
 
 	// Audit logs
 	audit := r.Group("/v1/audit")
 	audit.Use(gwmiddleware.JwtOrAPIKeyMiddleware(tokenizer, getenv("SERVICE_API_KEY", "service-secret")))
+	audit.Use(rateLimit)
 	audit.GET("/logs", routes.GetAuditLogs)
 	audit.GET("/logs/:logId", routes.GetAuditLog)
 	audit.POST("/logs", routes.CreateAuditLog)
@@ -161,13 +221,29 @@ func main() {
 	// System administration
 	admin := r.Group("/v1/admin")
 	admin.Use(gwmiddleware.JwtOrAPIKeyMiddleware(tokenizer, getenv("SERVICE_API_KEY", "service-secret")))
+	admin.Use(rateLimit)
 	admin.GET("/system/status", routes.GetSystemStatus)
-	admin.POST("/system/maintenance", routes.SetMaintenanceMode)
+	admin.POST("/system/maintenance", routes.RequireAdmin, routes.SetMaintenanceMode)
 	admin.GET("/system/config", routes.GetSystemConfig)
-	admin.PUT("/system/config", routes.UpdateSystemConfig)
-	admin.POST("/system/backup", routes.CreateBackup)
+	admin.PUT("/system/config", routes.RequireAdmin, routes.UpdateSystemConfig)
+	admin.POST("/system/backup", routes.RequireAdmin, routes.CreateBackup)
 	admin.GET("/system/backups", routes.ListBackups)
 	admin.GET("/system/backup/:backupId", routes.GetBackupStatus)
+	admin.GET("/usage", routes.GetAllUsage)
+	admin.GET("/cache", routes.RequireAdmin, routes.GetCompletionCache)
+	admin.DELETE("/cache", routes.RequireAdmin, routes.PurgeCompletionCache)
+	admin.DELETE("/cache/:key", routes.RequireAdmin, routes.DeleteCompletionCacheEntry)
+	admin.GET("/clock", routes.GetClock)
+	// Moving the scheduler clock fires due triggers and broadcasts of every
+	// tenant, so it is only exposed in test/dev deployments.
+	if os.Getenv("ENABLE_TEST_CLOCK") == "true" {
+		admin.PUT("/clock", routes.RequireAdmin, routes.SetClock)
+		admin.DELETE("/clock", routes.RequireAdmin, routes.ResetClock)
+	}
+	admin.GET("/models", routes.ListRegisteredModels)
+	admin.PUT("/models/:model", routes.RequireAdmin, routes.UpsertRegisteredModel)
+	admin.DELETE("/models/:model", routes.RequireAdmin, routes.DeleteRegisteredModel)
+	admin.POST("/models/reload", routes.RequireAdmin, routes.ReloadModelRegistry)
 
 	port := getenv("PORT", "8080")
 	_ = r.Run(":" + port)
@@ -99,3 +99,16 @@ func JwtOrAPIKeyMiddleware(tokenizer users.Tokenizer, serviceKey string) gin.Han
 		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
 	}
 }
+
+// BearerAPIKey lets clients that only know how to send "Authorization: Bearer
+// <key>" (e.g. OpenAI SDKs) use the service API key: the header is rewritten to
+// x-api-key before JwtOrAPIKeyMiddleware runs. Other bearer tokens pass through.
+func BearerAPIKey(serviceKey string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if serviceKey != "" && c.GetHeader("Authorization") == "Bearer "+serviceKey {
+			c.Request.Header.Del("Authorization")
+			c.Request.Header.Set("x-api-key", serviceKey)
+		}
+		c.Next()
+	}
+}
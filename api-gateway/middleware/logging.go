@@ -4,6 +4,7 @@ import (
 	"crypto/rand"
 	"encoding/hex"
 	"encoding/json"
+	"net/http"
 	"os"
 	"time"
 
@@ -58,6 +59,36 @@ func AccessLog() gin.HandlerFunc {
 	}
 }
 
+// RequestRecord describes a finished request for usage analytics.
+type RequestRecord struct {
+	Method  string
+	Path    string
+	Route   string // matched route pattern, empty when no route matched
+	Subject string // see CallerSubject
+	Status  int
+	At      time.Time
+}
+
+// RecordRequests hands every finished request to record. It runs the rest of
+// the chain first, so the caller subject set by auth middleware is known.
+func RecordRequests(record func(RequestRecord)) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+		subject := ""
+		if c.Writer.Status() != http.StatusUnauthorized {
+			subject = CallerSubject(c)
+		}
+		record(RequestRecord{
+			Method:  c.Request.Method,
+			Path:    c.Request.URL.Path,
+			Route:   c.FullPath(),
+			Subject: subject,
+			Status:  c.Writer.Status(),
+			At:      time.Now().UTC(),
+		})
+	}
+}
+
 func toString(v any) string {
 	if s, ok := v.(string); ok {
 		return s
@@ -0,0 +1,151 @@
+package middleware
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var rateLimitRejections = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "gateway_rate_limit_rejections_total",
+	Help: "Requests rejected with 429 by the rate limiter, by caller type and route.",
+}, []string{"subject_type", "route"})
+
+// RateLimitLookup returns the limit for a subject: at most requests per
+// window. requests <= 0 means the subject is not limited. It is called on
+// every request so admin config changes apply immediately.
+type RateLimitLookup func(subject string) (requests int, window time.Duration)
+
+// bucket is a token bucket holding up to limit tokens, refilled at
+// limit/window per second.
+type bucket struct {
+	limit  int
+	window time.Duration
+	tokens float64
+	last   time.Time
+}
+
+type rateLimiter struct {
+	mu        sync.Mutex
+	buckets   map[string]*bucket
+	lookup    RateLimitLookup
+	now       func() time.Time
+	lastSweep time.Time
+}
+
+// RateLimit enforces per-caller token buckets keyed by the JWT user ID set by
+// JwtOrAPIKeyMiddleware or, for service calls, by the API key. It must run
+// after authentication. Limited responses carry X-RateLimit-Limit,
+// X-RateLimit-Remaining and X-RateLimit-Reset; rejections are 429 with
+// Retry-After.
+func RateLimit(lookup RateLimitLookup) gin.HandlerFunc {
+	rl := &rateLimiter{buckets: map[string]*bucket{}, lookup: lookup, now: time.Now}
+	return rl.handle
+}
+
+// CallerSubject identifies the caller for rate limits and usage accounting:
+// "user:<id>" for JWT callers and "api_key:<sha256 prefix>" for API key
+// callers, or "" when neither is present.
+func CallerSubject(c *gin.Context) string {
+	if id := c.GetString("userID"); id != "" {
+		return "user:" + id
+	}
+	if key := c.GetHeader("x-api-key"); key != "" {
+		sum := sha256.Sum256([]byte(key))
+		return "api_key:" + hex.EncodeToString(sum[:])[:12]
+	}
+	return ""
+}
+
+func (rl *rateLimiter) handle(c *gin.Context) {
+	subject := CallerSubject(c)
+	if subject == "" {
+		c.Next()
+		return
+	}
+	limit, window := rl.lookup(subject)
+	if limit <= 0 || window <= 0 {
+		c.Next()
+		return
+	}
+	allowed, remaining, reset, retryAfter := rl.take(subject, limit, window)
+	c.Header("X-RateLimit-Limit", strconv.Itoa(limit))
+	c.Header("X-RateLimit-Remaining", strconv.Itoa(remaining))
+	c.Header("X-RateLimit-Reset", strconv.Itoa(reset))
+	if !allowed {
+		subjectType := "user"
+		if c.GetString("userID") == "" {
+			subjectType = "api_key"
+		}
+		rateLimitRejections.WithLabelValues(subjectType, c.FullPath()).Inc()
+		c.Header("Retry-After", strconv.Itoa(retryAfter))
+		c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{
+			"error":       "rate limit exceeded",
+			"limit":       limit,
+			"window":      int(window / time.Second),
+			"retry_after": retryAfter,
+		})
+		return
+	}
+	c.Next()
+}
+
+// take spends one token from the subject's bucket. It returns whether the
+// request is allowed, the whole tokens left, the seconds until the bucket is
+// full again and, when rejected, the seconds until a token is available.
+func (rl *rateLimiter) take(subject string, limit int, window time.Duration) (bool, int, int, int) {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+	now := rl.now()
+	rl.sweep(now)
+
+	b, ok := rl.buckets[subject]
+	if !ok {
+		b = &bucket{limit: limit, window: window, tokens: float64(limit), last: now}
+		rl.buckets[subject] = b
+	}
+	if b.limit != limit || b.window != window {
+		// config changed: keep what was spent but respect the new capacity
+		b.limit, b.window = limit, window
+		b.tokens = math.Min(b.tokens, float64(limit))
+	}
+	rate := float64(limit) / window.Seconds()
+	b.tokens = math.Min(float64(limit), b.tokens+now.Sub(b.last).Seconds()*rate)
+	b.last = now
+
+	allowed := b.tokens >= 1
+	if allowed {
+		b.tokens--
+	}
+	reset := int(math.Ceil((float64(limit) - b.tokens) / rate))
+	retryAfter := 0
+	if !allowed {
+		retryAfter = int(math.Ceil((1 - b.tokens) / rate))
+		if retryAfter < 1 {
+			retryAfter = 1
+		}
+	}
+	return allowed, int(b.tokens), reset, retryAfter
+}
+
+// sweep drops buckets that have been idle long enough to be full again, at
+// most once a minute.
+func (rl *rateLimiter) sweep(now time.Time) {
+	if now.Sub(rl.lastSweep) < time.Minute {
+		return
+	}
+	rl.lastSweep = now
+	for k, b := range rl.buckets {
+		if now.Sub(b.last) >= b.window {
+			delete(rl.buckets, k)
+		}
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"net/http"
 	"sort"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -26,6 +27,8 @@ var (
 		CompletionMessage string
 	}{}
 
+	// configMu guards systemConfig, which the rate limiter reads on every request.
+	configMu     sync.RWMutex
 	systemConfig = map[string]any{
 		"application": gin.H{
 			"name":        "QA Playwright Gateway",
@@ -44,6 +47,10 @@ var (
 				"enabled":             true,
 				"rate_limit_requests": 200,
 				"rate_limit_window":   60,
+				// per-window budget for each API key; 0 leaves service keys unlimited
+				"api_key_requests": 0,
+				// subject ("user:<id>" or "api_key:<sha256 prefix>") -> limit
+				"overrides": gin.H{},
 			},
 		},
 		"performance": gin.H{
@@ -51,13 +58,27 @@ var (
 			"timeout_settings": gin.H{
 				"request_timeout": 15000,
 			},
+			// completion cache for temperature-0 requests (see ai_cache.go)
 			"cache_settings": gin.H{
+				"enabled":   true,
 				"cache_ttl": 600,
 			},
 		},
 		"logging": gin.H{
 			"level": "info",
 		},
+		// token quotas per caller subject; 0 means unlimited
+		"quotas": gin.H{
+			"enabled":        true,
+			"daily_tokens":   0,
+			"monthly_tokens": 0,
+			"overrides":      gin.H{},
+		},
+		// executions of a deleted workflow stay readable for
+		// execution_retention seconds; 0 deletes them with the workflow
+		"workflows": gin.H{
+			"execution_retention": 0,
+		},
 		"features": gin.H{
 			"analytics_enabled":     true,
 			"notifications_enabled": true,
@@ -128,6 +149,7 @@ func GetSystemStatus(c *gin.Context) {
 // @Param request body routes.AdminMaintenanceRequest true "Maintenance settings"
 // @Success 200 {object} map[string]interface{}
 // @Failure 400 {object} map[string]interface{}
+// @Failure 403 {object} map[string]string
 // @Router /v1/admin/system/maintenance [post]
 func SetMaintenanceMode(c *gin.Context) {
 	var req struct {
@@ -205,12 +227,38 @@ func SetMaintenanceMode(c *gin.Context) {
 // @Success 200 {object} routes.AdminSystemConfig
 // @Router /v1/admin/system/config [get]
 func GetSystemConfig(c *gin.Context) {
+	configMu.RLock()
+	defer configMu.RUnlock()
 	c.JSON(http.StatusOK, systemConfig)
 }
 
+// RateLimitFor returns the live rate limit for a caller subject as produced by
+// middleware.CallerSubject. Overrides win over the defaults; a requests
+// value of 0 means unlimited.
+func RateLimitFor(subject string) (int, time.Duration) {
+	configMu.RLock()
+	defer configMu.RUnlock()
+	rl := systemConfig["security"].(gin.H)["rate_limiting"].(gin.H)
+	if enabled, _ := rl["enabled"].(bool); !enabled {
+		return 0, 0
+	}
+	requests, _ := toInt(rl["rate_limit_requests"])
+	window, _ := toInt(rl["rate_limit_window"])
+	if strings.HasPrefix(subject, "api_key:") {
+		requests, _ = toInt(rl["api_key_requests"])
+	}
+	if o, ok := rl["overrides"].(gin.H)[subject].(gin.H); ok {
+		requests, _ = toInt(o["rate_limit_requests"])
+		if w, ok := toInt(o["rate_limit_window"]); ok {
+			window = w
+		}
+	}
+	return requests, time.Duration(window) * time.Second
+}
+
 // UpdateSystemConfig updates selected configuration values
 // @Summary Update system configuration
-// @Description Partially update system configuration (performance, security, features)
+// @Description Partially update system configuration (performance, security, quotas, workflows, features). The patch is applied only if every value is valid.
 // @Tags admin
 // @Security BearerAuth
 // @Security ApiKeyAuth
@@ -219,6 +267,7 @@ func GetSystemConfig(c *gin.Context) {
 // @Param request body routes.AdminConfigPatch true "Configuration patch"
 // @Success 200 {object} routes.AdminUpdateResponse
 // @Failure 400 {object} map[string]interface{}
+// @Failure 403 {object} map[string]string
 // @Router /v1/admin/system/config [put]
 func UpdateSystemConfig(c *gin.Context) {
 	var patch map[string]any
@@ -227,8 +276,13 @@ func UpdateSystemConfig(c *gin.Context) {
 		return
 	}
 
-	// Validate and apply
+	configMu.Lock()
+	defer configMu.Unlock()
+
+	// Validate everything first and only apply when the whole patch is valid;
+	// settings such as rate limits take effect immediately.
 	validationErrors := []string{}
+	changes := []func(){}
 	updated := gin.H{}
 
 	if perfRaw, ok := patch["performance"]; ok {
@@ -236,7 +290,7 @@ func UpdateSystemConfig(c *gin.Context) {
 			perf := systemConfig["performance"].(gin.H)
 			if v, ok := perfMap["max_connections"]; ok {
 				if n, ok := toInt(v); ok && n > 0 {
-					perf["max_connections"] = n
+					changes = append(changes, func() { perf["max_connections"] = n })
 				} else {
 					validationErrors = append(validationErrors, "performance.max_connections invalid")
 				}
@@ -245,7 +299,7 @@ func UpdateSystemConfig(c *gin.Context) {
 				// flatten into timeout_settings.request_timeout
 				if n, ok := toInt(v); ok && n > 0 {
 					ts := perf["timeout_settings"].(gin.H)
-					ts["request_timeout"] = n
+					changes = append(changes, func() { ts["request_timeout"] = n })
 				} else {
 					validationErrors = append(validationErrors, "performance.request_timeout invalid")
 				}
@@ -253,11 +307,19 @@ func UpdateSystemConfig(c *gin.Context) {
 			if v, ok := perfMap["cache_ttl"]; ok {
 				if n, ok := toInt(v); ok && n > 0 {
 					cs := perf["cache_settings"].(gin.H)
-					cs["cache_ttl"] = n
+					changes = append(changes, func() { cs["cache_ttl"] = n })
 				} else {
 					validationErrors = append(validationErrors, "performance.cache_ttl invalid")
 				}
 			}
+			if v, ok := perfMap["cache_enabled"]; ok {
+				if b, ok := v.(bool); ok {
+					cs := perf["cache_settings"].(gin.H)
+					changes = append(changes, func() { cs["enabled"] = b })
+				} else {
+					validationErrors = append(validationErrors, "performance.cache_enabled invalid")
+				}
+			}
 			updated["performance"] = perf
 		}
 	}
@@ -268,31 +330,144 @@ func UpdateSystemConfig(c *gin.Context) {
 			rl := sec["rate_limiting"].(gin.H)
 			if v, ok := secMap["rate_limit_requests"]; ok {
 				if n, ok := toInt(v); ok && n > 0 {
-					rl["rate_limit_requests"] = n
-					// Also expose flattened values since tests read them at top-level under security
-					sec["rate_limit_requests"] = n
+					changes = append(changes, func() {
+						rl["rate_limit_requests"] = n
+						// Also expose flattened values since tests read them at top-level under security
+						sec["rate_limit_requests"] = n
+					})
 				} else {
 					validationErrors = append(validationErrors, "security.rate_limit_requests invalid")
 				}
 			}
 			if v, ok := secMap["rate_limit_window"]; ok {
 				if n, ok := toInt(v); ok && n > 0 {
-					rl["rate_limit_window"] = n
-					sec["rate_limit_window"] = n
+					changes = append(changes, func() {
+						rl["rate_limit_window"] = n
+						sec["rate_limit_window"] = n
+					})
 				} else {
 					validationErrors = append(validationErrors, "security.rate_limit_window invalid")
 				}
 			}
+			if v, ok := secMap["rate_limit_enabled"]; ok {
+				if b, ok := v.(bool); ok {
+					changes = append(changes, func() { rl["enabled"] = b })
+				} else {
+					validationErrors = append(validationErrors, "security.rate_limit_enabled invalid")
+				}
+			}
+			if v, ok := secMap["api_key_requests"]; ok {
+				if n, ok := toInt(v); ok && n >= 0 {
+					changes = append(changes, func() { rl["api_key_requests"] = n })
+				} else {
+					validationErrors = append(validationErrors, "security.api_key_requests invalid")
+				}
+			}
+			if v, ok := secMap["rate_limit_overrides"]; ok {
+				if m, ok := v.(map[string]any); ok {
+					overrides := rl["overrides"].(gin.H)
+					for subject, raw := range m {
+						if raw == nil {
+							// null removes the override
+							changes = append(changes, func() { delete(overrides, subject) })
+							continue
+						}
+						o, _ := raw.(map[string]any)
+						n, okN := toInt(o["rate_limit_requests"])
+						w, okW := toInt(o["rate_limit_window"])
+						if !strings.HasPrefix(subject, "user:") && !strings.HasPrefix(subject, "api_key:") ||
+							!okN || n < 0 || (o["rate_limit_window"] != nil && (!okW || w <= 0)) {
+							validationErrors = append(validationErrors, "security.rate_limit_overrides."+subject+" invalid")
+							continue
+						}
+						entry := gin.H{"rate_limit_requests": n}
+						if okW {
+							entry["rate_limit_window"] = w
+						}
+						changes = append(changes, func() { overrides[subject] = entry })
+					}
+				} else {
+					validationErrors = append(validationErrors, "security.rate_limit_overrides invalid")
+				}
+			}
 			updated["security"] = sec
 		}
 	}
 
+	if qRaw, ok := patch["quotas"]; ok {
+		if qMap, ok := qRaw.(map[string]any); ok {
+			q := systemConfig["quotas"].(gin.H)
+			if v, ok := qMap["enabled"]; ok {
+				if b, ok := v.(bool); ok {
+					changes = append(changes, func() { q["enabled"] = b })
+				} else {
+					validationErrors = append(validationErrors, "quotas.enabled invalid")
+				}
+			}
+			for _, key := range []string{"daily_tokens", "monthly_tokens"} {
+				if v, ok := qMap[key]; ok {
+					if n, ok := toInt(v); ok && n >= 0 {
+						changes = append(changes, func() { q[key] = n })
+					} else {
+						validationErrors = append(validationErrors, "quotas."+key+" invalid")
+					}
+				}
+			}
+			if v, ok := qMap["overrides"]; ok {
+				if m, ok := v.(map[string]any); ok {
+					overrides := q["overrides"].(gin.H)
+					for subject, raw := range m {
+						if raw == nil {
+							changes = append(changes, func() { delete(overrides, subject) })
+							continue
+						}
+						o, _ := raw.(map[string]any)
+						entry := gin.H{}
+						valid := o != nil && (strings.HasPrefix(subject, "user:") || strings.HasPrefix(subject, "api_key:"))
+						for _, key := range []string{"daily_tokens", "monthly_tokens"} {
+							if o[key] == nil {
+								continue
+							}
+							if n, ok := toInt(o[key]); ok && n >= 0 {
+								entry[key] = n
+							} else {
+								valid = false
+							}
+						}
+						if !valid {
+							validationErrors = append(validationErrors, "quotas.overrides."+subject+" invalid")
+							continue
+						}
+						changes = append(changes, func() { overrides[subject] = entry })
+					}
+				} else {
+					validationErrors = append(validationErrors, "quotas.overrides invalid")
+				}
+			}
+			updated["quotas"] = q
+		}
+	}
+
+	if wfRaw, ok := patch["workflows"]; ok {
+		if wfMap, ok := wfRaw.(map[string]any); ok {
+			wfc := systemConfig["workflows"].(gin.H)
+			if v, ok := wfMap["execution_retention"]; ok {
+				if n, ok := toInt(v); ok && n >= 0 {
+					changes = append(changes, func() { wfc["execution_retention"] = n })
+				} else {
+					validationErrors = append(validationErrors, "workflows.execution_retention invalid")
+				}
+			}
+			updated["workflows"] = wfc
+		}
+	}
+
 	if featRaw, ok := patch["features"]; ok {
 		if featMap, ok := featRaw.(map[string]any); ok {
 			feat := systemConfig["features"].(gin.H)
 			for k, v := range featMap {
 				if b, ok := v.(bool); ok {
-					feat[k] = b
+					changes = append(changes, func() { feat[k] = b })
 				}
 			}
 			updated["features"] = feat
@@ -303,6 +478,9 @@ func UpdateSystemConfig(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "configuration invalid", "validation_errors": validationErrors})
 		return
 	}
+	for _, apply := range changes {
+		apply()
+	}
 
 	// Build response
 	resp := gin.H{
@@ -324,6 +502,7 @@ func UpdateSystemConfig(c *gin.Context) {
 // @Param request body routes.AdminCreateBackupRequest true "Backup options"
 // @Success 202 {object} routes.BackupEntry
 // @Failure 400 {object} map[string]interface{}
+// @Failure 403 {object} map[string]string
 // @Router /v1/admin/system/backup [post]
 func CreateBackup(c *gin.Context) {
 	var req struct {
@@ -1,10 +1,15 @@
 package routes
 
 import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/weltschmerz/QA-Playground/api-gateway/adapters"
+	gwmiddleware "github.com/weltschmerz/QA-Playground/api-gateway/middleware"
 )
 
 // @Summary AI text completion
@@ -14,34 +19,125 @@ import (
 // @Produce json
 // @Security BearerAuth
 // @Security ApiKeyAuth
-// @Param request body object{prompt=string,model=string} true "Completion request"
-// @Success 200 {object} object{model=string,completion=string,usage=object,traceId=string}
+// @Param Cache-Control header string false "no-cache skips the cache lookup, no-store also skips storing the result"
+// @Param request body routes.AiCompleteRequest true "Completion request"
+// @Success 200 {object} routes.AiCompleteResponse
+// @Header 200 {string} x-cache "HIT or MISS for temperature-0 requests, BYPASS when the cache was not consulted (x-test-fail requests never are)"
 // @Failure 400 {object} map[string]string
 // @Failure 401 {object} map[string]string
-// @Failure 502 {object} map[string]string
+// @Failure 429 {object} map[string]interface{} "Token quota exhausted"
+// @Failure 502 {object} map[string]interface{}
+// @Failure 503 {object} map[string]interface{}
 // @Router /v1/ai/complete [post]
 func AiComplete(c *gin.Context) {
-	type req struct{ Prompt, Model string }
-	var body req
+	var body struct {
+		adapters.CompletionRequest
+		Fallback []string `json:"fallback"`
+	}
 	if err := c.BindJSON(&body); err != nil || body.Prompt == "" || body.Model == "" {
 		c.JSON(400, gin.H{"error": "invalid payload"})
 		return
 	}
-	adapterURL, err := adapters.ResolveAdapterURL(body.Model)
-	if err != nil {
-		c.JSON(400, gin.H{"error": err.Error()})
+	entry, ok := adapters.DefaultRegistry.Get(body.Model)
+	if !ok {
+		c.JSON(400, gin.H{"error": adapters.ErrUnknownModel.Error()})
+		return
+	}
+	if err := validateGeneration(body.CompletionRequest, entry); err != nil {
+		c.JSON(400, gin.H{"error": err.Error(), "model": body.Model})
 		return
 	}
+	if body.Stream {
+		if withinQuota(c) {
+			streamCompletion(c, body.CompletionRequest)
+		}
+		return
+	}
+	// cache hits cost no tokens, so they are served before the quota check
+	cacheKey, storeResult, served := serveFromCache(c, body.CompletionRequest, body.Fallback)
+	if served {
+		return
+	}
+	if !withinQuota(c) {
+		return
+	}
+	chain := fallbackChain(body.Model, body.Fallback)
 	failHeader := c.GetHeader("x-test-fail")
-	completion, err := adapters.CallAdapter(c, adapterURL, body.Prompt, body.Model, failHeader)
+	res, servedBy, attempts, err := completeWithFallback(c.Request.Context(), c.GetHeader("Authorization"), failHeader, body.CompletionRequest, chain)
 	if err != nil {
-		c.JSON(502, gin.H{"error": err.Error(), "model": body.Model, "completion": ""})
+		last := attempts[len(attempts)-1].Model
+		switch {
+		case len(attempts) == 1 && errors.Is(err, adapters.ErrCircuitOpen):
+			circuitOpen(c, last)
+		case errors.Is(err, adapters.ErrModelUnavailable), errors.Is(err, adapters.ErrCircuitOpen):
+			c.JSON(503, gin.H{"error": err.Error(), "model": last, "attempts": attempts})
+		default:
+			c.JSON(502, gin.H{"error": err.Error(), "model": last, "completion": "", "attempts": attempts})
+		}
 		return
 	}
-	c.JSON(200, gin.H{
-		"model":      body.Model,
-		"completion": completion,
-		"usage":      gin.H{"prompt_tokens": len(body.Prompt) / 4, "completion_tokens": len(completion) / 4, "total_tokens": len(body.Prompt)/4 + len(completion)/4},
-		"traceId":    time.Now().UnixNano(),
-	})
+	usage := res.Usage
+	if usage.TotalTokens == 0 && res.Text != "" {
+		// adapter did not report usage; fall back to the gateway estimate
+		usage = estimateUsage(body.Prompt, res.Text)
+	}
+	recordUsage(gwmiddleware.CallerSubject(c), servedBy, c.FullPath(), usage)
+	resp := gin.H{
+		"model":           servedBy,
+		"requested_model": body.Model,
+		"fallback_used":   servedBy != body.Model,
+		"attempts":        attempts,
+		"completion":      res.Text,
+		"usage":           usage,
+		"request_id":      res.RequestID,
+		"finish_reason":   res.FinishReason,
+		"traceId":         c.GetString("request_id"),
+	}
+	// only answers from the requested model are cached
+	if cacheKey != "" && storeResult && servedBy == body.Model {
+		storeCompletion(cacheKey, body.Model, body.Prompt, resp)
+	}
+	c.JSON(200, resp)
+}
+
+const maxStopSequences = 4
+
+// validateGeneration checks optional generation parameters against the
+// model's limits.
+func validateGeneration(req adapters.CompletionRequest, entry adapters.ModelEntry) error {
+	if req.Temperature != nil && (*req.Temperature < 0 || *req.Temperature > 2) {
+		return errors.New("temperature must be between 0 and 2")
+	}
+	if req.TopP != nil && (*req.TopP <= 0 || *req.TopP > 1) {
+		return errors.New("top_p must be greater than 0 and at most 1")
+	}
+	if req.MaxTokens < 0 {
+		return errors.New("max_tokens must not be negative")
+	}
+	if entry.MaxTokens > 0 && req.MaxTokens > entry.MaxTokens {
+		return fmt.Errorf("max_tokens exceeds %s limit of %d", entry.Name, entry.MaxTokens)
+	}
+	if len(req.StopSequences) > maxStopSequences {
+		return fmt.Errorf("at most %d stop_sequences are allowed", maxStopSequences)
+	}
+	for _, s := range req.StopSequences {
+		if s == "" {
+			return errors.New("stop_sequences must not contain empty strings")
+		}
+	}
+	return nil
+}
+
+// circuitOpen answers 503 for a model whose circuit breaker is open, with
+// Retry-After set to the end of the cooldown.
+func circuitOpen(c *gin.Context, model string) {
+	state := adapters.BreakerState(model)
+	if t, err := time.Parse(time.RFC3339, state.RetryAt); err == nil {
+		secs := int(math.Ceil(time.Until(t).Seconds()))
+		if secs < 1 {
+			secs = 1
+		}
+		c.Header("Retry-After", strconv.Itoa(secs))
+	}
+	c.JSON(503, gin.H{"error": adapters.ErrCircuitOpen.Error(), "model": model, "circuit_breaker": state})
 }
@@ -0,0 +1,406 @@
+package routes
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/weltschmerz/QA-Playground/api-gateway/adapters"
+	gwmiddleware "github.com/weltschmerz/QA-Playground/api-gateway/middleware"
+	"github.com/weltschmerz/QA-Playground/api-gateway/utils"
+)
+
+const (
+	maxBatchItems   = 100
+	jobRetention    = time.Hour
+	defaultWorkers  = 4
+	batchQueueDepth = 256
+)
+
+type batchItem struct {
+	Index       int    `json:"index"`
+	ID          string `json:"id,omitempty"`
+	Prompt      string `json:"prompt"`
+	Model       string `json:"model"`
+	ServedBy    string `json:"served_by,omitempty"` // set when a fallback model answered
+	Status      string `json:"status"`              // queued|running|success|failed|cancelled
+	Completion  string `json:"completion,omitempty"`
+	Error       string `json:"error,omitempty"`
+	StartedAt   string `json:"started_at,omitempty"`
+	CompletedAt string `json:"completed_at,omitempty"`
+}
+
+type batchJob struct {
+	ID          string
+	Status      string // queued|running|completed|failed|cancelled
+	Model       string
+	CallbackURL string
+	CreatedAt   string
+	StartedAt   string
+	CompletedAt string
+	Items       []batchItem
+
+	auth       string
+	owner      string // submitting user ID; empty for the service API key
+	failHeader string
+	subject    string // caller charged for usage
+	ctx        context.Context
+	cancel     context.CancelFunc
+	finishedAt time.Time
+
+	// callback_url delivery (see ai_webhooks.go)
+	webhookPayload []byte
+	webhookState   string // pending|delivered|failed
+	deliveries     []webhookAttempt
+}
+
+type batchTask struct {
+	jobID string
+	index int
+}
+
+// In-memory job store and worker pool for /v1/ai/batch
+var (
+	jobStore   = map[string]*batchJob{}
+	jobMu      = new(sync.RWMutex)
+	batchQueue = make(chan batchTask, batchQueueDepth)
+	workerOnce sync.Once
+)
+
+// startBatchWorkers launches the worker pool once; size comes from BATCH_WORKERS.
+func startBatchWorkers() {
+	workerOnce.Do(func() {
+		n := defaultWorkers
+		if v, err := strconv.Atoi(os.Getenv("BATCH_WORKERS")); err == nil && v > 0 {
+			n = v
+		}
+		for i := 0; i < n; i++ {
+			go batchWorker()
+		}
+	})
+}
+
+func batchWorker() {
+	for task := range batchQueue {
+		runBatchItem(task)
+	}
+}
+
+func runBatchItem(task batchTask) {
+	jobMu.Lock()
+	job, ok := jobStore[task.jobID]
+	if !ok || job.ctx.Err() != nil {
+		jobMu.Unlock()
+		return
+	}
+	now := time.Now().UTC().Format(time.RFC3339)
+	item := &job.Items[task.index]
+	item.Status = "running"
+	item.StartedAt = now
+	if job.Status == "queued" {
+		job.Status = "running"
+		job.StartedAt = now
+	}
+	ctx, auth, failHeader, subject := job.ctx, job.auth, job.failHeader, job.subject
+	prompt, model := item.Prompt, item.Model
+	jobMu.Unlock()
+
+	var res adapters.Completion
+	var servedBy string
+	var err error
+	if qe := checkQuota(subject); qe != nil {
+		err = fmt.Errorf("token quota exceeded: %s limit of %d", qe.Period, qe.Limit)
+	} else {
+		res, servedBy, _, err = completeWithFallback(ctx, auth, failHeader, adapters.CompletionRequest{Prompt: prompt}, fallbackChain(model, nil))
+	}
+	if err == nil {
+		usage := res.Usage
+		if usage.TotalTokens == 0 {
+			usage = estimateUsage(prompt, res.Text)
+		}
+		recordUsage(subject, servedBy, "/v1/ai/batch", usage)
+	}
+
+	jobMu.Lock()
+	defer jobMu.Unlock()
+	if ctx.Err() != nil {
+		// AiCancelJob already marked unfinished items
+		return
+	}
+	item.CompletedAt = time.Now().UTC().Format(time.RFC3339)
+	if err != nil {
+		item.Status = "failed"
+		item.Error = err.Error()
+	} else {
+		item.Status = "success"
+		item.Completion = res.Text
+		if servedBy != model {
+			item.ServedBy = servedBy
+		}
+	}
+	finishJobIfDone(job)
+}
+
+// finishJobIfDone sets the terminal job status once no item is pending. Caller holds jobMu.
+func finishJobIfDone(job *batchJob) {
+	succeeded := 0
+	for _, it := range job.Items {
+		switch it.Status {
+		case "queued", "running":
+			return
+		case "success":
+			succeeded++
+		}
+	}
+	job.Status = "completed"
+	if succeeded == 0 {
+		job.Status = "failed"
+	}
+	job.finishedAt = time.Now().UTC()
+	job.CompletedAt = job.finishedAt.Format(time.RFC3339)
+	job.cancel()
+	queueJobWebhook(job)
+}
+
+// pruneJobs drops finished jobs older than jobRetention. Caller holds jobMu.
+func pruneJobs() {
+	cutoff := time.Now().UTC().Add(-jobRetention)
+	for id, j := range jobStore {
+		if !j.finishedAt.IsZero() && j.finishedAt.Before(cutoff) {
+			delete(jobStore, id)
+		}
+	}
+}
+
+func jobProgress(job *batchJob) gin.H {
+	var done, succeeded, failed int
+	for _, it := range job.Items {
+		switch it.Status {
+		case "success":
+			done++
+			succeeded++
+		case "failed":
+			done++
+			failed++
+		case "cancelled":
+			done++
+		}
+	}
+	pct := 0
+	if len(job.Items) > 0 {
+		pct = done * 100 / len(job.Items)
+	}
+	return gin.H{
+		"total":      len(job.Items),
+		"completed":  done,
+		"succeeded":  succeeded,
+		"failed":     failed,
+		"percentage": pct,
+	}
+}
+
+func jobView(job *batchJob) gin.H {
+	results := make([]batchItem, len(job.Items))
+	copy(results, job.Items)
+	view := gin.H{
+		"job_id":     job.ID,
+		"status":     job.Status,
+		"created_at": job.CreatedAt,
+		"progress":   jobProgress(job),
+		"results":    results,
+	}
+	if job.Model != "" {
+		view["model"] = job.Model
+	}
+	if job.StartedAt != "" {
+		view["started_at"] = job.StartedAt
+	}
+	if job.CompletedAt != "" {
+		view["completed_at"] = job.CompletedAt
+	}
+	if job.CallbackURL != "" {
+		view["callback_url"] = job.CallbackURL
+		view["webhook_state"] = job.webhookState
+	}
+	return view
+}
+
+// AiBatchComplete queues batch completion requests and returns a job ID
+// @Summary Batch AI completion
+// @Description Queues each entry of requests (or inputs, a list of prompts) for completion and returns a job id
+// @Tags ai
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param request body routes.AiBatchRequest true "Batch request"
+// @Success 202 {object} routes.AiBatchAccepted
+// @Failure 400 {object} map[string]interface{}
+// @Router /v1/ai/batch [post]
+func AiBatchComplete(c *gin.Context) {
+	var req struct {
+		Requests []struct {
+			ID     string `json:"id"`
+			Prompt string `json:"prompt"`
+			Model  string `json:"model"`
+		} `json:"requests"`
+		Inputs      []string `json:"inputs"`
+		Model       string   `json:"model"`
+		CallbackURL string   `json:"callback_url"`
+	}
+	if err := c.BindJSON(&req); err != nil || (len(req.Requests) == 0 && len(req.Inputs) == 0) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid batch request"})
+		return
+	}
+	if req.CallbackURL != "" && !validCallbackURL(req.CallbackURL) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid callback_url"})
+		return
+	}
+	defaultModel := strings.TrimSpace(req.Model)
+	if defaultModel == "" {
+		defaultModel = "adapter-a"
+	}
+	items := make([]batchItem, 0, len(req.Requests)+len(req.Inputs))
+	for _, r := range req.Requests {
+		items = append(items, batchItem{ID: r.ID, Prompt: r.Prompt, Model: strings.TrimSpace(r.Model)})
+	}
+	for _, p := range req.Inputs {
+		items = append(items, batchItem{Prompt: p})
+	}
+	if len(items) > maxBatchItems {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "too many requests in batch", "max_items": maxBatchItems})
+		return
+	}
+	for i := range items {
+		items[i].Index = i
+		items[i].Status = "queued"
+		if items[i].Model == "" {
+			items[i].Model = defaultModel
+		}
+		if strings.TrimSpace(items[i].Prompt) == "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "prompt is required", "index": i})
+			return
+		}
+		if _, ok := adapters.DefaultRegistry.Get(items[i].Model); !ok {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "unknown model", "index": i, "model": items[i].Model})
+			return
+		}
+	}
+
+	if !withinQuota(c) {
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	job := &batchJob{
+		ID:          "job-" + utils.GenID()[:12],
+		Status:      "queued",
+		Model:       strings.TrimSpace(req.Model),
+		CallbackURL: req.CallbackURL,
+		CreatedAt:   time.Now().UTC().Format(time.RFC3339),
+		Items:       items,
+		auth:        c.GetHeader("Authorization"),
+		owner:       c.GetString("userID"),
+		failHeader:  c.GetHeader("x-test-fail"),
+		subject:     gwmiddleware.CallerSubject(c),
+		ctx:         ctx,
+		cancel:      cancel,
+	}
+	jobMu.Lock()
+	pruneJobs()
+	jobStore[job.ID] = job
+	jobMu.Unlock()
+
+	startBatchWorkers()
+	go func(jobID string, n int) {
+		for i := 0; i < n; i++ {
+			select {
+			case batchQueue <- batchTask{jobID: jobID, index: i}:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}(job.ID, len(items))
+
+	c.JSON(http.StatusAccepted, gin.H{
+		"job_id":               job.ID,
+		"status":               "queued",
+		"total_requests":       len(items),
+		"estimated_completion": "2-5 minutes",
+	})
+}
+
+// visibleJob returns the job named by :jobId when the caller submitted it or is
+// the service API key or an admin; anyone else gets the same 404 as for an
+// unknown job. Caller holds jobMu.
+func visibleJob(c *gin.Context) (*batchJob, bool) {
+	job, ok := jobStore[c.Param("jobId")]
+	if !ok || (job.owner != c.GetString("userID") && !isPrivilegedCaller(c)) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "job not found"})
+		return nil, false
+	}
+	return job, true
+}
+
+// AiJobStatus returns job status, progress and (partial) results
+// @Summary AI job status
+// @Description Returns the status, progress and per-item results for a batch completion job; jobs of other callers answer 404
+// @Tags ai
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Produce json
+// @Param jobId path string true "Job ID"
+// @Success 200 {object} routes.AiJobStatusResponse
+// @Failure 404 {object} map[string]string
+// @Router /v1/ai/jobs/{jobId} [get]
+func AiJobStatus(c *gin.Context) {
+	jobMu.RLock()
+	defer jobMu.RUnlock()
+	job, ok := visibleJob(c)
+	if !ok {
+		return
+	}
+	c.JSON(http.StatusOK, jobView(job))
+}
+
+// AiCancelJob cancels a queued or running batch job
+// @Summary Cancel AI job
+// @Description Cancels pending items of a batch job; finished items keep their results
+// @Tags ai
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Produce json
+// @Param jobId path string true "Job ID"
+// @Success 200 {object} routes.AiJobStatusResponse
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /v1/ai/jobs/{jobId} [delete]
+func AiCancelJob(c *gin.Context) {
+	jobMu.Lock()
+	defer jobMu.Unlock()
+	job, ok := visibleJob(c)
+	if !ok {
+		return
+	}
+	if job.Status != "queued" && job.Status != "running" {
+		c.JSON(http.StatusConflict, gin.H{"error": "job already finished", "status": job.Status})
+		return
+	}
+	job.cancel()
+	for i := range job.Items {
+		if job.Items[i].Status == "queued" || job.Items[i].Status == "running" {
+			job.Items[i].Status = "cancelled"
+		}
+	}
+	job.Status = "cancelled"
+	job.finishedAt = time.Now().UTC()
+	job.CompletedAt = job.finishedAt.Format(time.RFC3339)
+	queueJobWebhook(job)
+	c.JSON(http.StatusOK, jobView(job))
+}
@@ -0,0 +1,262 @@
+package routes
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/weltschmerz/QA-Playground/api-gateway/adapters"
+)
+
+const maxCacheEntries = 1000
+
+// cacheEntry is a stored /v1/ai/complete response body.
+type cacheEntry struct {
+	Key       string
+	Model     string
+	Prompt    string
+	Response  gin.H
+	CreatedAt time.Time
+	Hits      int
+}
+
+var (
+	cacheMu    sync.Mutex
+	cacheStore = map[string]*cacheEntry{}
+
+	cacheRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "gateway_completion_cache_requests_total",
+		Help: "Cacheable completion requests by result (hit|miss|bypass).",
+	}, []string{"result"})
+	cacheEntries = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "gateway_completion_cache_entries",
+		Help: "Completions currently held in the gateway cache.",
+	})
+)
+
+// cacheSettings returns whether the completion cache is enabled and its TTL,
+// read live from performance.cache_settings in the system config.
+func cacheSettings() (bool, time.Duration) {
+	configMu.RLock()
+	defer configMu.RUnlock()
+	cs := systemConfig["performance"].(gin.H)["cache_settings"].(gin.H)
+	enabled, _ := cs["enabled"].(bool)
+	ttl, _ := toInt(cs["cache_ttl"])
+	return enabled && ttl > 0, time.Duration(ttl) * time.Second
+}
+
+// completionCacheKey returns the cache key for a request, or "" when the
+// request is not deterministic (only temperature 0 is cached). Requests with
+// an explicit fallback list are keyed on it too.
+func completionCacheKey(req adapters.CompletionRequest, fallback []string) string {
+	if req.Temperature == nil || *req.Temperature != 0 || req.Stream {
+		return ""
+	}
+	b, _ := json.Marshal(struct {
+		adapters.CompletionRequest
+		Fallback []string `json:"fallback"`
+	}{req, fallback})
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:])
+}
+
+// cacheDirectives reads Cache-Control: no-cache skips the lookup (the fresh
+// result is still stored), no-store skips both.
+func cacheDirectives(c *gin.Context) (lookup, store bool) {
+	lookup, store = true, true
+	for _, d := range strings.Split(c.GetHeader("Cache-Control"), ",") {
+		switch strings.ToLower(strings.TrimSpace(d)) {
+		case "no-cache":
+			lookup = false
+		case "no-store":
+			lookup, store = false, false
+		}
+	}
+	return lookup, store
+}
+
+// serveFromCache answers a cacheable request from the cache when possible and
+// sets x-cache to HIT, MISS or BYPASS (not looked up). It returns the
+// request's cache key ("" when not cacheable), whether a fresh result may be
+// stored, and whether the response was already written. Failure-injection
+// requests (x-test-fail) never touch the cache.
+func serveFromCache(c *gin.Context, req adapters.CompletionRequest, fallback []string) (key string, store, served bool) {
+	c.Header("x-cache", "BYPASS")
+	enabled, ttl := cacheSettings()
+	if !enabled || c.GetHeader("x-test-fail") != "" {
+		return "", false, false
+	}
+	if key = completionCacheKey(req, fallback); key == "" {
+		return "", false, false
+	}
+	lookup, store := cacheDirectives(c)
+	if !lookup {
+		cacheRequests.WithLabelValues("bypass").Inc()
+		return key, store, false
+	}
+	resp, ok := cachedCompletion(key, ttl)
+	if !ok {
+		cacheRequests.WithLabelValues("miss").Inc()
+		c.Header("x-cache", "MISS")
+		return key, store, false
+	}
+	cacheRequests.WithLabelValues("hit").Inc()
+	resp["traceId"] = c.GetString("request_id")
+	c.Header("x-cache", "HIT")
+	c.JSON(http.StatusOK, resp)
+	return key, store, true
+}
+
+// cachedCompletion returns a copy of the live cached response for key.
+func cachedCompletion(key string, ttl time.Duration) (gin.H, bool) {
+	cacheMu.Lock()
+	defer cacheMu.Unlock()
+	e, ok := cacheStore[key]
+	if !ok {
+		return nil, false
+	}
+	if time.Since(e.CreatedAt) >= ttl {
+		delete(cacheStore, key)
+		cacheEntries.Set(float64(len(cacheStore)))
+		return nil, false
+	}
+	e.Hits++
+	out := gin.H{}
+	for k, v := range e.Response {
+		out[k] = v
+	}
+	return out, true
+}
+
+// storeCompletion caches a response, evicting the oldest entry when full.
+func storeCompletion(key, model, prompt string, resp gin.H) {
+	cacheMu.Lock()
+	defer cacheMu.Unlock()
+	if _, ok := cacheStore[key]; !ok && len(cacheStore) >= maxCacheEntries {
+		var oldest *cacheEntry
+		for _, e := range cacheStore {
+			if oldest == nil || e.CreatedAt.Before(oldest.CreatedAt) {
+				oldest = e
+			}
+		}
+		delete(cacheStore, oldest.Key)
+	}
+	stored := gin.H{}
+	for k, v := range resp {
+		if k != "traceId" {
+			stored[k] = v
+		}
+	}
+	cacheStore[key] = &cacheEntry{Key: key, Model: model, Prompt: prompt, Response: stored, CreatedAt: time.Now().UTC()}
+	cacheEntries.Set(float64(len(cacheStore)))
+}
+
+// GetCompletionCache lists cached completions
+// @Summary Inspect completion cache
+// @Description Returns cache settings and the cached /v1/ai/complete responses (temperature 0 only), newest first. Expired entries are dropped.
+// @Tags admin
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Produce json
+// @Param model query string false "Only entries for this model"
+// @Success 200 {object} map[string]interface{}
+// @Failure 403 {object} map[string]string
+// @Router /v1/admin/cache [get]
+func GetCompletionCache(c *gin.Context) {
+	enabled, ttl := cacheSettings()
+	model := strings.TrimSpace(c.Query("model"))
+	cacheMu.Lock()
+	entries := make([]gin.H, 0, len(cacheStore))
+	for key, e := range cacheStore {
+		if time.Since(e.CreatedAt) >= ttl {
+			delete(cacheStore, key)
+			continue
+		}
+		if model != "" && e.Model != model {
+			continue
+		}
+		prompt := e.Prompt
+		if len(prompt) > 80 {
+			prompt = prompt[:80] + "..."
+		}
+		entries = append(entries, gin.H{
+			"key":        e.Key,
+			"model":      e.Model,
+			"prompt":     prompt,
+			"hits":       e.Hits,
+			"created_at": e.CreatedAt.Format(time.RFC3339),
+			"expires_at": e.CreatedAt.Add(ttl).Format(time.RFC3339),
+		})
+	}
+	cacheEntries.Set(float64(len(cacheStore)))
+	cacheMu.Unlock()
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i]["created_at"].(string) > entries[j]["created_at"].(string)
+	})
+	c.JSON(http.StatusOK, gin.H{
+		"enabled":     enabled,
+		"ttl_seconds": int(ttl / time.Second),
+		"max_entries": maxCacheEntries,
+		"count":       len(entries),
+		"entries":     entries,
+	})
+}
+
+// PurgeCompletionCache removes cached completions
+// @Summary Purge completion cache
+// @Description Removes all cached completions, or only those for a model
+// @Tags admin
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Produce json
+// @Param model query string false "Only purge entries for this model"
+// @Success 200 {object} map[string]interface{}
+// @Failure 403 {object} map[string]string
+// @Router /v1/admin/cache [delete]
+func PurgeCompletionCache(c *gin.Context) {
+	model := strings.TrimSpace(c.Query("model"))
+	cacheMu.Lock()
+	purged := 0
+	for key, e := range cacheStore {
+		if model == "" || e.Model == model {
+			delete(cacheStore, key)
+			purged++
+		}
+	}
+	cacheEntries.Set(float64(len(cacheStore)))
+	cacheMu.Unlock()
+	c.JSON(http.StatusOK, gin.H{"purged": purged})
+}
+
+// DeleteCompletionCacheEntry removes one cached completion
+// @Summary Delete a cache entry
+// @Tags admin
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Produce json
+// @Param key path string true "Cache key"
+// @Success 200 {object} map[string]interface{}
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /v1/admin/cache/{key} [delete]
+func DeleteCompletionCacheEntry(c *gin.Context) {
+	key := c.Param("key")
+	cacheMu.Lock()
+	_, ok := cacheStore[key]
+	delete(cacheStore, key)
+	cacheEntries.Set(float64(len(cacheStore)))
+	cacheMu.Unlock()
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": "cache entry not found"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"purged": 1})
+}
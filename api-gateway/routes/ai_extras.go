@@ -2,10 +2,9 @@ package routes
 
 import (
 	"net/http"
-	"time"
 
 	"github.com/gin-gonic/gin"
-	"github.com/weltschmerz/QA-Playground/api-gateway/utils"
+	"github.com/weltschmerz/QA-Playground/api-gateway/adapters"
 )
 
 // AiListModels returns available AI models and basic capabilities
@@ -18,16 +17,25 @@ import (
 // @Success 200 {object} routes.AiModelsResponse
 // @Router /v1/ai/models [get]
 func AiListModels(c *gin.Context) {
-	models := []map[string]interface{}{
-		{"name": "adapter-a", "type": "text-completion", "status": "active", "version": "1.0.0", "max_tokens": 4096},
-		{"name": "adapter-b", "type": "text-completion", "status": "active", "version": "1.2.0", "max_tokens": 8192},
+	entries := adapters.DefaultRegistry.List()
+	models := make([]map[string]interface{}, 0, len(entries))
+	for _, m := range entries {
+		models = append(models, map[string]interface{}{
+			"name":         m.Name,
+			"type":         m.Type,
+			"status":       m.Status,
+			"version":      m.Version,
+			"max_tokens":   m.MaxTokens,
+			"capabilities": m.Capabilities,
+			"fallback":     m.Fallback,
+		})
 	}
 	c.JSON(http.StatusOK, gin.H{"models": models})
 }
 
 // AiModelStatus returns health/status for a specific model
 // @Summary Get AI model status
-// @Description Returns health and status for a specific AI model
+// @Description Returns health and status for a specific AI model, including its circuit breaker state and effective retry policy
 // @Tags ai
 // @Security BearerAuth
 // @Security ApiKeyAuth
@@ -38,9 +46,24 @@ func AiListModels(c *gin.Context) {
 // @Router /v1/ai/models/{model}/status [get]
 func AiModelStatus(c *gin.Context) {
 	model := c.Param("model")
+	entry, ok := adapters.DefaultRegistry.Get(model)
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": "model not found"})
+		return
+	}
+	online := "online"
+	if entry.Status == "disabled" {
+		online = "offline"
+	}
 	status := gin.H{
-		"status": "online",
-		"uptime": "99.99%",
+		"status":         online,
+		"uptime":         "99.99%",
+		"registry_state": entry.Status,
+		"backends":       len(entry.URLs),
+	}
+	breaker := adapters.BreakerState(model)
+	if breaker.State == adapters.BreakerOpen {
+		status["status"] = "degraded"
 	}
 	health := gin.H{
 		"errors":       3,
@@ -48,15 +71,17 @@ func AiModelStatus(c *gin.Context) {
 		"memory_usage": "512MB",
 	}
 	c.JSON(http.StatusOK, gin.H{
-		"model":  model,
-		"status": status,
-		"health": health,
+		"model":           model,
+		"status":          status,
+		"health":          health,
+		"circuit_breaker": breaker,
+		"resilience":      adapters.PolicyFor(model),
 	})
 }
 
 // AiConfigureModel updates configuration settings for a specific model
 // @Summary Configure AI model
-// @Description Updates configuration settings for a specific AI model
+// @Description Updates configuration settings for a specific AI model. Registry fields (status, max_tokens, urls, capabilities, version) are applied to the model registry; other keys are stored as model config.
 // @Tags ai
 // @Security BearerAuth
 // @Security ApiKeyAuth
@@ -66,11 +91,12 @@ func AiModelStatus(c *gin.Context) {
 // @Param request body map[string]interface{} true "Configuration settings"
 // @Success 200 {object} routes.AiGenericMessageResponse
 // @Failure 400 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
 // @Failure 404 {object} map[string]interface{}
 // @Router /v1/ai/models/{model}/configure [post]
 func AiConfigureModel(c *gin.Context) {
 	model := c.Param("model")
-	if model != "adapter-a" && model != "adapter-b" {
+	if _, ok := adapters.DefaultRegistry.Get(model); !ok {
 		c.JSON(http.StatusNotFound, gin.H{"error": "model not found"})
 		return
 	}
@@ -79,7 +105,14 @@ func AiConfigureModel(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid configuration"})
 		return
 	}
-	c.JSON(http.StatusOK, gin.H{"message": "model configured successfully", "model": model})
+	updated, err := adapters.DefaultRegistry.Update(model, func(e *adapters.ModelEntry) error {
+		return applyModelConfig(e, config)
+	})
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid configuration: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "model configured successfully", "model": model, "config": updated})
 }
 
 // AiMetrics returns metrics for all AI models
@@ -105,57 +138,3 @@ func AiMetrics(c *gin.Context) {
 		},
 	})
 }
-
-// AiBatchComplete accepts batch requests and returns a job ID
-// @Summary Batch AI completion
-// @Description Accepts a batch completion request and returns a job id
-// @Tags ai
-// @Security BearerAuth
-// @Security ApiKeyAuth
-// @Accept json
-// @Produce json
-// @Param request body routes.AiBatchRequest true "Batch request"
-// @Success 202 {object} routes.AiBatchAccepted
-// @Failure 400 {object} map[string]interface{}
-// @Router /v1/ai/batch [post]
-func AiBatchComplete(c *gin.Context) {
-	var req struct {
-		Requests    []map[string]string `json:"requests"`
-		Model       string              `json:"model"`
-		CallbackURL string              `json:"callback_url"`
-	}
-	if err := c.BindJSON(&req); err != nil || len(req.Requests) == 0 {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid batch request"})
-		return
-	}
-	jobID := utils.GenID()
-	c.JSON(http.StatusAccepted, gin.H{
-		"job_id":               jobID,
-		"status":               "queued",
-		"estimated_completion": "2-5 minutes",
-	})
-}
-
-// AiJobStatus returns job status and results
-// @Summary AI job status
-// @Description Returns the status and results for a batch completion job
-// @Tags ai
-// @Security BearerAuth
-// @Security ApiKeyAuth
-// @Produce json
-// @Param jobId path string true "Job ID"
-// @Success 200 {object} routes.AiJobStatusResponse
-// @Router /v1/ai/jobs/{jobId} [get]
-func AiJobStatus(c *gin.Context) {
-	jobID := c.Param("jobId")
-	c.JSON(http.StatusOK, gin.H{
-		"job_id":       jobID,
-		"status":       "completed",
-		"created_at":   time.Now().Add(-3 * time.Minute).UTC().Format(time.RFC3339),
-		"completed_at": time.Now().UTC().Format(time.RFC3339),
-		"results": []gin.H{
-			{"index": 0, "completion": "Sample completion for first prompt", "status": "success"},
-			{"index": 1, "completion": "Sample completion for second prompt", "status": "success"},
-		},
-	})
-}
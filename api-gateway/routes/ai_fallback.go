@@ -0,0 +1,99 @@
+package routes
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/weltschmerz/QA-Playground/api-gateway/adapters"
+)
+
+const maxFallbackChain = 5
+
+var modelFallbacks = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "gateway_model_fallbacks_total",
+	Help: "Completions served by a fallback model, by requested and serving model.",
+}, []string{"requested", "served"})
+
+// modelAttempt records one model tried for a completion.
+type modelAttempt struct {
+	Model      string `json:"model"`
+	Outcome    string `json:"outcome"` // success|failed
+	Error      string `json:"error,omitempty"`
+	StatusCode int    `json:"status_code,omitempty"`
+}
+
+// fallbackChain returns the models to try in order: the requested model, then
+// the request's fallback list or, when the request has none, the registry's.
+// An explicit empty list disables fallback.
+func fallbackChain(model string, requested []string) []string {
+	fallback := requested
+	if fallback == nil {
+		if e, ok := adapters.DefaultRegistry.Get(model); ok {
+			fallback = e.Fallback
+		}
+	}
+	chain := []string{model}
+	seen := map[string]bool{model: true}
+	for _, f := range fallback {
+		f = strings.TrimSpace(f)
+		if f == "" || seen[f] || len(chain) == maxFallbackChain {
+			continue
+		}
+		seen[f] = true
+		chain = append(chain, f)
+	}
+	return chain
+}
+
+// fallbackEligible reports whether err should move on to the next model.
+// Non-retryable adapter statuses (e.g. 400, 413) mean the request itself is
+// bad and would fail on any model.
+func fallbackEligible(ctx context.Context, model string, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+	var statusErr *adapters.AdapterStatusError
+	if errors.As(err, &statusErr) {
+		return adapters.PolicyFor(model).Retryable(statusErr.Code)
+	}
+	return true
+}
+
+// completeWithFallback tries each model of chain until one succeeds. It returns
+// the serving model and every attempt made; err is the last failure. Models
+// whose max_tokens limit is below the request are skipped.
+func completeWithFallback(ctx context.Context, auth, failHeader string, req adapters.CompletionRequest, chain []string) (res adapters.Completion, servedBy string, attempts []modelAttempt, err error) {
+	for _, model := range chain {
+		req.Model = model
+		var adapterURL string
+		if e, ok := adapters.DefaultRegistry.Get(model); ok && e.MaxTokens > 0 && req.MaxTokens > e.MaxTokens {
+			err = fmt.Errorf("max_tokens exceeds %s limit of %d", model, e.MaxTokens)
+		} else {
+			adapterURL, err = adapters.ResolveAdapterURL(model)
+		}
+		if err == nil {
+			res, err = adapters.Complete(ctx, auth, adapterURL, failHeader, req)
+		}
+		if err == nil {
+			attempts = append(attempts, modelAttempt{Model: model, Outcome: "success"})
+			if model != chain[0] {
+				modelFallbacks.WithLabelValues(chain[0], model).Inc()
+			}
+			return res, model, attempts, nil
+		}
+		a := modelAttempt{Model: model, Outcome: "failed", Error: err.Error()}
+		var statusErr *adapters.AdapterStatusError
+		if errors.As(err, &statusErr) {
+			a.StatusCode = statusErr.Code
+		}
+		attempts = append(attempts, a)
+		if !fallbackEligible(ctx, model, err) {
+			break
+		}
+	}
+	return adapters.Completion{}, "", attempts, err
+}
@@ -0,0 +1,207 @@
+package routes
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/weltschmerz/QA-Playground/api-gateway/adapters"
+	gwmiddleware "github.com/weltschmerz/QA-Playground/api-gateway/middleware"
+)
+
+const maxStreamLine = 1 << 20
+
+// streamChunk is the subset of an adapter stream chunk the gateway inspects.
+type streamChunk struct {
+	Choices []struct {
+		Delta struct {
+			Content string `json:"content"`
+		} `json:"delta"`
+		FinishReason *string `json:"finish_reason"`
+	} `json:"choices"`
+	Usage *adapters.Usage `json:"usage,omitempty"`
+}
+
+// AiCompleteStream streams a completion as server-sent events
+// @Summary Streaming AI text completion
+// @Description Relays adapter chunks as text/event-stream. Each chunk is a data event; the stream ends with a usage event and data: [DONE]. Equivalent to POST /v1/ai/complete with stream=true.
+// @Tags ai
+// @Accept json
+// @Produce text/event-stream
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param request body routes.AiCompleteRequest true "Completion request"
+// @Success 200 {string} string "Server-sent events stream"
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 429 {object} map[string]interface{}
+// @Failure 502 {object} map[string]string
+// @Failure 503 {object} map[string]string
+// @Router /v1/ai/complete/stream [post]
+func AiCompleteStream(c *gin.Context) {
+	var body adapters.CompletionRequest
+	if err := c.BindJSON(&body); err != nil || body.Prompt == "" || body.Model == "" {
+		c.JSON(400, gin.H{"error": "invalid payload"})
+		return
+	}
+	entry, ok := adapters.DefaultRegistry.Get(body.Model)
+	if !ok {
+		c.JSON(400, gin.H{"error": adapters.ErrUnknownModel.Error()})
+		return
+	}
+	if err := validateGeneration(body, entry); err != nil {
+		c.JSON(400, gin.H{"error": err.Error(), "model": body.Model})
+		return
+	}
+	if !withinQuota(c) {
+		return
+	}
+	streamCompletion(c, body)
+}
+
+// streamCompletion opens an adapter stream and relays it to the client. The
+// upstream request is bound to the client request context, so a client
+// disconnect cancels it.
+func streamCompletion(c *gin.Context, req adapters.CompletionRequest) {
+	prompt, model := req.Prompt, req.Model
+	upstream, status, err := openStream(c, req)
+	if errors.Is(err, adapters.ErrCircuitOpen) {
+		circuitOpen(c, model)
+		return
+	}
+	if err != nil {
+		c.JSON(status, gin.H{"error": err.Error(), "model": model})
+		return
+	}
+	defer upstream.Close()
+	startSSE(c)
+
+	text, finishReason, reported, err := readAdapterStream(upstream, func(data []byte, _ streamChunk) error {
+		return writeSSE(c, "", data)
+	})
+	usage := estimateUsage(prompt, text)
+	if reported != nil {
+		usage = *reported
+	}
+	recordUsage(gwmiddleware.CallerSubject(c), model, c.FullPath(), usage)
+	if c.Request.Context().Err() != nil {
+		// client went away; the upstream request is already cancelled
+		return
+	}
+	if err != nil {
+		msg, _ := json.Marshal(gin.H{"error": "upstream stream failed: " + err.Error(), "model": model})
+		_ = writeSSE(c, "error", msg)
+		return
+	}
+	summary := gin.H{
+		"model":             model,
+		"prompt_tokens":     usage.PromptTokens,
+		"completion_tokens": usage.CompletionTokens,
+		"total_tokens":      usage.TotalTokens,
+	}
+	if finishReason != "" {
+		summary["finish_reason"] = finishReason
+	}
+	final, _ := json.Marshal(summary)
+	if writeSSE(c, "usage", final) != nil {
+		return
+	}
+	_ = writeSSE(c, "", []byte("[DONE]"))
+}
+
+// openStream checks that the model can stream and opens the adapter stream.
+// On failure it returns the HTTP status to answer with.
+func openStream(c *gin.Context, req adapters.CompletionRequest) (io.ReadCloser, int, error) {
+	entry, ok := adapters.DefaultRegistry.Get(req.Model)
+	if !ok {
+		return nil, http.StatusBadRequest, adapters.ErrUnknownModel
+	}
+	if !entry.HasCapability("streaming") {
+		return nil, http.StatusBadRequest, errors.New("model does not support streaming")
+	}
+	adapterURL, err := adapters.ResolveAdapterURL(req.Model)
+	if errors.Is(err, adapters.ErrModelUnavailable) {
+		return nil, http.StatusServiceUnavailable, err
+	}
+	if err != nil {
+		return nil, http.StatusBadRequest, err
+	}
+	upstream, err := adapters.OpenAdapterStream(c.Request.Context(), c.GetHeader("Authorization"), adapterURL, c.GetHeader("x-test-fail"), req)
+	if errors.Is(err, adapters.ErrCircuitOpen) {
+		return nil, http.StatusServiceUnavailable, err
+	}
+	if err != nil {
+		return nil, http.StatusBadGateway, err
+	}
+	return upstream, http.StatusOK, nil
+}
+
+func startSSE(c *gin.Context) {
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+	c.Writer.Flush()
+}
+
+// readAdapterStream calls fn with every data event of an adapter stream until
+// [DONE] or EOF. It returns the concatenated text, the finish reason and the
+// usage if the adapter reported one.
+func readAdapterStream(r io.Reader, fn func(data []byte, chunk streamChunk) error) (string, string, *adapters.Usage, error) {
+	var text strings.Builder
+	var usage *adapters.Usage
+	finishReason := ""
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxStreamLine)
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if !bytes.HasPrefix(line, []byte("data:")) {
+			continue
+		}
+		data := bytes.TrimSpace(bytes.TrimPrefix(line, []byte("data:")))
+		if len(data) == 0 {
+			continue
+		}
+		if string(data) == "[DONE]" {
+			break
+		}
+		var chunk streamChunk
+		if json.Unmarshal(data, &chunk) == nil {
+			for _, ch := range chunk.Choices {
+				text.WriteString(ch.Delta.Content)
+				if ch.FinishReason != nil {
+					finishReason = *ch.FinishReason
+				}
+			}
+			if chunk.Usage != nil {
+				usage = chunk.Usage
+			}
+		}
+		if err := fn(data, chunk); err != nil {
+			return text.String(), finishReason, usage, err
+		}
+	}
+	return text.String(), finishReason, usage, scanner.Err()
+}
+
+// writeSSE writes a single event and flushes it to the client.
+func writeSSE(c *gin.Context, event string, data []byte) error {
+	var buf bytes.Buffer
+	if event != "" {
+		buf.WriteString("event: " + event + "\n")
+	}
+	buf.WriteString("data: ")
+	buf.Write(data)
+	buf.WriteString("\n\n")
+	if _, err := c.Writer.Write(buf.Bytes()); err != nil {
+		return err
+	}
+	c.Writer.Flush()
+	return nil
+}
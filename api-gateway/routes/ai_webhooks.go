@@ -0,0 +1,216 @@
+package routes
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/weltschmerz/QA-Playground/api-gateway/utils"
+)
+
+// Webhook retry tuning; vars so they can be shortened in tests
+var (
+	webhookMaxAttempts = 5
+	webhookBaseBackoff = 500 * time.Millisecond
+	webhookMaxBackoff  = 30 * time.Second
+	webhookTimeout     = 5 * time.Second
+)
+
+type webhookAttempt struct {
+	DeliveryID  string `json:"delivery_id"`
+	Attempt     int    `json:"attempt"`
+	URL         string `json:"url"`
+	StatusCode  int    `json:"status_code,omitempty"`
+	Error       string `json:"error,omitempty"`
+	Success     bool   `json:"success"`
+	DurationMs  int64  `json:"duration_ms"`
+	AttemptedAt string `json:"attempted_at"`
+	Redelivery  bool   `json:"redelivery,omitempty"`
+}
+
+func webhookSecret() string {
+	if s := os.Getenv("WEBHOOK_SECRET"); s != "" {
+		return s
+	}
+	return "dev-webhook-secret"
+}
+
+// signWebhook returns the hex HMAC-SHA256 of body, sent as "sha256=<hex>".
+func signWebhook(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func validCallbackURL(raw string) bool {
+	u, err := url.Parse(raw)
+	return err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
+
+func retryableWebhookStatus(code int) bool {
+	return code >= 500 || code == http.StatusRequestTimeout || code == http.StatusTooManyRequests
+}
+
+// queueJobWebhook snapshots the finished job as the webhook payload and starts
+// delivery. Caller holds jobMu.
+func queueJobWebhook(job *batchJob) {
+	if job.CallbackURL == "" {
+		return
+	}
+	view := jobView(job)
+	// the delivery state of this very webhook means nothing to its receiver
+	delete(view, "webhook_state")
+	payload, _ := json.Marshal(gin.H{
+		"event": "batch." + job.Status,
+		"job":   view,
+	})
+	job.webhookPayload = payload
+	job.webhookState = "pending"
+	go deliverJobWebhook(job.ID, "dlv-"+utils.GenID()[:12], job.CallbackURL, payload, false)
+}
+
+// deliverJobWebhook POSTs payload with retry and exponential backoff, logging
+// every attempt on the job.
+func deliverJobWebhook(jobID, deliveryID, target string, payload []byte, redelivery bool) {
+	client := &http.Client{Timeout: webhookTimeout}
+	signature := "sha256=" + signWebhook(webhookSecret(), payload)
+	event := "batch"
+	var head struct {
+		Event string `json:"event"`
+	}
+	if json.Unmarshal(payload, &head) == nil && head.Event != "" {
+		event = head.Event
+	}
+
+	backoff := webhookBaseBackoff
+	for attempt := 1; attempt <= webhookMaxAttempts; attempt++ {
+		rec := webhookAttempt{
+			DeliveryID:  deliveryID,
+			Attempt:     attempt,
+			URL:         target,
+			AttemptedAt: time.Now().UTC().Format(time.RFC3339),
+			Redelivery:  redelivery,
+		}
+		start := time.Now()
+		retry := true
+		req, err := http.NewRequest(http.MethodPost, target, bytes.NewReader(payload))
+		if err == nil {
+			req.Header.Set("content-type", "application/json")
+			req.Header.Set("X-Webhook-Event", event)
+			req.Header.Set("X-Webhook-Delivery", deliveryID)
+			req.Header.Set("X-Webhook-Attempt", strconv.Itoa(attempt))
+			req.Header.Set("X-Webhook-Signature", signature)
+			var resp *http.Response
+			resp, err = client.Do(req)
+			if err == nil {
+				resp.Body.Close()
+				rec.StatusCode = resp.StatusCode
+				rec.Success = resp.StatusCode >= 200 && resp.StatusCode < 300
+				retry = !rec.Success && retryableWebhookStatus(resp.StatusCode)
+			}
+		} else {
+			retry = false
+		}
+		if err != nil {
+			rec.Error = err.Error()
+		}
+		rec.DurationMs = time.Since(start).Milliseconds()
+
+		jobMu.Lock()
+		job, ok := jobStore[jobID]
+		if ok {
+			job.deliveries = append(job.deliveries, rec)
+			switch {
+			case rec.Success:
+				job.webhookState = "delivered"
+			case !retry || attempt == webhookMaxAttempts:
+				job.webhookState = "failed"
+			}
+		}
+		jobMu.Unlock()
+		if !ok || rec.Success || !retry {
+			return
+		}
+		time.Sleep(backoff)
+		backoff *= 2
+		if backoff > webhookMaxBackoff {
+			backoff = webhookMaxBackoff
+		}
+	}
+}
+
+// AiJobDeliveries returns the webhook delivery log for a batch job
+// @Summary AI job webhook deliveries
+// @Description Returns every callback delivery attempt for a batch job
+// @Tags ai
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Produce json
+// @Param jobId path string true "Job ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 404 {object} map[string]string
+// @Router /v1/ai/jobs/{jobId}/deliveries [get]
+func AiJobDeliveries(c *gin.Context) {
+	jobMu.RLock()
+	defer jobMu.RUnlock()
+	job, ok := visibleJob(c)
+	if !ok {
+		return
+	}
+	deliveries := make([]webhookAttempt, len(job.deliveries))
+	copy(deliveries, job.deliveries)
+	c.JSON(http.StatusOK, gin.H{
+		"job_id":       job.ID,
+		"callback_url": job.CallbackURL,
+		"state":        job.webhookState,
+		"deliveries":   deliveries,
+	})
+}
+
+// AiRedeliverJobWebhook re-sends the completion webhook for a finished job
+// @Summary Redeliver AI job webhook
+// @Description Sends the stored completion payload to the job's callback_url again as a new delivery; 409 while a delivery is still in progress
+// @Tags ai
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Produce json
+// @Param jobId path string true "Job ID"
+// @Success 202 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /v1/ai/jobs/{jobId}/redeliver [post]
+func AiRedeliverJobWebhook(c *gin.Context) {
+	jobMu.Lock()
+	defer jobMu.Unlock()
+	job, ok := visibleJob(c)
+	if !ok {
+		return
+	}
+	if job.CallbackURL == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "job has no callback_url"})
+		return
+	}
+	if job.webhookPayload == nil {
+		c.JSON(http.StatusConflict, gin.H{"error": "job not finished", "status": job.Status})
+		return
+	}
+	// one delivery at a time, so a late failure of the previous one cannot
+	// overwrite the state of this one
+	if job.webhookState == "pending" {
+		c.JSON(http.StatusConflict, gin.H{"error": "webhook delivery in progress", "state": job.webhookState})
+		return
+	}
+	deliveryID := "dlv-" + utils.GenID()[:12]
+	job.webhookState = "pending"
+	go deliverJobWebhook(job.ID, deliveryID, job.CallbackURL, job.webhookPayload, true)
+	c.JSON(http.StatusAccepted, gin.H{"job_id": job.ID, "delivery_id": deliveryID, "state": job.webhookState})
+}
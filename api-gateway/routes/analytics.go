@@ -2,6 +2,7 @@ package routes
 
 import (
 	"net/http"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -13,7 +14,7 @@ import (
 // GetUsageAnalytics returns usage analytics aligned with tests
 // GetUsageAnalytics returns usage analytics with optional filters
 // @Summary Get usage analytics
-// @Description Returns usage analytics with optional date range, endpoint filter, and grouping
+// @Description Returns request counts from the gateway request log and token usage from the usage ledger, with optional date range, endpoint prefix filter, and grouping
 // @Tags analytics
 // @Security BearerAuth
 // @Security ApiKeyAuth
@@ -31,63 +32,138 @@ func GetUsageAnalytics(c *gin.Context) {
 	endpoint := strings.TrimSpace(c.Query("endpoint"))
 	groupBy := strings.TrimSpace(c.Query("group_by"))
 
-	var startT, endT time.Time
-	var err error
-	if start != "" {
-		startT, err = time.Parse(time.RFC3339, start)
-		if err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid date format"})
-			return
-		}
+	startT, endT, ok := parseDateRange(c)
+	if !ok {
+		return
 	}
-	if end != "" {
-		endT, err = time.Parse(time.RFC3339, end)
-		if err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid date format"})
-			return
+	var bucket func(time.Time) time.Time
+	switch groupBy {
+	case "":
+	case "hour":
+		bucket = func(t time.Time) time.Time { return t.Truncate(time.Hour) }
+	case "day":
+		bucket = func(t time.Time) time.Time { return t.Truncate(24 * time.Hour) }
+	case "week":
+		bucket = func(t time.Time) time.Time {
+			d := t.Truncate(24 * time.Hour)
+			return d.AddDate(0, 0, -int((d.Weekday()+6)%7)) // Monday
 		}
-	}
-	if !startT.IsZero() && !endT.IsZero() && endT.Before(startT) {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid date range"})
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid group_by"})
 		return
 	}
 
-	// Defaults
-	total := 1000
-	unique := 50
-	// If both dates are in the future, return zero data
-	now := time.Now().UTC()
-	if !startT.IsZero() && !endT.IsZero() && startT.After(now) && endT.After(now) {
-		total = 0
-		unique = 0
+	type seriesPoint struct {
+		requests, tokens int
+		users            map[string]bool
+	}
+	series := map[time.Time]*seriesPoint{}
+	point := func(t time.Time) *seriesPoint {
+		k := bucket(t)
+		if series[k] == nil {
+			series[k] = &seriesPoint{users: map[string]bool{}}
+		}
+		return series[k]
+	}
+
+	total := 0
+	users := map[string]bool{}
+	byEndpoint := map[string]int{}
+	byMethod := map[string]int{}
+	var tokens usageTotals
+	tokensByModel := map[string]*usageTotals{}
+
+	usageMu.RLock()
+	for _, r := range requestLog {
+		route := r.Route
+		if route == "" {
+			route = r.Path
+		}
+		if !inRange(r.At, startT, endT) || (endpoint != "" && !strings.HasPrefix(route, endpoint)) {
+			continue
+		}
+		total++
+		byEndpoint[route]++
+		byMethod[r.Method]++
+		if r.Subject != "" {
+			users[r.Subject] = true
+		}
+		if bucket != nil {
+			p := point(r.At)
+			p.requests++
+			if r.Subject != "" {
+				p.users[r.Subject] = true
+			}
+		}
 	}
+	for _, r := range tokenLedger {
+		if !inRange(r.At, startT, endT) || (endpoint != "" && !strings.HasPrefix(r.Endpoint, endpoint)) {
+			continue
+		}
+		tokens.add(r.Usage)
+		if tokensByModel[r.Model] == nil {
+			tokensByModel[r.Model] = &usageTotals{}
+		}
+		tokensByModel[r.Model].add(r.Usage)
+		if bucket != nil {
+			point(r.At).tokens += r.Usage.TotalTokens
+		}
+	}
+	usageMu.RUnlock()
+
 	resp := gin.H{
 		"total_requests":       total,
-		"unique_users":         unique,
-		"requests_by_endpoint": []gin.H{{"endpoint": "/v1/ai/complete", "count": minInt(total, 200)}},
-		"requests_by_method":   []gin.H{{"method": "GET", "count": minInt(total, 600)}, {"method": "POST", "count": minInt(total, 400)}},
+		"unique_users":         len(users),
+		"requests_by_endpoint": countsDesc(byEndpoint, "endpoint"),
+		"requests_by_method":   countsDesc(byMethod, "method"),
+		"token_usage":          tokens,
+		"tokens_by_model":      tokensByModel,
 		"time_period":          gin.H{"start": start, "end": end},
 		"generated_at":         time.Now().UTC().Format(time.RFC3339),
 	}
 	if endpoint != "" {
 		resp["endpoint_filter"] = endpoint
 	}
-	if groupBy != "" {
-		// Simulate a simple timeseries
-		series := []gin.H{}
-		base := time.Now().UTC().Add(-1 * time.Hour)
-		for i := 0; i < 5; i++ {
-			series = append(series, gin.H{
-				"timestamp":    base.Add(time.Duration(i) * time.Minute).Format(time.RFC3339),
-				"requests":     10 + i,
-				"unique_users": 5 + i%3,
+	if bucket != nil {
+		keys := make([]time.Time, 0, len(series))
+		for k := range series {
+			keys = append(keys, k)
+		}
+		sort.Slice(keys, func(i, j int) bool { return keys[i].Before(keys[j]) })
+		points := make([]gin.H, 0, len(keys))
+		for _, k := range keys {
+			p := series[k]
+			points = append(points, gin.H{
+				"timestamp":    k.Format(time.RFC3339),
+				"requests":     p.requests,
+				"unique_users": len(p.users),
+				"total_tokens": p.tokens,
 			})
 		}
-		resp["time_series"] = series
+		resp["time_series"] = points
 	}
 	c.JSON(http.StatusOK, resp)
 }
 
+// countsDesc turns a count map into [{key: k, count: n}] sorted by count, then key.
+func countsDesc(counts map[string]int, key string) []gin.H {
+	keys := make([]string, 0, len(counts))
+	for k := range counts {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if counts[keys[i]] != counts[keys[j]] {
+			return counts[keys[i]] > counts[keys[j]]
+		}
+		return keys[i] < keys[j]
+	})
+	out := make([]gin.H, 0, len(keys))
+	for _, k := range keys {
+		out = append(out, gin.H{key: k, "count": counts[k]})
+	}
+	return out
+}
+
 // GetPerformanceMetrics returns system performance metrics per tests
 // GetPerformanceMetrics returns performance metrics for endpoints
 // @Summary Get performance metrics
@@ -275,7 +351,20 @@ func TrackEvent(c *gin.Context) {
 			return
 		}
 	}
-	c.JSON(http.StatusCreated, gin.H{"event_id": utils.GenID(), "status": "recorded", "timestamp": ts, "event_type": event.EventType, "event_name": event.EventName})
+	resp := gin.H{"event_id": utils.GenID(), "status": "recorded", "timestamp": ts, "event_type": event.EventType, "event_name": event.EventName}
+	// start workflows with a matching event trigger (see workflow_events.go)
+	if started := dispatchEvent(eventSourceAnalytics, gin.H{
+		"event_id":   resp["event_id"],
+		"event_type": event.EventType,
+		"event_name": event.EventName,
+		"user_id":    event.UserID,
+		"timestamp":  ts,
+		"properties": event.Properties,
+		"metadata":   event.Metadata,
+	}, c.GetHeader("Idempotency-Key")); len(started) > 0 {
+		resp["triggered_executions"] = started
+	}
+	c.JSON(http.StatusCreated, resp)
 }
 
 // TrackEventBatch records multiple analytics events
@@ -299,11 +388,3 @@ func TrackEventBatch(c *gin.Context) {
 	}
 	c.JSON(http.StatusAccepted, gin.H{"batch_id": utils.GenID(), "events_received": len(payload.Events), "status": "processing"})
 }
-
-// small helper used above
-func minInt(a, b int) int {
-	if a < b {
-		return a
-	}
-	return b
-}
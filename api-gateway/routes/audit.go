@@ -27,6 +27,8 @@ type AuditLog struct {
 	// Optional additional fields used by integration tests
 	EventType string         `json:"event_type,omitempty"`
 	Metadata  map[string]any `json:"metadata,omitempty"`
+	// Workflow executions this entry started, only in the create response
+	TriggeredExecutions []gin.H `json:"triggered_executions,omitempty" swaggertype:"array,object"`
 }
 
 var auditLogs = []AuditLog{}
@@ -300,5 +302,7 @@ func CreateAuditLog(c *gin.Context) {
 		EventType:    payload.EventType,
 	}
 	auditLogs = append(auditLogs, entry)
+	// start workflows with a matching event trigger (see workflow_events.go)
+	entry.TriggeredExecutions = dispatchEvent(eventSourceAudit, entry, c.GetHeader("Idempotency-Key"))
 	c.JSON(http.StatusCreated, entry)
 }
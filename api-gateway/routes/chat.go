@@ -0,0 +1,294 @@
+package routes
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/weltschmerz/QA-Playground/api-gateway/adapters"
+	gwmiddleware "github.com/weltschmerz/QA-Playground/api-gateway/middleware"
+	"github.com/weltschmerz/QA-Playground/api-gateway/utils"
+)
+
+// stopList accepts OpenAI's "stop" as either a string or an array of strings.
+type stopList []string
+
+func (s *stopList) UnmarshalJSON(b []byte) error {
+	var one string
+	if err := json.Unmarshal(b, &one); err == nil {
+		if one != "" {
+			*s = stopList{one}
+		}
+		return nil
+	}
+	var many []string
+	if err := json.Unmarshal(b, &many); err != nil {
+		return errors.New("stop must be a string or an array of strings")
+	}
+	*s = many
+	return nil
+}
+
+type chatCompletionRequest struct {
+	Model         string                 `json:"model"`
+	Messages      []adapters.ChatMessage `json:"messages"`
+	Temperature   *float64               `json:"temperature"`
+	TopP          *float64               `json:"top_p"`
+	MaxTokens     int                    `json:"max_tokens"`
+	Stop          stopList               `json:"stop"`
+	Stream        bool                   `json:"stream"`
+	StreamOptions *struct {
+		IncludeUsage bool `json:"include_usage"`
+	} `json:"stream_options"`
+	N    int    `json:"n"`
+	User string `json:"user"`
+}
+
+// openAIError answers in the OpenAI error envelope so SDK clients surface the message.
+func openAIError(c *gin.Context, status int, message, typ string) {
+	c.JSON(status, gin.H{"error": gin.H{"message": message, "type": typ, "param": nil, "code": nil}})
+}
+
+// completionRequest flattens a chat into a single prompt for adapters without
+// native chat: system messages become the system prompt, a lone user turn is
+// sent as-is and longer conversations as a role-prefixed transcript.
+func (r chatCompletionRequest) completionRequest() adapters.CompletionRequest {
+	var system []string
+	var turns []adapters.ChatMessage
+	for _, m := range r.Messages {
+		if m.Role == "system" {
+			system = append(system, m.Content)
+		} else {
+			turns = append(turns, m)
+		}
+	}
+	prompt := ""
+	if len(turns) == 1 {
+		prompt = turns[0].Content
+	} else {
+		var b strings.Builder
+		for _, m := range turns {
+			b.WriteString(m.Role + ": " + m.Content + "\n")
+		}
+		b.WriteString("assistant:")
+		prompt = b.String()
+	}
+	return adapters.CompletionRequest{
+		Prompt:        prompt,
+		Model:         r.Model,
+		Temperature:   r.Temperature,
+		MaxTokens:     r.MaxTokens,
+		TopP:          r.TopP,
+		SystemPrompt:  strings.Join(system, "\n"),
+		StopSequences: r.Stop,
+	}
+}
+
+// ChatCompletions is an OpenAI-compatible chat endpoint
+// @Summary OpenAI-compatible chat completion
+// @Description Accepts the OpenAI chat.completions request format and routes by model. Models with the chat capability are called natively; others are translated to /complete. With stream=true the response is a stream of chat.completion.chunk events ending with data: [DONE].
+// @Tags ai
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param request body routes.ChatCompletionRequest true "Chat completion request"
+// @Success 200 {object} adapters.ChatResponse
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 429 {object} map[string]interface{}
+// @Failure 502 {object} map[string]interface{}
+// @Failure 503 {object} map[string]interface{}
+// @Router /v1/chat/completions [post]
+func ChatCompletions(c *gin.Context) {
+	var req chatCompletionRequest
+	if err := c.BindJSON(&req); err != nil {
+		openAIError(c, http.StatusBadRequest, "invalid request body: "+err.Error(), "invalid_request_error")
+		return
+	}
+	if req.Model == "" || len(req.Messages) == 0 {
+		openAIError(c, http.StatusBadRequest, "model and messages are required", "invalid_request_error")
+		return
+	}
+	for _, m := range req.Messages {
+		if m.Role != "system" && m.Role != "user" && m.Role != "assistant" {
+			openAIError(c, http.StatusBadRequest, "unsupported message role: "+m.Role, "invalid_request_error")
+			return
+		}
+	}
+	if req.N > 1 {
+		openAIError(c, http.StatusBadRequest, "n > 1 is not supported", "invalid_request_error")
+		return
+	}
+	entry, ok := adapters.DefaultRegistry.Get(req.Model)
+	if !ok {
+		openAIError(c, http.StatusNotFound, "The model '"+req.Model+"' does not exist", "invalid_request_error")
+		return
+	}
+	creq := req.completionRequest()
+	if err := validateGeneration(creq, entry); err != nil {
+		openAIError(c, http.StatusBadRequest, err.Error(), "invalid_request_error")
+		return
+	}
+	if qe := checkQuota(gwmiddleware.CallerSubject(c)); qe != nil {
+		c.Header("Retry-After", qe.retryAfter())
+		openAIError(c, http.StatusTooManyRequests, fmt.Sprintf("You exceeded your %s token quota of %d; it resets at %s", qe.Period, qe.Limit, qe.ResetsAt), "insufficient_quota")
+		return
+	}
+	if req.Stream {
+		streamChat(c, req, creq)
+		return
+	}
+
+	var res adapters.ChatResponse
+	var err error
+	if entry.HasCapability("chat") {
+		var adapterURL string
+		adapterURL, err = adapters.ResolveAdapterURL(req.Model)
+		if err == nil {
+			res, err = adapters.Chat(c.Request.Context(), c.GetHeader("Authorization"), adapterURL, c.GetHeader("x-test-fail"), adapters.ChatRequest{
+				Model:       req.Model,
+				Messages:    req.Messages,
+				Temperature: req.Temperature,
+				TopP:        req.TopP,
+				MaxTokens:   req.MaxTokens,
+				Stop:        req.Stop,
+			})
+		}
+	} else {
+		var out adapters.Completion
+		out, _, _, err = completeWithFallback(c.Request.Context(), c.GetHeader("Authorization"), c.GetHeader("x-test-fail"), creq, []string{req.Model})
+		if err == nil {
+			res = adapters.ChatResponse{
+				Choices: []adapters.ChatChoice{{
+					Message:      adapters.ChatMessage{Role: "assistant", Content: out.Text},
+					FinishReason: out.FinishReason,
+				}},
+				Usage: out.Usage,
+			}
+		}
+	}
+	if err != nil {
+		status := http.StatusBadGateway
+		if errors.Is(err, adapters.ErrModelUnavailable) || errors.Is(err, adapters.ErrCircuitOpen) {
+			status = http.StatusServiceUnavailable
+		}
+		openAIError(c, status, err.Error(), "api_error")
+		return
+	}
+	if res.Usage.TotalTokens == 0 && len(res.Choices) > 0 {
+		res.Usage = estimateUsage(creq.Prompt, res.Choices[0].Message.Content)
+	}
+	recordUsage(gwmiddleware.CallerSubject(c), req.Model, c.FullPath(), res.Usage)
+	if res.ID == "" {
+		res.ID = "chatcmpl-" + utils.GenID()[:24]
+	}
+	if res.Created == 0 {
+		res.Created = time.Now().Unix()
+	}
+	res.Object = "chat.completion"
+	res.Model = req.Model
+	for i := range res.Choices {
+		res.Choices[i].Index = i
+		if res.Choices[i].FinishReason == "" {
+			res.Choices[i].FinishReason = "stop"
+		}
+	}
+	c.JSON(http.StatusOK, res)
+}
+
+// streamChat relays an adapter stream as OpenAI chat.completion.chunk events.
+func streamChat(c *gin.Context, req chatCompletionRequest, creq adapters.CompletionRequest) {
+	upstream, status, err := openStream(c, creq)
+	if err != nil {
+		typ := "api_error"
+		if status == http.StatusBadRequest {
+			typ = "invalid_request_error"
+		}
+		openAIError(c, status, err.Error(), typ)
+		return
+	}
+	defer upstream.Close()
+	startSSE(c)
+
+	id := "chatcmpl-" + utils.GenID()[:24]
+	created := time.Now().Unix()
+	chunk := func(delta gin.H, finish any) []byte {
+		b, _ := json.Marshal(gin.H{
+			"id":      id,
+			"object":  "chat.completion.chunk",
+			"created": created,
+			"model":   req.Model,
+			"choices": []gin.H{{"index": 0, "delta": delta, "finish_reason": finish}},
+		})
+		return b
+	}
+	if writeSSE(c, "", chunk(gin.H{"role": "assistant", "content": ""}, nil)) != nil {
+		return
+	}
+	text, finishReason, reported, err := readAdapterStream(upstream, func(_ []byte, ch streamChunk) error {
+		for _, choice := range ch.Choices {
+			if choice.Delta.Content == "" {
+				continue
+			}
+			if err := writeSSE(c, "", chunk(gin.H{"content": choice.Delta.Content}, nil)); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	usage := estimateUsage(creq.Prompt, text)
+	if reported != nil {
+		usage = *reported
+	}
+	recordUsage(gwmiddleware.CallerSubject(c), req.Model, c.FullPath(), usage)
+	if c.Request.Context().Err() != nil {
+		return
+	}
+	if err != nil {
+		msg, _ := json.Marshal(gin.H{"error": gin.H{"message": "upstream stream failed: " + err.Error(), "type": "api_error"}})
+		_ = writeSSE(c, "", msg)
+		return
+	}
+	if finishReason == "" {
+		finishReason = "stop"
+	}
+	if writeSSE(c, "", chunk(gin.H{}, finishReason)) != nil {
+		return
+	}
+	if req.StreamOptions != nil && req.StreamOptions.IncludeUsage {
+		b, _ := json.Marshal(gin.H{
+			"id":      id,
+			"object":  "chat.completion.chunk",
+			"created": created,
+			"model":   req.Model,
+			"choices": []gin.H{},
+			"usage":   usage,
+		})
+		if writeSSE(c, "", b) != nil {
+			return
+		}
+	}
+	_ = writeSSE(c, "", []byte("[DONE]"))
+}
+
+// ListOpenAIModels lists registry models in the OpenAI /v1/models format
+// @Summary OpenAI-compatible model list
+// @Tags ai
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Router /v1/models [get]
+func ListOpenAIModels(c *gin.Context) {
+	entries := adapters.DefaultRegistry.List()
+	data := make([]gin.H, 0, len(entries))
+	for _, m := range entries {
+		data = append(data, gin.H{"id": m.Name, "object": "model", "created": 0, "owned_by": "qa-playground"})
+	}
+	c.JSON(http.StatusOK, gin.H{"object": "list", "data": data})
+}
@@ -0,0 +1,150 @@
+package routes
+
+import (
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// schedClock is the time source of the schedulers (workflow triggers and
+// scheduled notification broadcasts). It follows the wall clock shifted by an
+// offset, or stands still while frozen, so tests can drive scheduled work
+// through /v1/admin/clock (moving it requires ENABLE_TEST_CLOCK=true).
+var schedClock = &clock{}
+
+type clock struct {
+	mu     sync.RWMutex
+	offset time.Duration
+	frozen *time.Time
+	subs   []chan struct{}
+}
+
+// Now returns the scheduler's current time in UTC.
+func (c *clock) Now() time.Time {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.nowLocked()
+}
+
+func (c *clock) nowLocked() time.Time {
+	if c.frozen != nil {
+		return *c.frozen
+	}
+	return time.Now().UTC().Add(c.offset)
+}
+
+// subscribe returns a channel that is signalled whenever the clock is moved,
+// so schedulers can re-check without waiting for their next tick.
+func (c *clock) subscribe() <-chan struct{} {
+	ch := make(chan struct{}, 1)
+	c.mu.Lock()
+	c.subs = append(c.subs, ch)
+	c.mu.Unlock()
+	return ch
+}
+
+// set moves the clock to now, keeping it frozen or running. Caller holds c.mu.
+func (c *clock) set(now time.Time, frozen bool) {
+	now = now.UTC()
+	if frozen {
+		c.frozen = &now
+	} else {
+		c.frozen = nil
+		c.offset = now.Sub(time.Now().UTC())
+	}
+	for _, ch := range c.subs {
+		select {
+		case ch <- struct{}{}:
+		default:
+		}
+	}
+}
+
+func (c *clock) view() gin.H {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return gin.H{
+		"now":       c.nowLocked().Format(time.RFC3339Nano),
+		"frozen":    c.frozen != nil,
+		"offset_ms": c.offset.Milliseconds(),
+	}
+}
+
+// GetClock returns the scheduler clock
+// @Summary Get scheduler clock
+// @Description Current time of the clock that drives workflow triggers and scheduled broadcasts, and whether it is frozen or shifted from the wall clock
+// @Tags admin
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Router /v1/admin/clock [get]
+func GetClock(c *gin.Context) {
+	c.JSON(http.StatusOK, schedClock.view())
+}
+
+// SetClock moves the scheduler clock
+// @Summary Set scheduler clock
+// @Description Sets the clock to `now` (RFC3339), moves it by `advance_seconds`, and/or freezes or releases it with `frozen`. Triggers and broadcasts that fall due are fired immediately. Only registered when the gateway runs with ENABLE_TEST_CLOCK=true.
+// @Tags admin
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Router /v1/admin/clock [put]
+func SetClock(c *gin.Context) {
+	var payload struct {
+		Now            string   `json:"now"`
+		AdvanceSeconds *float64 `json:"advance_seconds"`
+		Frozen         *bool    `json:"frozen"`
+	}
+	if err := c.BindJSON(&payload); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid payload"})
+		return
+	}
+	var at time.Time
+	if payload.Now != "" {
+		t, err := time.Parse(time.RFC3339, payload.Now)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "now must be an RFC3339 timestamp"})
+			return
+		}
+		at = t
+	}
+	schedClock.mu.Lock()
+	if at.IsZero() {
+		at = schedClock.nowLocked()
+	}
+	if payload.AdvanceSeconds != nil {
+		at = at.Add(time.Duration(*payload.AdvanceSeconds * float64(time.Second)))
+	}
+	frozen := schedClock.frozen != nil
+	if payload.Frozen != nil {
+		frozen = *payload.Frozen
+	}
+	schedClock.set(at, frozen)
+	schedClock.mu.Unlock()
+	c.JSON(http.StatusOK, schedClock.view())
+}
+
+// ResetClock returns the scheduler clock to wall time
+// @Summary Reset scheduler clock
+// @Description Only registered when the gateway runs with ENABLE_TEST_CLOCK=true.
+// @Tags admin
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Failure 403 {object} map[string]string
+// @Router /v1/admin/clock [delete]
+func ResetClock(c *gin.Context) {
+	schedClock.mu.Lock()
+	schedClock.set(time.Now().UTC(), false)
+	schedClock.mu.Unlock()
+	c.JSON(http.StatusOK, schedClock.view())
+}
@@ -0,0 +1,242 @@
+package routes
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+// schemaError is one validation failure, located by a JSON pointer (RFC 6901)
+// into the validated document.
+type schemaError struct {
+	Pointer string `json:"pointer"`
+	Message string `json:"message"`
+}
+
+// jsonSchema validates decoded JSON against a JSON Schema document. It covers
+// the keywords the gateway's own schemas use: $ref (local), type, const,
+// enum, required, properties, additionalProperties, items, minItems,
+// uniqueItems, minLength, pattern, minimum, maximum, allOf, anyOf and
+// if/then. Unknown keywords are ignored.
+type jsonSchema struct {
+	root     map[string]any
+	mu       sync.Mutex
+	patterns map[string]*regexp.Regexp
+}
+
+func mustParseSchema(raw []byte) *jsonSchema {
+	var root map[string]any
+	if err := json.Unmarshal(raw, &root); err != nil {
+		panic(fmt.Sprintf("invalid JSON schema: %v", err))
+	}
+	return &jsonSchema{root: root, patterns: map[string]*regexp.Regexp{}}
+}
+
+// Validate returns every violation in doc, ordered by pointer.
+func (s *jsonSchema) Validate(doc any) []schemaError {
+	errs := s.check(s.root, doc, "")
+	sort.SliceStable(errs, func(i, j int) bool { return errs[i].Pointer < errs[j].Pointer })
+	return errs
+}
+
+func (s *jsonSchema) check(schema map[string]any, v any, ptr string) []schemaError {
+	var errs []schemaError
+	fail := func(format string, args ...any) {
+		errs = append(errs, schemaError{Pointer: ptr, Message: fmt.Sprintf(format, args...)})
+	}
+	if ref, ok := schema["$ref"].(string); ok {
+		errs = append(errs, s.check(s.resolve(ref), v, ptr)...)
+	}
+	if t, ok := schema["type"]; ok && !typeMatches(t, v) {
+		fail("must be of type %s", typeNames(t))
+		return errs // the remaining keywords would only repeat this
+	}
+	if c, ok := schema["const"]; ok && !reflect.DeepEqual(c, v) {
+		fail("must be %s", compactJSON(c))
+	}
+	if enum, ok := schema["enum"].([]any); ok && !containsValue(enum, v) {
+		fail("must be one of %s", compactJSON(enum))
+	}
+
+	switch t := v.(type) {
+	case string:
+		if n, ok := schema["minLength"].(float64); ok && utf8.RuneCountInString(t) < int(n) {
+			fail("must be at least %d characters", int(n))
+		}
+		if p, ok := schema["pattern"].(string); ok && !s.pattern(p).MatchString(t) {
+			fail("must match pattern %s", p)
+		}
+	case float64:
+		if n, ok := schema["minimum"].(float64); ok && t < n {
+			fail("must be >= %v", n)
+		}
+		if n, ok := schema["maximum"].(float64); ok && t > n {
+			fail("must be <= %v", n)
+		}
+	case []any:
+		if n, ok := schema["minItems"].(float64); ok && len(t) < int(n) {
+			fail("must have at least %d items", int(n))
+		}
+		if u, _ := schema["uniqueItems"].(bool); u {
+			for i := range t {
+				for j := 0; j < i; j++ {
+					if reflect.DeepEqual(t[i], t[j]) {
+						errs = append(errs, schemaError{Pointer: ptr + "/" + fmt.Sprint(i), Message: fmt.Sprintf("duplicates item %d", j)})
+					}
+				}
+			}
+		}
+		if items, ok := schema["items"].(map[string]any); ok {
+			for i, e := range t {
+				errs = append(errs, s.check(items, e, ptr+"/"+fmt.Sprint(i))...)
+			}
+		}
+	case map[string]any:
+		if req, ok := schema["required"].([]any); ok {
+			for _, k := range req {
+				if _, present := t[k.(string)]; !present {
+					fail("missing required property %q", k)
+				}
+			}
+		}
+		props, _ := schema["properties"].(map[string]any)
+		for k, e := range t {
+			child := ptr + "/" + escapePointer(k)
+			if ps, ok := props[k].(map[string]any); ok {
+				errs = append(errs, s.check(ps, e, child)...)
+				continue
+			}
+			switch ap := schema["additionalProperties"].(type) {
+			case bool:
+				if !ap {
+					errs = append(errs, schemaError{Pointer: child, Message: "is not an allowed property"})
+				}
+			case map[string]any:
+				errs = append(errs, s.check(ap, e, child)...)
+			}
+		}
+	}
+
+	if all, ok := schema["allOf"].([]any); ok {
+		for _, sub := range all {
+			errs = append(errs, s.check(sub.(map[string]any), v, ptr)...)
+		}
+	}
+	if anyOf, ok := schema["anyOf"].([]any); ok {
+		// report the closest alternative when none matches
+		var best []schemaError
+		for i, sub := range anyOf {
+			e := s.check(sub.(map[string]any), v, ptr)
+			if len(e) == 0 {
+				best = nil
+				break
+			}
+			if i == 0 || len(e) < len(best) {
+				best = e
+			}
+		}
+		errs = append(errs, best...)
+	}
+	if cond, ok := schema["if"].(map[string]any); ok && len(s.check(cond, v, ptr)) == 0 {
+		if then, ok := schema["then"].(map[string]any); ok {
+			errs = append(errs, s.check(then, v, ptr)...)
+		}
+	}
+	return errs
+}
+
+// resolve follows a local reference such as #/$defs/step.
+func (s *jsonSchema) resolve(ref string) map[string]any {
+	var cur any = s.root
+	for _, part := range strings.Split(strings.TrimPrefix(ref, "#/"), "/") {
+		m, _ := cur.(map[string]any)
+		cur = m[unescapePointer(part)]
+	}
+	out, _ := cur.(map[string]any)
+	return out
+}
+
+func (s *jsonSchema) pattern(p string) *regexp.Regexp {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	re, ok := s.patterns[p]
+	if !ok {
+		re = regexp.MustCompile(p)
+		s.patterns[p] = re
+	}
+	return re
+}
+
+func typeMatches(t any, v any) bool {
+	if list, ok := t.([]any); ok {
+		for _, e := range list {
+			if typeMatches(e, v) {
+				return true
+			}
+		}
+		return false
+	}
+	switch t {
+	case "object":
+		_, ok := v.(map[string]any)
+		return ok
+	case "array":
+		_, ok := v.([]any)
+		return ok
+	case "string":
+		_, ok := v.(string)
+		return ok
+	case "number":
+		_, ok := v.(float64)
+		return ok
+	case "integer":
+		f, ok := v.(float64)
+		return ok && f == math.Trunc(f)
+	case "boolean":
+		_, ok := v.(bool)
+		return ok
+	case "null":
+		return v == nil
+	}
+	return true
+}
+
+func typeNames(t any) string {
+	list, ok := t.([]any)
+	if !ok {
+		return fmt.Sprintf("%s", t)
+	}
+	names := make([]string, len(list))
+	for i, e := range list {
+		names[i] = fmt.Sprint(e)
+	}
+	return strings.Join(names, " or ")
+}
+
+func containsValue(list []any, v any) bool {
+	for _, e := range list {
+		if reflect.DeepEqual(e, v) {
+			return true
+		}
+	}
+	return false
+}
+
+func compactJSON(v any) string {
+	b, _ := json.Marshal(v)
+	return string(b)
+}
+
+func escapePointer(s string) string {
+	return strings.NewReplacer("~", "~0", "/", "~1").Replace(s)
+}
+
+func unescapePointer(s string) string {
+	return strings.NewReplacer("~1", "/", "~0", "~").Replace(s)
+}
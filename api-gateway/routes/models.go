@@ -0,0 +1,184 @@
+package routes
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/weltschmerz/QA-Playground/api-gateway/adapters"
+)
+
+// ListRegisteredModels returns the full model registry including backend URLs
+// @Summary List model registry
+// @Description Returns every registered model with backend URLs, capabilities and status
+// @Tags admin
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Router /v1/admin/models [get]
+func ListRegisteredModels(c *gin.Context) {
+	models := adapters.DefaultRegistry.List()
+	c.JSON(http.StatusOK, gin.H{
+		"models": models,
+		"total":  len(models),
+		"source": adapters.DefaultRegistry.Source(),
+	})
+}
+
+// UpsertRegisteredModel creates or replaces a model registry entry
+// @Summary Register model
+// @Description Creates or replaces a model entry; the path name wins over any name in the body
+// @Tags admin
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param model path string true "Model name"
+// @Param request body adapters.ModelEntry true "Model entry"
+// @Success 200 {object} adapters.ModelEntry
+// @Success 201 {object} adapters.ModelEntry
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Router /v1/admin/models/{model} [put]
+func UpsertRegisteredModel(c *gin.Context) {
+	name := c.Param("model")
+	var entry adapters.ModelEntry
+	if err := c.BindJSON(&entry); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid payload"})
+		return
+	}
+	entry.Name = name
+	_, existed := adapters.DefaultRegistry.Get(name)
+	saved, err := adapters.DefaultRegistry.Upsert(entry)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if existed {
+		c.JSON(http.StatusOK, saved)
+		return
+	}
+	c.JSON(http.StatusCreated, saved)
+}
+
+// DeleteRegisteredModel removes a model from the registry
+// @Summary Unregister model
+// @Tags admin
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param model path string true "Model name"
+// @Success 204
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /v1/admin/models/{model} [delete]
+func DeleteRegisteredModel(c *gin.Context) {
+	if adapters.DefaultRegistry.Delete(c.Param("model")) {
+		c.Status(http.StatusNoContent)
+		return
+	}
+	c.JSON(http.StatusNotFound, gin.H{"error": "model not found"})
+}
+
+// ReloadModelRegistry re-reads the registry file configured via MODEL_REGISTRY_FILE
+// @Summary Reload model registry
+// @Description Re-reads the registry file; fails with 409 when the registry was not loaded from a file
+// @Tags admin
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /v1/admin/models/reload [post]
+func ReloadModelRegistry(c *gin.Context) {
+	if adapters.DefaultRegistry.Source() == "" {
+		c.JSON(http.StatusConflict, gin.H{"error": "registry was not loaded from a file"})
+		return
+	}
+	if err := adapters.DefaultRegistry.Reload(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"reloaded": true,
+		"source":   adapters.DefaultRegistry.Source(),
+		"total":    len(adapters.DefaultRegistry.List()),
+	})
+}
+
+// applyModelConfig maps a configure payload onto a registry entry. Known
+// registry fields are typed; anything else lands in the free-form config map.
+func applyModelConfig(e *adapters.ModelEntry, config map[string]any) error {
+	for k, v := range config {
+		switch k {
+		case "status", "version", "type":
+			s, ok := v.(string)
+			if !ok || strings.TrimSpace(s) == "" {
+				return errors.New(k + " must be a non-empty string")
+			}
+			switch k {
+			case "status":
+				e.Status = s
+			case "version":
+				e.Version = s
+			default:
+				e.Type = s
+			}
+		case "max_tokens":
+			n, ok := toInt(v)
+			if !ok || n <= 0 {
+				return errors.New("max_tokens must be a positive integer")
+			}
+			e.MaxTokens = n
+		case "resilience":
+			raw, _ := json.Marshal(v)
+			var p adapters.ResiliencePolicy
+			dec := json.NewDecoder(bytes.NewReader(raw))
+			dec.DisallowUnknownFields()
+			if err := dec.Decode(&p); err != nil {
+				return errors.New("resilience must be a policy object")
+			}
+			e.Resilience = &p
+		case "urls", "capabilities", "fallback":
+			list, ok := toStringSlice(v)
+			if !ok {
+				return errors.New(k + " must be an array of strings")
+			}
+			switch k {
+			case "urls":
+				e.URLs = list
+			case "capabilities":
+				e.Capabilities = list
+			default:
+				e.Fallback = list
+			}
+		default:
+			if e.Config == nil {
+				e.Config = map[string]any{}
+			}
+			e.Config[k] = v
+		}
+	}
+	return nil
+}
+
+func toStringSlice(v any) ([]string, bool) {
+	raw, ok := v.([]any)
+	if !ok {
+		return nil, false
+	}
+	out := make([]string, 0, len(raw))
+	for _, item := range raw {
+		s, ok := item.(string)
+		if !ok {
+			return nil, false
+		}
+		out = append(out, s)
+	}
+	return out, true
+}
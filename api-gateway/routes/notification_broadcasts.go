@@ -0,0 +1,303 @@
+package routes
+
+import (
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Broadcast statuses: scheduled -> sent | cancelled.
+const (
+	broadcastScheduled = "scheduled"
+	broadcastSent      = "sent"
+	broadcastCancelled = "cancelled"
+)
+
+// broadcastTick is how often the scheduler looks for due broadcasts when the
+// scheduler clock is not moved.
+const broadcastTick = time.Second
+
+// notificationBroadcast is one broadcast: a notification for each recipient,
+// created at once or when the scheduler clock reaches ScheduledFor.
+type notificationBroadcast struct {
+	ID              string         `json:"id"`
+	Title           string         `json:"title"`
+	Message         string         `json:"message"`
+	Type            string         `json:"type"`
+	Priority        string         `json:"priority"`
+	Metadata        map[string]any `json:"metadata,omitempty"`
+	Recipients      []string       `json:"recipients"`
+	Sender          string         `json:"sender"`
+	Status          string         `json:"status"` // scheduled|sent|cancelled
+	ScheduledFor    string         `json:"scheduled_for"`
+	CreatedAt       string         `json:"created_at"`
+	UpdatedAt       string         `json:"updated_at"`
+	SentAt          string         `json:"sent_at,omitempty"`
+	CancelledAt     string         `json:"cancelled_at,omitempty"`
+	RecipientsCount int            `json:"recipients_count"`
+	DeliveredCount  int            `json:"delivered_count"`
+	ReadCount       int            `json:"read_count"`
+	NotificationIDs []string       `json:"notification_ids,omitempty"`
+
+	tenant       string
+	recipientIDs []string // users-core IDs by recipient, "" when unknown
+	at           time.Time
+}
+
+var (
+	broadcastStore = map[string]*notificationBroadcast{} // by broadcast ID
+	// bcastMu guards broadcastStore; it is taken before notifMu.
+	bcastMu            sync.Mutex
+	broadcastSchedOnce sync.Once
+)
+
+// startBroadcastScheduler launches the delivery loop once. It follows
+// schedClock, so moving the clock through /v1/admin/clock delivers due
+// broadcasts right away.
+func startBroadcastScheduler() {
+	broadcastSchedOnce.Do(func() {
+		moved := schedClock.subscribe()
+		go func() {
+			tick := time.NewTicker(broadcastTick)
+			defer tick.Stop()
+			for {
+				select {
+				case <-tick.C:
+				case <-moved:
+				}
+				deliverDueBroadcasts(schedClock.Now())
+			}
+		}()
+	})
+}
+
+// deliverDueBroadcasts sends every scheduled broadcast due at now.
+func deliverDueBroadcasts(now time.Time) {
+	bcastMu.Lock()
+	defer bcastMu.Unlock()
+	for _, b := range broadcastStore {
+		if b.Status == broadcastScheduled && !b.at.After(now) {
+			b.deliver(now)
+		}
+	}
+}
+
+// deliver creates the recipients' notifications. Caller holds bcastMu.
+func (b *notificationBroadcast) deliver(now time.Time) {
+	ts := now.UTC().Format(time.RFC3339)
+	notifMu.Lock()
+	store := tenantStore(b.tenant)
+	for i, r := range b.Recipients {
+		n := Notification{
+			ID:          newNotificationID(),
+			Title:       b.Title,
+			Message:     b.Message,
+			Type:        b.Type,
+			Recipient:   r,
+			RecipientID: b.recipientIDs[i],
+			Sender:      b.Sender,
+			Priority:    b.Priority,
+			Status:      "unread",
+			CreatedAt:   ts,
+			UpdatedAt:   ts,
+			Metadata:    b.Metadata,
+		}
+		store.put("created", n)
+		b.NotificationIDs = append(b.NotificationIDs, n.ID)
+	}
+	notifMu.Unlock()
+	b.DeliveredCount = len(b.NotificationIDs)
+	b.Status, b.SentAt, b.UpdatedAt = broadcastSent, ts, ts
+}
+
+// view returns a copy of b with its read count. Caller holds bcastMu.
+func (b *notificationBroadcast) view() notificationBroadcast {
+	v := *b
+	v.Recipients = append([]string(nil), b.Recipients...)
+	v.NotificationIDs = append([]string(nil), b.NotificationIDs...)
+	notifMu.Lock()
+	store := tenantStore(b.tenant)
+	for _, id := range b.NotificationIDs {
+		if n, ok := store.byID[id]; ok && n.Status == "read" {
+			v.ReadCount++
+		}
+	}
+	notifMu.Unlock()
+	return v
+}
+
+// visibleBroadcast returns broadcast broadcastId when the caller sent it (or
+// is privileged), answering 404 otherwise. Caller holds bcastMu.
+func visibleBroadcast(c *gin.Context, caller notifCaller) (*notificationBroadcast, bool) {
+	b, ok := broadcastStore[c.Param("broadcastId")]
+	if !ok || b.tenant != caller.tenant || (!caller.privileged && b.Sender != caller.sender()) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "broadcast not found"})
+		return nil, false
+	}
+	return b, true
+}
+
+// parseScheduleFor parses an RFC3339 schedule_for; a time in the past is
+// delivered on the scheduler's next tick.
+func parseScheduleFor(raw string) (time.Time, bool) {
+	t, err := time.Parse(time.RFC3339, strings.TrimSpace(raw))
+	if err != nil {
+		return time.Time{}, false
+	}
+	return t.UTC(), true
+}
+
+// ListBroadcasts lists broadcasts
+// @Summary List notification broadcasts
+// @Description Broadcasts of the tenant the caller sent (all of them for the service API key and admins), newest first, with recipient, delivered and read counts
+// @Tags notifications
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Produce json
+// @Param status query string false "scheduled|sent|cancelled"
+// @Param page query int false "Page number"
+// @Param limit query int false "Items per page"
+// @Success 200 {object} map[string]interface{}
+// @Router /v1/notifications/broadcasts [get]
+func ListBroadcasts(c *gin.Context) {
+	caller, ok := notificationCaller(c)
+	if !ok {
+		return
+	}
+	statusFilter := strings.TrimSpace(c.Query("status"))
+	page, limit := 1, 20
+	if n, err := strconv.Atoi(c.Query("page")); err == nil && n > 0 {
+		page = n
+	}
+	if n, err := strconv.Atoi(c.Query("limit")); err == nil && n > 0 {
+		limit = n
+	}
+	bcastMu.Lock()
+	items := make([]notificationBroadcast, 0)
+	for _, b := range broadcastStore {
+		if b.tenant != caller.tenant || (!caller.privileged && b.Sender != caller.sender()) {
+			continue
+		}
+		if statusFilter != "" && !strings.EqualFold(b.Status, statusFilter) {
+			continue
+		}
+		items = append(items, b.view())
+	}
+	bcastMu.Unlock()
+	sort.Slice(items, func(i, j int) bool {
+		if items[i].CreatedAt != items[j].CreatedAt {
+			return items[i].CreatedAt > items[j].CreatedAt
+		}
+		return items[i].ID > items[j].ID
+	})
+	total := len(items)
+	start := min((page-1)*limit, total)
+	end := min(start+limit, total)
+	c.JSON(http.StatusOK, gin.H{"broadcasts": items[start:end], "total": total, "page": page, "limit": limit})
+}
+
+// GetBroadcast returns one broadcast
+// @Summary Get notification broadcast
+// @Description Status, schedule and delivery counts of a broadcast
+// @Tags notifications
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Produce json
+// @Param broadcastId path string true "Broadcast ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 404 {object} map[string]string
+// @Router /v1/notifications/broadcasts/{broadcastId} [get]
+func GetBroadcast(c *gin.Context) {
+	caller, ok := notificationCaller(c)
+	if !ok {
+		return
+	}
+	bcastMu.Lock()
+	defer bcastMu.Unlock()
+	if b, ok := visibleBroadcast(c, caller); ok {
+		c.JSON(http.StatusOK, b.view())
+	}
+}
+
+// CancelBroadcast cancels a scheduled broadcast
+// @Summary Cancel notification broadcast
+// @Description Cancels a broadcast that has not been delivered yet
+// @Tags notifications
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Produce json
+// @Param broadcastId path string true "Broadcast ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /v1/notifications/broadcasts/{broadcastId}/cancel [post]
+func CancelBroadcast(c *gin.Context) {
+	caller, ok := notificationCaller(c)
+	if !ok {
+		return
+	}
+	bcastMu.Lock()
+	defer bcastMu.Unlock()
+	b, ok := visibleBroadcast(c, caller)
+	if !ok {
+		return
+	}
+	if b.Status != broadcastScheduled {
+		c.JSON(http.StatusConflict, gin.H{"error": "only scheduled broadcasts can be changed", "status": b.Status})
+		return
+	}
+	now := schedClock.Now().Format(time.RFC3339)
+	b.Status, b.CancelledAt, b.UpdatedAt = broadcastCancelled, now, now
+	c.JSON(http.StatusOK, b.view())
+}
+
+// RescheduleBroadcast moves a scheduled broadcast
+// @Summary Reschedule notification broadcast
+// @Description Moves a broadcast that has not been delivered yet to schedule_for (RFC3339)
+// @Tags notifications
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param broadcastId path string true "Broadcast ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /v1/notifications/broadcasts/{broadcastId}/reschedule [post]
+func RescheduleBroadcast(c *gin.Context) {
+	caller, ok := notificationCaller(c)
+	if !ok {
+		return
+	}
+	var payload struct {
+		ScheduleFor string `json:"schedule_for"`
+	}
+	if err := c.BindJSON(&payload); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid payload"})
+		return
+	}
+	at, ok := parseScheduleFor(payload.ScheduleFor)
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "schedule_for must be an RFC3339 timestamp"})
+		return
+	}
+	bcastMu.Lock()
+	defer bcastMu.Unlock()
+	b, ok := visibleBroadcast(c, caller)
+	if !ok {
+		return
+	}
+	if b.Status != broadcastScheduled {
+		c.JSON(http.StatusConflict, gin.H{"error": "only scheduled broadcasts can be changed", "status": b.Status})
+		return
+	}
+	b.at, b.ScheduledFor = at, at.Format(time.RFC3339)
+	b.UpdatedAt = schedClock.Now().Format(time.RFC3339)
+	c.JSON(http.StatusOK, b.view())
+}
@@ -0,0 +1,282 @@
+package routes
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"golang.org/x/net/websocket"
+)
+
+const (
+	// notificationHistory is how many events a tenant keeps for clients
+	// resuming with Last-Event-ID.
+	notificationHistory = 500
+	// streamBuffer bounds the events queued for one client; a client that
+	// falls further behind is disconnected and resumes with Last-Event-ID.
+	streamBuffer = 64
+	// streamHeartbeat is the default interval of heartbeats on idle streams.
+	streamHeartbeat = 15 * time.Second
+)
+
+// notificationEvent is a change to a notification: created, updated, read,
+// unread or deleted. IDs count up per tenant.
+type notificationEvent struct {
+	ID   int64
+	Type string
+	n    Notification
+	data []byte // {"id", "type", "notification"}, encoded when published
+}
+
+// notificationStream fans a tenant's notification events out to its
+// subscribers. It is part of notificationStore and guarded by notifMu.
+type notificationStream struct {
+	seq    int64
+	events []notificationEvent // oldest first
+	subs   map[*streamSubscriber]bool
+}
+
+type streamSubscriber struct {
+	caller notifCaller
+	ch     chan notificationEvent
+}
+
+// follows reports whether the caller's streams carry events about n: the
+// recipient's do, and the service API key and admins get every event of the
+// tenant.
+func (u notifCaller) follows(n Notification) bool {
+	return u.privileged || u.isRecipient(n)
+}
+
+func (s *notificationStream) publish(event string, n Notification) {
+	s.seq++
+	data, _ := json.Marshal(gin.H{"id": s.seq, "type": event, "notification": n})
+	ev := notificationEvent{ID: s.seq, Type: event, n: n, data: data}
+	s.events = append(s.events, ev)
+	if len(s.events) > notificationHistory {
+		s.events = s.events[1:]
+	}
+	for sub := range s.subs {
+		if !sub.caller.follows(n) {
+			continue
+		}
+		select {
+		case sub.ch <- ev:
+		default:
+			delete(s.subs, sub)
+			close(sub.ch)
+		}
+	}
+}
+
+// subscribe registers a subscriber and returns the events after lastID it
+// follows. resync is set when events after lastID are no longer kept (or
+// lastID is unknown, e.g. after a restart), so the client should reload.
+// Caller holds notifMu.
+func (s *notificationStream) subscribe(caller notifCaller, lastID int64, resume bool) (sub *streamSubscriber, backlog []notificationEvent, resync bool) {
+	sub = &streamSubscriber{caller: caller, ch: make(chan notificationEvent, streamBuffer)}
+	s.subs[sub] = true
+	if !resume {
+		return sub, nil, false
+	}
+	oldest := s.seq + 1
+	if len(s.events) > 0 {
+		oldest = s.events[0].ID
+	}
+	resync = lastID > s.seq || lastID+1 < oldest
+	for _, ev := range s.events {
+		if ev.ID > lastID && caller.follows(ev.n) {
+			backlog = append(backlog, ev)
+		}
+	}
+	return sub, backlog, resync
+}
+
+// unsubscribe removes sub unless publish already dropped it. Caller holds
+// notifMu.
+func (s *notificationStream) unsubscribe(sub *streamSubscriber) {
+	if s.subs[sub] {
+		delete(s.subs, sub)
+		close(sub.ch)
+	}
+}
+
+// notificationFeed is one connected client of a tenant's stream.
+type notificationFeed struct {
+	store     *notificationStore
+	sub       *streamSubscriber
+	backlog   []notificationEvent
+	resync    bool
+	cursor    int64 // last event ID of the tenant when subscribing
+	heartbeat time.Duration
+}
+
+// openFeed subscribes the caller to its tenant's events, resuming after the
+// Last-Event-ID header (or last_event_id query parameter, for clients that
+// cannot set headers). It answers 400 for malformed parameters.
+func openFeed(c *gin.Context) (*notificationFeed, bool) {
+	caller, ok := notificationCaller(c)
+	if !ok {
+		return nil, false
+	}
+	var lastID int64
+	raw := strings.TrimSpace(c.GetHeader("Last-Event-ID"))
+	if raw == "" {
+		raw = strings.TrimSpace(c.Query("last_event_id"))
+	}
+	if raw != "" {
+		n, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil || n < 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Last-Event-ID must be an event id"})
+			return nil, false
+		}
+		lastID = n
+	}
+	feed := &notificationFeed{heartbeat: streamHeartbeat}
+	if raw := c.Query("heartbeat"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n < 1 || n > 300 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "heartbeat must be between 1 and 300 seconds"})
+			return nil, false
+		}
+		feed.heartbeat = time.Duration(n) * time.Second
+	}
+	notifMu.Lock()
+	feed.store = tenantStore(caller.tenant)
+	feed.sub, feed.backlog, feed.resync = feed.store.stream.subscribe(caller, lastID, raw != "")
+	feed.cursor = feed.store.stream.seq
+	notifMu.Unlock()
+	return feed, true
+}
+
+func (f *notificationFeed) close() {
+	notifMu.Lock()
+	f.store.stream.unsubscribe(f.sub)
+	notifMu.Unlock()
+}
+
+// run sends a ready message, a resync notice if needed, the missed events and
+// then live events, with heartbeats while idle. It returns when ctx ends, a
+// send fails or the subscriber is dropped for falling behind.
+func (f *notificationFeed) run(ctx context.Context, send func(id int64, event string, data []byte) error, heartbeat func() error) {
+	ready, _ := json.Marshal(gin.H{"type": "ready", "tenant": f.sub.caller.tenant, "last_event_id": f.cursor})
+	if send(0, "ready", ready) != nil {
+		return
+	}
+	if f.resync {
+		msg, _ := json.Marshal(gin.H{"type": "resync", "last_event_id": f.cursor})
+		if send(0, "resync", msg) != nil {
+			return
+		}
+	}
+	for _, ev := range f.backlog {
+		if send(ev.ID, ev.Type, ev.data) != nil {
+			return
+		}
+	}
+	tick := time.NewTicker(f.heartbeat)
+	defer tick.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case ev, ok := <-f.sub.ch:
+			if !ok || send(ev.ID, ev.Type, ev.data) != nil {
+				return
+			}
+			tick.Reset(f.heartbeat)
+		case <-tick.C:
+			if heartbeat() != nil {
+				return
+			}
+		}
+	}
+}
+
+// StreamNotifications pushes notification events over Server-Sent Events
+// @Summary Notification event stream
+// @Description Server-Sent Events of the caller's tenant: created, updated, read, unread and deleted events (data: {id, type, notification}) for notifications addressed to the caller; the service API key and admins get all of them. The stream opens with a ready event carrying the latest event id. Reconnect with Last-Event-ID (or last_event_id) to receive the events missed since; a resync event means some are no longer kept and the list should be reloaded. Idle streams get a heartbeat comment every heartbeat seconds (default 15).
+// @Tags notifications
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Produce text/event-stream
+// @Param Last-Event-ID header string false "Resume after this event id"
+// @Param last_event_id query int false "Resume after this event id"
+// @Param heartbeat query int false "Heartbeat interval in seconds (1-300)"
+// @Success 200 {string} string "event stream"
+// @Failure 400 {object} map[string]string
+// @Router /v1/notifications/stream [get]
+func StreamNotifications(c *gin.Context) {
+	feed, ok := openFeed(c)
+	if !ok {
+		return
+	}
+	defer feed.close()
+	startSSE(c)
+	send := func(id int64, event string, data []byte) error {
+		if id > 0 {
+			fmt.Fprintf(c.Writer, "id: %d\n", id)
+		}
+		if _, err := fmt.Fprintf(c.Writer, "event: %s\ndata: %s\n\n", event, data); err != nil {
+			return err
+		}
+		c.Writer.Flush()
+		return nil
+	}
+	heartbeat := func() error {
+		if _, err := fmt.Fprintf(c.Writer, ": heartbeat %s\n\n", time.Now().UTC().Format(time.RFC3339)); err != nil {
+			return err
+		}
+		c.Writer.Flush()
+		return nil
+	}
+	feed.run(c.Request.Context(), send, heartbeat)
+}
+
+// NotificationSocket pushes notification events over a WebSocket
+// @Summary Notification WebSocket
+// @Description The events of /v1/notifications/stream over a WebSocket: each text message is a JSON object with a type (ready, resync, heartbeat, or the event type with its id and notification). Resume with the Last-Event-ID header or last_event_id. Messages from the client are ignored.
+// @Tags notifications
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param Last-Event-ID header string false "Resume after this event id"
+// @Param last_event_id query int false "Resume after this event id"
+// @Param heartbeat query int false "Heartbeat interval in seconds (1-300)"
+// @Success 101 {string} string "switching protocols"
+// @Failure 400 {object} map[string]string
+// @Router /v1/notifications/ws [get]
+func NotificationSocket(c *gin.Context) {
+	if !strings.EqualFold(c.GetHeader("Upgrade"), "websocket") {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "websocket upgrade required"})
+		return
+	}
+	feed, ok := openFeed(c)
+	if !ok {
+		return
+	}
+	defer feed.close()
+	// no Handshake: clients outside browsers send no Origin, and the caller
+	// is already authenticated
+	websocket.Server{Handler: func(ws *websocket.Conn) {
+		ctx, cancel := context.WithCancel(c.Request.Context())
+		defer cancel()
+		go func() {
+			io.Copy(io.Discard, ws) // returns when the client closes
+			cancel()
+		}()
+		send := func(_ int64, _ string, data []byte) error {
+			return websocket.Message.Send(ws, string(data))
+		}
+		heartbeat := func() error {
+			msg, _ := json.Marshal(gin.H{"type": "heartbeat", "time": time.Now().UTC().Format(time.RFC3339)})
+			return websocket.Message.Send(ws, string(msg))
+		}
+		feed.run(ctx, send, heartbeat)
+	}}.ServeHTTP(c.Writer, c.Request)
+}
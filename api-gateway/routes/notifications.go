@@ -6,6 +6,7 @@ import (
 	"sort"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -13,45 +14,176 @@ import (
 )
 
 type Notification struct {
-	ID        string         `json:"id"`
-	Title     string         `json:"title"`
-	Message   string         `json:"message"`
-	Type      string         `json:"type"`
-	Recipient string         `json:"recipient"`
-	Priority  string         `json:"priority"`
-	Status    string         `json:"status"`
-	CreatedAt string         `json:"created_at"`
-	UpdatedAt string         `json:"updated_at"`
-	ReadBy    *string        `json:"read_by"`
-	ReadAt    *string        `json:"read_at"`
-	Metadata  map[string]any `json:"metadata,omitempty"`
+	ID          string         `json:"id"`
+	Title       string         `json:"title"`
+	Message     string         `json:"message"`
+	Type        string         `json:"type"`
+	Recipient   string         `json:"recipient"`
+	RecipientID string         `json:"recipient_id,omitempty"` // users-core ID of the recipient, when known
+	Sender      string         `json:"sender,omitempty"`       // user:<id>, service or workflow:<id>
+	Priority    string         `json:"priority"`
+	Status      string         `json:"status"`
+	CreatedAt   string         `json:"created_at"`
+	UpdatedAt   string         `json:"updated_at"`
+	ReadBy      *string        `json:"read_by"`
+	ReadAt      *string        `json:"read_at"`
+	Metadata    map[string]any `json:"metadata,omitempty"`
 }
 
+// Notifications live in tenants, chosen with the X-Tenant-ID header, so test
+// runs can keep theirs apart. Within a tenant a user sees what they sent or
+// received; service API keys and admins see everything.
+const (
+	tenantHeader  = "X-Tenant-ID"
+	defaultTenant = "default"
+)
+
+var tenantPattern = regexp.MustCompile(`^[A-Za-z0-9._:-]{1,64}$`)
+
 var (
-	// Namespace notifications per auth to avoid cross-test interference
-	notifDataNS = map[string]map[string]Notification{}
+	notifTenants = map[string]*notificationStore{}
+	// notifMu guards notifTenants; handlers hold it for the whole request since
+	// workflow notify steps write to the store in the background.
+	notifMu sync.Mutex
 )
 
-func nsKey(c *gin.Context) string {
-	if s := strings.TrimSpace(c.GetHeader("Authorization")); s != "" {
-		return s
+// notificationStore is one tenant's notifications, indexed by recipient, with
+// the recent change events its streams resume from.
+type notificationStore struct {
+	byID        map[string]Notification
+	byRecipient map[string]map[string]bool // lower-cased recipient email -> IDs
+	stream      notificationStream
+}
+
+// put adds or replaces n and publishes event (created, updated, read or
+// unread) to the tenant's streams. Caller holds notifMu.
+func (s *notificationStore) put(event string, n Notification) {
+	s.unindex(n.ID)
+	s.byID[n.ID] = n
+	if key := strings.ToLower(n.Recipient); key != "" {
+		if s.byRecipient[key] == nil {
+			s.byRecipient[key] = map[string]bool{}
+		}
+		s.byRecipient[key][n.ID] = true
 	}
-	if s := strings.TrimSpace(c.GetHeader("x-api-key")); s != "" {
-		return "apikey:" + s
+	s.stream.publish(event, n)
+}
+
+// remove drops a notification and publishes a deleted event. Caller holds
+// notifMu.
+func (s *notificationStore) remove(id string) {
+	if old, ok := s.unindex(id); ok {
+		s.stream.publish("deleted", old)
 	}
-	return "anon"
 }
 
-func getNotifStore(c *gin.Context) map[string]Notification {
-	key := nsKey(c)
-	if store, ok := notifDataNS[key]; ok {
+func (s *notificationStore) unindex(id string) (Notification, bool) {
+	old, ok := s.byID[id]
+	if !ok {
+		return old, false
+	}
+	delete(s.byID, id)
+	key := strings.ToLower(old.Recipient)
+	delete(s.byRecipient[key], id)
+	if len(s.byRecipient[key]) == 0 {
+		delete(s.byRecipient, key)
+	}
+	return old, true
+}
+
+// inbox returns the notifications addressed to email. Caller holds notifMu.
+func (s *notificationStore) inbox(email string) []Notification {
+	ids := s.byRecipient[strings.ToLower(email)]
+	items := make([]Notification, 0, len(ids))
+	for id := range ids {
+		items = append(items, s.byID[id])
+	}
+	return items
+}
+
+// notifTenant returns the caller's notification tenant.
+func notifTenant(c *gin.Context) string {
+	if t := strings.TrimSpace(c.GetHeader(tenantHeader)); t != "" {
+		return t
+	}
+	return defaultTenant
+}
+
+// tenantStore returns the store of a tenant, creating it. Caller holds notifMu.
+func tenantStore(tenant string) *notificationStore {
+	if store, ok := notifTenants[tenant]; ok {
 		return store
 	}
-	store := map[string]Notification{}
-	notifDataNS[key] = store
+	store := &notificationStore{
+		byID:        map[string]Notification{},
+		byRecipient: map[string]map[string]bool{},
+		stream:      notificationStream{subs: map[*streamSubscriber]bool{}},
+	}
+	notifTenants[tenant] = store
 	return store
 }
 
+// notifCaller is the identity a notification request is made with.
+type notifCaller struct {
+	tenant     string
+	userID     string // empty for the service API key
+	email      string
+	privileged bool // service API key or admin
+}
+
+// notificationCaller resolves the caller of a notification endpoint through
+// users-core, answering 400 for a malformed tenant.
+func notificationCaller(c *gin.Context) (notifCaller, bool) {
+	caller := notifCaller{tenant: notifTenant(c), userID: c.GetString("userID")}
+	if !tenantPattern.MatchString(caller.tenant) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid " + tenantHeader})
+		return caller, false
+	}
+	if caller.userID == "" {
+		caller.privileged = true
+		return caller, true
+	}
+	if u := lookupUser(caller.userID); u != nil {
+		caller.email = u.Email
+		caller.privileged = isAdminUser(u)
+	}
+	return caller, true
+}
+
+func (u notifCaller) sender() string {
+	if u.userID == "" {
+		return "service"
+	}
+	return "user:" + u.userID
+}
+
+func (u notifCaller) isSender(n Notification) bool {
+	return u.userID != "" && n.Sender == "user:"+u.userID
+}
+
+func (u notifCaller) isRecipient(n Notification) bool {
+	if u.userID == "" {
+		return false
+	}
+	return n.RecipientID == u.userID || (u.email != "" && strings.EqualFold(n.Recipient, u.email))
+}
+
+func (u notifCaller) canSee(n Notification) bool {
+	return u.privileged || u.isSender(n) || u.isRecipient(n)
+}
+
+// visibleNotification returns notification id when the caller may see it,
+// answering 404 otherwise. Caller holds notifMu.
+func visibleNotification(c *gin.Context, caller notifCaller, id string) (Notification, *notificationStore, bool) {
+	store := tenantStore(caller.tenant)
+	n, ok := store.byID[id]
+	if !ok || !caller.canSee(n) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "notification not found"})
+		return n, store, false
+	}
+	return n, store, true
+}
+
 func newNotificationID() string { return "notif-" + utils.GenID()[:12] }
 
 func isValidEmail(s string) bool {
@@ -71,7 +203,7 @@ func defaultOrAllowed(v string, allowed []string, def string) string {
 
 // ListNotifications lists notifications with filters
 // @Summary List notifications
-// @Description Returns notifications with filters, pagination, and sorting
+// @Description Returns the tenant's notifications the caller sent or received (all of them for the service API key and admins) with filters, pagination, and sorting
 // @Tags notifications
 // @Security BearerAuth
 // @Security ApiKeyAuth
@@ -90,6 +222,10 @@ func defaultOrAllowed(v string, allowed []string, def string) string {
 // @Success 200 {object} map[string]interface{}
 // @Router /v1/notifications [get]
 func ListNotifications(c *gin.Context) {
+	caller, ok := notificationCaller(c)
+	if !ok {
+		return
+	}
 	// Filters and sorting
 	recipient := strings.TrimSpace(c.Query("recipient"))
 	statusFilter := strings.TrimSpace(c.Query("status"))
@@ -126,9 +262,14 @@ func ListNotifications(c *gin.Context) {
 		}
 	}
 
-	store := getNotifStore(c)
-	items := make([]Notification, 0, len(store))
-	for _, n := range store {
+	notifMu.Lock()
+	defer notifMu.Unlock()
+	store := tenantStore(caller.tenant)
+	items := make([]Notification, 0, len(store.byID))
+	for _, n := range store.byID {
+		if !caller.canSee(n) {
+			continue
+		}
 		if recipient != "" && !strings.EqualFold(n.Recipient, recipient) {
 			continue
 		}
@@ -211,8 +352,82 @@ func ListNotifications(c *gin.Context) {
 	c.JSON(http.StatusOK, resp)
 }
 
+// GetMyNotifications returns the caller's inbox
+// @Summary My notifications
+// @Description Notifications addressed to the calling user (by users-core user ID or email) in the tenant, newest first, with the unread count. Needs a user token.
+// @Tags notifications
+// @Security BearerAuth
+// @Produce json
+// @Param status query string false "Filter by status (read|unread)"
+// @Param page query int false "Page number"
+// @Param limit query int false "Items per page"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Router /v1/notifications/me [get]
+func GetMyNotifications(c *gin.Context) {
+	caller, ok := notificationCaller(c)
+	if !ok {
+		return
+	}
+	if caller.userID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "the inbox needs a user token"})
+		return
+	}
+	statusFilter := strings.TrimSpace(c.Query("status"))
+	page, limit := 1, 20
+	if n, err := strconv.Atoi(c.Query("page")); err == nil && n > 0 {
+		page = n
+	}
+	if n, err := strconv.Atoi(c.Query("limit")); err == nil && n > 0 {
+		limit = n
+	}
+
+	notifMu.Lock()
+	store := tenantStore(caller.tenant)
+	var candidates []Notification
+	if caller.email != "" {
+		candidates = store.inbox(caller.email)
+	}
+	// notifications addressed by user ID before the email was known
+	for _, n := range store.byID {
+		if n.RecipientID == caller.userID && !strings.EqualFold(n.Recipient, caller.email) {
+			candidates = append(candidates, n)
+		}
+	}
+	notifMu.Unlock()
+
+	items := make([]Notification, 0, len(candidates))
+	unread := 0
+	for _, n := range candidates {
+		if n.Status == "unread" {
+			unread++
+		}
+		if statusFilter == "" || strings.EqualFold(n.Status, statusFilter) {
+			items = append(items, n)
+		}
+	}
+	sort.Slice(items, func(i, j int) bool {
+		if items[i].CreatedAt != items[j].CreatedAt {
+			return items[i].CreatedAt > items[j].CreatedAt
+		}
+		return items[i].ID > items[j].ID
+	})
+	total := len(items)
+	start := min((page-1)*limit, total)
+	end := min(start+limit, total)
+	c.JSON(http.StatusOK, gin.H{
+		"notifications": items[start:end],
+		"total":         total,
+		"unread_count":  unread,
+		"recipient":     caller.email,
+		"page":          page,
+		"limit":         limit,
+	})
+}
+
 // CreateNotification creates a notification
 // @Summary Create notification
+// @Description The recipient is an email or a users-core user ID; the caller is recorded as sender
 // @Tags notifications
 // @Security BearerAuth
 // @Security ApiKeyAuth
@@ -243,11 +458,21 @@ func CreateNotification(c *gin.Context) {
 		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": "message length too long"})
 		return
 	}
-	if n.Recipient != "" && !isValidEmail(n.Recipient) {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid recipient"})
+	caller, ok := notificationCaller(c)
+	if !ok {
 		return
 	}
+	n.RecipientID = ""
+	if n.Recipient != "" {
+		email, userID, ok := resolveRecipient(n.Recipient)
+		if !ok {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid recipient"})
+			return
+		}
+		n.Recipient, n.RecipientID = email, userID
+	}
 	n.ID = newNotificationID()
+	n.Sender = caller.sender()
 	// Validate type/priority with sensible defaults
 	allowedTypes := []string{"info", "warning", "alert", "error"}
 	if strings.TrimSpace(n.Type) == "" {
@@ -272,8 +497,9 @@ func CreateNotification(c *gin.Context) {
 	now := time.Now().UTC().Format(time.RFC3339)
 	n.CreatedAt = now
 	n.UpdatedAt = now
-	store := getNotifStore(c)
-	store[n.ID] = n
+	notifMu.Lock()
+	defer notifMu.Unlock()
+	tenantStore(caller.tenant).put("created", n)
 	c.JSON(http.StatusCreated, n)
 }
 
@@ -288,17 +514,20 @@ func CreateNotification(c *gin.Context) {
 // @Failure 404 {object} map[string]string
 // @Router /v1/notifications/{notificationId} [get]
 func GetNotificationByID(c *gin.Context) {
-	id := c.Param("notificationId")
-	store := getNotifStore(c)
-	if n, ok := store[id]; ok {
-		c.JSON(http.StatusOK, n)
+	caller, ok := notificationCaller(c)
+	if !ok {
 		return
 	}
-	c.JSON(http.StatusNotFound, gin.H{"error": "notification not found"})
+	notifMu.Lock()
+	defer notifMu.Unlock()
+	if n, _, ok := visibleNotification(c, caller, c.Param("notificationId")); ok {
+		c.JSON(http.StatusOK, n)
+	}
 }
 
 // UpdateNotification updates a notification
 // @Summary Update notification
+// @Description Only the sender (or the service API key / an admin) can change a notification
 // @Tags notifications
 // @Security BearerAuth
 // @Security ApiKeyAuth
@@ -310,11 +539,18 @@ func GetNotificationByID(c *gin.Context) {
 // @Failure 404 {object} map[string]string
 // @Router /v1/notifications/{notificationId} [put]
 func UpdateNotification(c *gin.Context) {
-	id := c.Param("notificationId")
-	store := getNotifStore(c)
-	old, ok := store[id]
+	caller, ok := notificationCaller(c)
 	if !ok {
-		c.JSON(http.StatusNotFound, gin.H{"error": "notification not found"})
+		return
+	}
+	notifMu.Lock()
+	defer notifMu.Unlock()
+	old, store, ok := visibleNotification(c, caller, c.Param("notificationId"))
+	if !ok {
+		return
+	}
+	if !caller.privileged && !caller.isSender(old) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "only the sender can change a notification"})
 		return
 	}
 	var patch Notification
@@ -343,12 +579,13 @@ func UpdateNotification(c *gin.Context) {
 		}
 	}
 	old.UpdatedAt = time.Now().UTC().Format(time.RFC3339)
-	store[id] = old
+	store.put("updated", old)
 	c.JSON(http.StatusOK, old)
 }
 
 // MarkNotificationRead marks a notification as read
 // @Summary Mark notification as read
+// @Description Only the recipient (or the service API key / an admin) can change the read status
 // @Tags notifications
 // @Security BearerAuth
 // @Security ApiKeyAuth
@@ -359,11 +596,18 @@ func UpdateNotification(c *gin.Context) {
 // @Failure 404 {object} map[string]string
 // @Router /v1/notifications/{notificationId}/read [put]
 func MarkNotificationRead(c *gin.Context) {
-	id := c.Param("notificationId")
-	store := getNotifStore(c)
-	n, ok := store[id]
+	caller, ok := notificationCaller(c)
+	if !ok {
+		return
+	}
+	notifMu.Lock()
+	defer notifMu.Unlock()
+	n, store, ok := visibleNotification(c, caller, c.Param("notificationId"))
 	if !ok {
-		c.JSON(http.StatusNotFound, gin.H{"error": "notification not found"})
+		return
+	}
+	if !caller.privileged && !caller.isRecipient(n) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "only the recipient can mark a notification read"})
 		return
 	}
 	var body struct {
@@ -372,7 +616,10 @@ func MarkNotificationRead(c *gin.Context) {
 	}
 	_ = c.BindJSON(&body)
 	who := body.ReadBy
-	if strings.TrimSpace(who) == "" {
+	if !caller.privileged || strings.TrimSpace(who) == "" {
+		who = caller.email
+	}
+	if who == "" {
 		who = "system"
 	}
 	ts := body.ReadAt
@@ -383,13 +630,13 @@ func MarkNotificationRead(c *gin.Context) {
 	n.ReadBy = &who
 	n.ReadAt = &ts
 	n.UpdatedAt = ts
-	store[id] = n
+	store.put("read", n)
 	c.JSON(http.StatusOK, n)
 }
 
 // MarkNotificationUnread clears read status for a notification
 // @Summary Mark notification as unread
-// @Description Clears read status for a notification
+// @Description Clears read status for a notification; only the recipient (or the service API key / an admin) can change it
 // @Tags notifications
 // @Security BearerAuth
 // @Security ApiKeyAuth
@@ -400,30 +647,40 @@ func MarkNotificationRead(c *gin.Context) {
 // @Failure 404 {object} map[string]string
 // @Router /v1/notifications/{notificationId}/unread [put]
 func MarkNotificationUnread(c *gin.Context) {
-	id := c.Param("notificationId")
-	store := getNotifStore(c)
-	n, ok := store[id]
+	caller, ok := notificationCaller(c)
+	if !ok {
+		return
+	}
+	notifMu.Lock()
+	defer notifMu.Unlock()
+	n, store, ok := visibleNotification(c, caller, c.Param("notificationId"))
 	if !ok {
-		c.JSON(http.StatusNotFound, gin.H{"error": "notification not found"})
+		return
+	}
+	if !caller.privileged && !caller.isRecipient(n) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "only the recipient can mark a notification unread"})
 		return
 	}
 	n.Status = "unread"
 	n.ReadBy = nil
 	n.ReadAt = nil
 	n.UpdatedAt = time.Now().UTC().Format(time.RFC3339)
-	store[id] = n
+	store.put("unread", n)
 	c.JSON(http.StatusOK, n)
 }
 
 func DeleteNotification(c *gin.Context) {
-	id := c.Param("notificationId")
-	store := getNotifStore(c)
-	if _, ok := store[id]; ok {
-		delete(store, id)
-		c.Status(http.StatusNoContent)
+	caller, ok := notificationCaller(c)
+	if !ok {
 		return
 	}
-	c.JSON(http.StatusNotFound, gin.H{"error": "notification not found"})
+	notifMu.Lock()
+	defer notifMu.Unlock()
+	// the sender retracts a notification, the recipient dismisses it
+	if n, store, ok := visibleNotification(c, caller, c.Param("notificationId")); ok {
+		store.remove(n.ID)
+		c.Status(http.StatusNoContent)
+	}
 }
 
 func BroadcastNotification(c *gin.Context) {
@@ -445,41 +702,63 @@ func BroadcastNotification(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "recipients are required"})
 		return
 	}
+	caller, ok := notificationCaller(c)
+	if !ok {
+		return
+	}
+	type recipient struct{ email, userID string }
+	recipients := make([]recipient, 0, len(payload.Recipients))
 	for _, r := range payload.Recipients {
-		if !isValidEmail(r) {
+		email, userID, ok := resolveRecipient(r)
+		if !ok {
 			c.JSON(http.StatusBadRequest, gin.H{"error": "recipient email invalid"})
 			return
 		}
+		recipients = append(recipients, recipient{email, userID})
+	}
+	now := schedClock.Now()
+	b := &notificationBroadcast{
+		ID:              "broadcast-" + utils.GenID()[:8],
+		Title:           payload.Title,
+		Message:         payload.Message,
+		Type:            defaultOrAllowed(payload.Type, []string{"info", "warning", "alert", "error"}, "info"),
+		Priority:        defaultOrAllowed(payload.Priority, []string{"low", "medium", "normal", "high", "critical"}, "normal"),
+		Metadata:        payload.Metadata,
+		Sender:          caller.sender(),
+		Status:          broadcastScheduled,
+		CreatedAt:       now.Format(time.RFC3339),
+		UpdatedAt:       now.Format(time.RFC3339),
+		RecipientsCount: len(recipients),
+		tenant:          caller.tenant,
+	}
+	for _, r := range recipients {
+		b.Recipients = append(b.Recipients, r.email)
+		b.recipientIDs = append(b.recipientIDs, r.userID)
 	}
-	id := "broadcast-" + utils.GenID()[:8]
 	if payload.Immediate {
-		// Create notifications immediately
-		created := []string{}
-		now := time.Now().UTC().Format(time.RFC3339)
-		store := getNotifStore(c)
-		for _, r := range payload.Recipients {
-			n := Notification{
-				ID:        newNotificationID(),
-				Title:     payload.Title,
-				Message:   payload.Message,
-				Type:      defaultOrAllowed(payload.Type, []string{"info", "warning", "alert", "error"}, "info"),
-				Recipient: r,
-				Priority:  defaultOrAllowed(payload.Priority, []string{"low", "medium", "normal", "high", "critical"}, "normal"),
-				Status:    "unread",
-				CreatedAt: now,
-				UpdatedAt: now,
-				Metadata:  payload.Metadata,
-			}
-			store[n.ID] = n
-			created = append(created, n.ID)
-		}
-		c.JSON(http.StatusCreated, gin.H{"broadcast_id": id, "status": "sent", "notifications_created": created})
+		b.at, b.ScheduledFor = now, b.CreatedAt
+		bcastMu.Lock()
+		defer bcastMu.Unlock()
+		b.deliver(now)
+		broadcastStore[b.ID] = b
+		c.JSON(http.StatusCreated, gin.H{"broadcast_id": b.ID, "status": b.Status, "notifications_created": b.NotificationIDs})
 		return
 	}
-	// Scheduled broadcast
-	scheduledFor := payload.ScheduleFor
-	if strings.TrimSpace(scheduledFor) == "" {
-		scheduledFor = time.Now().UTC().Add(1 * time.Hour).Format(time.RFC3339)
-	}
-	c.JSON(http.StatusAccepted, gin.H{"broadcast_id": id, "recipients_count": len(payload.Recipients), "status": "scheduled", "scheduled_for": scheduledFor})
+	// Scheduled broadcast: the scheduler delivers it once schedClock reaches
+	// schedule_for (a past time is delivered on its next tick)
+	b.at = now.Add(1 * time.Hour)
+	if strings.TrimSpace(payload.ScheduleFor) != "" {
+		at, ok := parseScheduleFor(payload.ScheduleFor)
+		if !ok {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "schedule_for must be an RFC3339 timestamp"})
+			return
+		}
+		b.at = at
+	}
+	b.ScheduledFor = b.at.Format(time.RFC3339)
+	bcastMu.Lock()
+	broadcastStore[b.ID] = b
+	bcastMu.Unlock()
+	startBroadcastScheduler()
+	c.JSON(http.StatusAccepted, gin.H{"broadcast_id": b.ID, "recipients_count": b.RecipientsCount, "status": b.Status, "scheduled_for": b.ScheduledFor})
 }
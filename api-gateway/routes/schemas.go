@@ -1,5 +1,7 @@
 package routes
 
+import "github.com/weltschmerz/QA-Playground/api-gateway/adapters"
+
 // Typed schemas used for Swagger documentation
 
 // ---- Admin Schemas ----
@@ -20,6 +22,15 @@ type AdminSecurityRateLimiting struct {
 	Enabled           bool `json:"enabled" example:"true"`
 	RateLimitRequests int  `json:"rate_limit_requests" example:"200"`
 	RateLimitWindow   int  `json:"rate_limit_window" example:"60"`
+	// Per-window budget for each API key; 0 means unlimited
+	APIKeyRequests int `json:"api_key_requests" example:"0"`
+	// Subject ("user:<id>" or "api_key:<sha256 prefix>") -> limit
+	Overrides map[string]AdminRateLimitOverride `json:"overrides"`
+}
+
+type AdminRateLimitOverride struct {
+	RateLimitRequests int `json:"rate_limit_requests" example:"10"`
+	RateLimitWindow   int `json:"rate_limit_window,omitempty" example:"60"`
 }
 
 type AdminSecurityConfig struct {
@@ -36,7 +47,8 @@ type AdminPerformanceTimeoutSettings struct {
 }
 
 type AdminPerformanceCacheSettings struct {
-	CacheTTL int `json:"cache_ttl" example:"600"`
+	Enabled  bool `json:"enabled" example:"true"`
+	CacheTTL int  `json:"cache_ttl" example:"600"`
 }
 
 type AdminPerformanceConfig struct {
@@ -61,18 +73,44 @@ type AdminSystemConfig struct {
 	Security    AdminSecurityConfig    `json:"security"`
 	Performance AdminPerformanceConfig `json:"performance"`
 	Logging     AdminLoggingConfig     `json:"logging"`
+	Quotas      AdminQuotasConfig      `json:"quotas"`
+	Workflows   AdminWorkflowsConfig   `json:"workflows"`
 	Features    AdminFeaturesConfig    `json:"features"`
 }
 
+// AdminWorkflowsConfig: executions of a deleted workflow stay readable for
+// ExecutionRetention seconds; 0 deletes them with the workflow
+type AdminWorkflowsConfig struct {
+	ExecutionRetention int `json:"execution_retention" example:"0"`
+}
+
+// AdminQuotasConfig holds token quotas per caller subject; 0 means unlimited
+type AdminQuotasConfig struct {
+	Enabled       bool                          `json:"enabled" example:"true"`
+	DailyTokens   int                           `json:"daily_tokens" example:"0"`
+	MonthlyTokens int                           `json:"monthly_tokens" example:"0"`
+	Overrides     map[string]AdminQuotaOverride `json:"overrides"`
+}
+
+type AdminQuotaOverride struct {
+	DailyTokens   int `json:"daily_tokens,omitempty" example:"10000"`
+	MonthlyTokens int `json:"monthly_tokens,omitempty" example:"200000"`
+}
+
 type AdminConfigPatchPerformance struct {
-	MaxConnections int `json:"max_connections,omitempty" example:"200"`
-	RequestTimeout int `json:"request_timeout,omitempty" example:"20000"`
-	CacheTTL       int `json:"cache_ttl,omitempty" example:"1200"`
+	MaxConnections int   `json:"max_connections,omitempty" example:"200"`
+	RequestTimeout int   `json:"request_timeout,omitempty" example:"20000"`
+	CacheTTL       int   `json:"cache_ttl,omitempty" example:"1200"`
+	CacheEnabled   *bool `json:"cache_enabled,omitempty"`
 }
 
 type AdminConfigPatchSecurity struct {
-	RateLimitRequests int `json:"rate_limit_requests,omitempty" example:"300"`
-	RateLimitWindow   int `json:"rate_limit_window,omitempty" example:"60"`
+	RateLimitRequests int   `json:"rate_limit_requests,omitempty" example:"300"`
+	RateLimitWindow   int   `json:"rate_limit_window,omitempty" example:"60"`
+	RateLimitEnabled  *bool `json:"rate_limit_enabled,omitempty"`
+	APIKeyRequests    *int  `json:"api_key_requests,omitempty" example:"1000"`
+	// Set a subject's limit; a null value removes the override
+	RateLimitOverrides map[string]*AdminRateLimitOverride `json:"rate_limit_overrides,omitempty"`
 }
 
 type AdminConfigPatchFeatures struct {
@@ -84,9 +122,23 @@ type AdminConfigPatchFeatures struct {
 type AdminConfigPatch struct {
 	Performance *AdminConfigPatchPerformance `json:"performance,omitempty"`
 	Security    *AdminConfigPatchSecurity    `json:"security,omitempty"`
+	Quotas      *AdminConfigPatchQuotas      `json:"quotas,omitempty"`
+	Workflows   *AdminConfigPatchWorkflows   `json:"workflows,omitempty"`
 	Features    *AdminConfigPatchFeatures    `json:"features,omitempty"`
 }
 
+type AdminConfigPatchWorkflows struct {
+	ExecutionRetention *int `json:"execution_retention,omitempty" example:"3600"`
+}
+
+type AdminConfigPatchQuotas struct {
+	Enabled       *bool `json:"enabled,omitempty"`
+	DailyTokens   *int  `json:"daily_tokens,omitempty" example:"50000"`
+	MonthlyTokens *int  `json:"monthly_tokens,omitempty" example:"1000000"`
+	// Set a subject's quota; a null value removes the override
+	Overrides map[string]*AdminQuotaOverride `json:"overrides,omitempty"`
+}
+
 type AdminUpdateResponse struct {
 	UpdatedSettings map[string]interface{} `json:"updated_settings"`
 	AppliedAt       string                 `json:"applied_at" example:"2025-09-17T12:00:00Z"`
@@ -169,12 +221,66 @@ type AdminCreateBackupRequest struct {
 
 // ---- AI Schemas ----
 
+// AiCompleteRequest: generation parameters are validated against the model's
+// registry limits and forwarded to the adapter.
+type AiCompleteRequest struct {
+	Prompt string `json:"prompt" example:"Hello world"`
+	Model  string `json:"model" example:"adapter-a"`
+	// 0 makes the request cacheable
+	Temperature   *float64 `json:"temperature,omitempty" example:"0.7"`
+	MaxTokens     int      `json:"max_tokens,omitempty" example:"256"`
+	TopP          *float64 `json:"top_p,omitempty" example:"0.9"`
+	SystemPrompt  string   `json:"system_prompt,omitempty" example:"You are a helpful assistant"`
+	StopSequences []string `json:"stop_sequences,omitempty"`
+	// Answer with a text/event-stream, as /v1/ai/complete/stream does
+	Stream bool `json:"stream,omitempty" example:"false"`
+	// Models tried in order on a retryable failure; defaults to the model's
+	// registry fallback list
+	Fallback []string `json:"fallback,omitempty"`
+}
+
+type AiCompleteAttempt struct {
+	Model      string `json:"model" example:"adapter-a"`
+	Outcome    string `json:"outcome" example:"success"`
+	Error      string `json:"error,omitempty"`
+	StatusCode int    `json:"status_code,omitempty"`
+}
+
+type AiCompleteResponse struct {
+	// The model that served the request
+	Model          string `json:"model" example:"adapter-a"`
+	RequestedModel string `json:"requested_model" example:"adapter-a"`
+	FallbackUsed   bool   `json:"fallback_used" example:"false"`
+	// Every model tried, in order
+	Attempts     []AiCompleteAttempt `json:"attempts"`
+	Completion   string              `json:"completion" example:"A: Hello world"`
+	Usage        adapters.Usage      `json:"usage"`
+	RequestID    string              `json:"request_id" example:"req-1726482600-42"`
+	FinishReason string              `json:"finish_reason" example:"stop"`
+	TraceID      string              `json:"traceId" example:"3f9c2a7e5b1d4c8f"`
+}
+
+type ChatCompletionRequest struct {
+	Model         string                 `json:"model" example:"adapter-a"`
+	Messages      []adapters.ChatMessage `json:"messages"`
+	Temperature   *float64               `json:"temperature,omitempty" example:"0.7"`
+	TopP          *float64               `json:"top_p,omitempty" example:"0.9"`
+	MaxTokens     int                    `json:"max_tokens,omitempty" example:"256"`
+	Stop          []string               `json:"stop,omitempty"`
+	Stream        bool                   `json:"stream,omitempty" example:"false"`
+	StreamOptions *struct {
+		IncludeUsage bool `json:"include_usage" example:"true"`
+	} `json:"stream_options,omitempty"`
+}
+
 type AiModel struct {
 	Name      string `json:"name" example:"adapter-a"`
 	Type      string `json:"type" example:"text-completion"`
 	Status    string `json:"status" example:"active"`
 	Version   string `json:"version" example:"1.0.0"`
 	MaxTokens int    `json:"max_tokens" example:"4096"`
+	// Capabilities as declared in the model registry
+	Capabilities []string `json:"capabilities"`
 }
 
 type AiModelsResponse struct {
@@ -182,8 +288,10 @@ type AiModelsResponse struct {
 }
 
 type AiModelStatusInfo struct {
-	Status string `json:"status" example:"online"`
-	Uptime string `json:"uptime" example:"99.99%"`
+	Status        string `json:"status" example:"online"`
+	Uptime        string `json:"uptime" example:"99.99%"`
+	RegistryState string `json:"registry_state" example:"active"`
+	Backends      int    `json:"backends" example:"1"`
 }
 
 type AiModelHealth struct {
@@ -193,9 +301,11 @@ type AiModelHealth struct {
 }
 
 type AiModelStatusResponse struct {
-	Model  string            `json:"model" example:"adapter-a"`
-	Status AiModelStatusInfo `json:"status"`
-	Health AiModelHealth     `json:"health"`
+	Model          string                    `json:"model" example:"adapter-a"`
+	Status         AiModelStatusInfo         `json:"status"`
+	Health         AiModelHealth             `json:"health"`
+	CircuitBreaker adapters.BreakerStatus    `json:"circuit_breaker"`
+	Resilience     adapters.ResiliencePolicy `json:"resilience"`
 }
 
 type AiGenericMessageResponse struct {
@@ -218,28 +328,57 @@ type AiMetricsResponse struct {
 	} `json:"metrics"`
 }
 
+type AiBatchRequestItem struct {
+	ID     string `json:"id,omitempty" example:"item-1"`
+	Prompt string `json:"prompt" example:"Hello"`
+	Model  string `json:"model,omitempty" example:"adapter-b"`
+}
+
 type AiBatchRequest struct {
-	Requests    []map[string]string `json:"requests"`
-	Model       string              `json:"model" example:"adapter-a"`
-	CallbackURL string              `json:"callback_url,omitempty" example:"https://example.com/callback"`
+	Requests []AiBatchRequestItem `json:"requests"`
+	// Shorthand: plain prompts, completed with the batch-level model
+	Inputs      []string `json:"inputs,omitempty"`
+	Model       string   `json:"model" example:"adapter-a"`
+	CallbackURL string   `json:"callback_url,omitempty" example:"https://example.com/callback"`
 }
 
 type AiBatchAccepted struct {
-	JobID               string `json:"job_id" example:"job_abc123"`
+	JobID               string `json:"job_id" example:"job-abc123"`
 	Status              string `json:"status" example:"queued"`
+	TotalRequests       int    `json:"total_requests" example:"2"`
 	EstimatedCompletion string `json:"estimated_completion" example:"2-5 minutes"`
 }
 
 type AiJobResult struct {
-	Index      int    `json:"index" example:"0"`
-	Completion string `json:"completion" example:"Sample completion for first prompt"`
-	Status     string `json:"status" example:"success"`
+	Index       int    `json:"index" example:"0"`
+	ID          string `json:"id,omitempty" example:"item-1"`
+	Prompt      string `json:"prompt" example:"Hello"`
+	Model       string `json:"model" example:"adapter-a"`
+	Status      string `json:"status" example:"success"`
+	Completion  string `json:"completion,omitempty" example:"A: Hello"`
+	Error       string `json:"error,omitempty"`
+	StartedAt   string `json:"started_at,omitempty" example:"2025-09-17T12:00:01Z"`
+	CompletedAt string `json:"completed_at,omitempty" example:"2025-09-17T12:00:02Z"`
+}
+
+type AiJobProgress struct {
+	Total      int `json:"total" example:"2"`
+	Completed  int `json:"completed" example:"1"`
+	Succeeded  int `json:"succeeded" example:"1"`
+	Failed     int `json:"failed" example:"0"`
+	Percentage int `json:"percentage" example:"50"`
 }
 
 type AiJobStatusResponse struct {
-	JobID       string        `json:"job_id" example:"job_abc123"`
-	Status      string        `json:"status" example:"completed"`
+	JobID       string        `json:"job_id" example:"job-abc123"`
+	Status      string        `json:"status" example:"running"`
+	Model       string        `json:"model,omitempty" example:"adapter-a"`
 	CreatedAt   string        `json:"created_at" example:"2025-09-17T12:00:00Z"`
-	CompletedAt string        `json:"completed_at" example:"2025-09-17T12:03:00Z"`
+	StartedAt   string        `json:"started_at,omitempty" example:"2025-09-17T12:00:01Z"`
+	CompletedAt string        `json:"completed_at,omitempty" example:"2025-09-17T12:03:00Z"`
+	Progress    AiJobProgress `json:"progress"`
 	Results     []AiJobResult `json:"results"`
+	// Present when the batch was submitted with a callback_url
+	CallbackURL  string `json:"callback_url,omitempty" example:"https://example.com/callback"`
+	WebhookState string `json:"webhook_state,omitempty" example:"delivered"`
 }
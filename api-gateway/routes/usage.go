@@ -0,0 +1,385 @@
+package routes
+
+import (
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/weltschmerz/QA-Playground/api-gateway/adapters"
+	gwmiddleware "github.com/weltschmerz/QA-Playground/api-gateway/middleware"
+)
+
+// maxUsageRecords bounds the in-memory request log and token ledger; the
+// oldest entries are dropped first. Quota counters are kept separately and
+// are not affected.
+const maxUsageRecords = 50000
+
+// usageRecord is one ledger entry: the tokens a caller spent on a model.
+type usageRecord struct {
+	Subject  string
+	Model    string
+	Endpoint string
+	Usage    adapters.Usage
+	At       time.Time
+}
+
+var (
+	usageMu     sync.RWMutex
+	requestLog  []gwmiddleware.RequestRecord
+	tokenLedger []usageRecord
+	// quotaUsed holds tokens per subject per period key ("day:2006-01-02",
+	// "month:2006-01").
+	quotaUsed = map[string]map[string]int{}
+
+	tokensUsed = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "gateway_tokens_total",
+		Help: "Tokens consumed through the gateway, by model and type (prompt|completion).",
+	}, []string{"model", "type"})
+	quotaRejections = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "gateway_quota_rejections_total",
+		Help: "Requests rejected because the caller's token quota was exhausted, by period.",
+	}, []string{"period"})
+)
+
+// RecordRequest adds a finished request to the analytics request log. Health,
+// metrics, docs and static UI requests are not recorded.
+func RecordRequest(r gwmiddleware.RequestRecord) {
+	if untrackedPath(r.Path) {
+		return
+	}
+	usageMu.Lock()
+	defer usageMu.Unlock()
+	requestLog = append(requestLog, r)
+	if len(requestLog) > maxUsageRecords {
+		requestLog = append(requestLog[:0:0], requestLog[len(requestLog)-maxUsageRecords:]...)
+	}
+}
+
+func untrackedPath(p string) bool {
+	if p == "/" || p == "/healthz" || p == "/metrics" || p == "/docs" {
+		return true
+	}
+	for _, prefix := range []string{"/swagger/", "/docs/", "/specs/", "/ui", "/demo"} {
+		if strings.HasPrefix(p, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// estimateUsage is the gateway's ~4 characters per token estimate, used when
+// an adapter does not report usage.
+func estimateUsage(prompt, text string) adapters.Usage {
+	u := adapters.Usage{PromptTokens: len(prompt) / 4, CompletionTokens: len(text) / 4}
+	u.TotalTokens = u.PromptTokens + u.CompletionTokens
+	return u
+}
+
+// recordUsage writes a ledger entry and charges the tokens to the subject's
+// daily and monthly quota.
+func recordUsage(subject, model, endpoint string, u adapters.Usage) {
+	if u.TotalTokens == 0 {
+		u.TotalTokens = u.PromptTokens + u.CompletionTokens
+	}
+	tokensUsed.WithLabelValues(model, "prompt").Add(float64(u.PromptTokens))
+	tokensUsed.WithLabelValues(model, "completion").Add(float64(u.CompletionTokens))
+	now := time.Now().UTC()
+	day, month := quotaPeriods(now)
+
+	usageMu.Lock()
+	defer usageMu.Unlock()
+	tokenLedger = append(tokenLedger, usageRecord{Subject: subject, Model: model, Endpoint: endpoint, Usage: u, At: now})
+	if len(tokenLedger) > maxUsageRecords {
+		tokenLedger = append(tokenLedger[:0:0], tokenLedger[len(tokenLedger)-maxUsageRecords:]...)
+	}
+	used := quotaUsed[subject]
+	if used == nil {
+		used = map[string]int{}
+		quotaUsed[subject] = used
+	}
+	for k := range used {
+		if k != day && k != month {
+			delete(used, k)
+		}
+	}
+	used[day] += u.TotalTokens
+	used[month] += u.TotalTokens
+}
+
+func quotaPeriods(t time.Time) (day, month string) {
+	return "day:" + t.Format("2006-01-02"), "month:" + t.Format("2006-01")
+}
+
+// quotaLimits returns the subject's daily and monthly token quotas from the
+// system config; 0 means unlimited.
+func quotaLimits(subject string) (daily, monthly int) {
+	configMu.RLock()
+	defer configMu.RUnlock()
+	q := systemConfig["quotas"].(gin.H)
+	if enabled, _ := q["enabled"].(bool); !enabled {
+		return 0, 0
+	}
+	daily, _ = toInt(q["daily_tokens"])
+	monthly, _ = toInt(q["monthly_tokens"])
+	if o, ok := q["overrides"].(gin.H)[subject].(gin.H); ok {
+		if n, ok := toInt(o["daily_tokens"]); ok {
+			daily = n
+		}
+		if n, ok := toInt(o["monthly_tokens"]); ok {
+			monthly = n
+		}
+	}
+	return daily, monthly
+}
+
+// quotaUsage returns the tokens the subject has used today and this month.
+func quotaUsage(subject string) (daily, monthly int) {
+	day, month := quotaPeriods(time.Now().UTC())
+	usageMu.RLock()
+	defer usageMu.RUnlock()
+	return quotaUsed[subject][day], quotaUsed[subject][month]
+}
+
+// quotaError describes an exhausted quota.
+type quotaError struct {
+	Period   string `json:"period"` // daily|monthly
+	Limit    int    `json:"limit"`
+	Used     int    `json:"used"`
+	ResetsAt string `json:"resets_at"`
+	resetIn  time.Duration
+}
+
+// retryAfter is the Retry-After value: seconds until the quota period resets.
+func (qe *quotaError) retryAfter() string {
+	return strconv.Itoa(int(math.Ceil(qe.resetIn.Seconds())))
+}
+
+// checkQuota returns a quotaError when the subject has no tokens left for the
+// current day or month. Anonymous calls are not metered.
+func checkQuota(subject string) *quotaError {
+	if subject == "" {
+		return nil
+	}
+	dailyLimit, monthlyLimit := quotaLimits(subject)
+	if dailyLimit == 0 && monthlyLimit == 0 {
+		return nil
+	}
+	daily, monthly := quotaUsage(subject)
+	now := time.Now().UTC()
+	var qe *quotaError
+	if monthlyLimit > 0 && monthly >= monthlyLimit {
+		reset := time.Date(now.Year(), now.Month()+1, 1, 0, 0, 0, 0, time.UTC)
+		qe = &quotaError{Period: "monthly", Limit: monthlyLimit, Used: monthly, ResetsAt: reset.Format(time.RFC3339), resetIn: reset.Sub(now)}
+	} else if dailyLimit > 0 && daily >= dailyLimit {
+		reset := time.Date(now.Year(), now.Month(), now.Day()+1, 0, 0, 0, 0, time.UTC)
+		qe = &quotaError{Period: "daily", Limit: dailyLimit, Used: daily, ResetsAt: reset.Format(time.RFC3339), resetIn: reset.Sub(now)}
+	}
+	if qe != nil {
+		quotaRejections.WithLabelValues(qe.Period).Inc()
+	}
+	return qe
+}
+
+// withinQuota answers 429 with Retry-After and returns false when the caller's
+// token quota is exhausted.
+func withinQuota(c *gin.Context) bool {
+	qe := checkQuota(gwmiddleware.CallerSubject(c))
+	if qe == nil {
+		return true
+	}
+	c.Header("Retry-After", qe.retryAfter())
+	c.JSON(http.StatusTooManyRequests, gin.H{"error": "token quota exceeded", "quota": qe})
+	return false
+}
+
+// usageTotals aggregates ledger entries.
+type usageTotals struct {
+	Requests         int `json:"requests"`
+	PromptTokens     int `json:"prompt_tokens"`
+	CompletionTokens int `json:"completion_tokens"`
+	TotalTokens      int `json:"total_tokens"`
+}
+
+func (t *usageTotals) add(u adapters.Usage) {
+	t.Requests++
+	t.PromptTokens += u.PromptTokens
+	t.CompletionTokens += u.CompletionTokens
+	t.TotalTokens += u.TotalTokens
+}
+
+type modelUsage struct {
+	Model string `json:"model"`
+	usageTotals
+}
+
+type subjectUsage struct {
+	Subject string       `json:"subject"`
+	Totals  usageTotals  `json:"totals"`
+	ByModel []modelUsage `json:"by_model"`
+}
+
+// summarizeUsage aggregates the ledger entries between start and end (zero
+// means unbounded) per subject and model, sorted by subject.
+func summarizeUsage(start, end time.Time, keep func(usageRecord) bool) []subjectUsage {
+	bySubject := map[string]map[string]*usageTotals{}
+	usageMu.RLock()
+	for _, r := range tokenLedger {
+		if !inRange(r.At, start, end) || !keep(r) {
+			continue
+		}
+		models := bySubject[r.Subject]
+		if models == nil {
+			models = map[string]*usageTotals{}
+			bySubject[r.Subject] = models
+		}
+		if models[r.Model] == nil {
+			models[r.Model] = &usageTotals{}
+		}
+		models[r.Model].add(r.Usage)
+	}
+	usageMu.RUnlock()
+
+	out := make([]subjectUsage, 0, len(bySubject))
+	for subject, models := range bySubject {
+		su := subjectUsage{Subject: subject, ByModel: []modelUsage{}}
+		for model, t := range models {
+			su.ByModel = append(su.ByModel, modelUsage{Model: model, usageTotals: *t})
+			su.Totals.Requests += t.Requests
+			su.Totals.PromptTokens += t.PromptTokens
+			su.Totals.CompletionTokens += t.CompletionTokens
+			su.Totals.TotalTokens += t.TotalTokens
+		}
+		sort.Slice(su.ByModel, func(i, j int) bool { return su.ByModel[i].Model < su.ByModel[j].Model })
+		out = append(out, su)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Subject < out[j].Subject })
+	return out
+}
+
+func inRange(t, start, end time.Time) bool {
+	return (start.IsZero() || !t.Before(start)) && (end.IsZero() || !t.After(end))
+}
+
+// parseDateRange reads the optional RFC3339 start_date/end_date query
+// parameters, answering 400 and returning false when they are invalid.
+func parseDateRange(c *gin.Context) (start, end time.Time, ok bool) {
+	var err error
+	if s := strings.TrimSpace(c.Query("start_date")); s != "" {
+		if start, err = time.Parse(time.RFC3339, s); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid date format"})
+			return start, end, false
+		}
+	}
+	if s := strings.TrimSpace(c.Query("end_date")); s != "" {
+		if end, err = time.Parse(time.RFC3339, s); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid date format"})
+			return start, end, false
+		}
+	}
+	if !start.IsZero() && !end.IsZero() && end.Before(start) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid date range"})
+		return start, end, false
+	}
+	return start, end, true
+}
+
+func quotaView(subject string) gin.H {
+	dailyLimit, monthlyLimit := quotaLimits(subject)
+	daily, monthly := quotaUsage(subject)
+	remaining := func(limit, used int) any {
+		if limit == 0 {
+			return nil
+		}
+		return max(limit-used, 0)
+	}
+	return gin.H{
+		"daily_tokens":      dailyLimit,
+		"monthly_tokens":    monthlyLimit,
+		"daily_used":        daily,
+		"monthly_used":      monthly,
+		"daily_remaining":   remaining(dailyLimit, daily),
+		"monthly_remaining": remaining(monthlyLimit, monthly),
+	}
+}
+
+// GetMyUsage returns the caller's own token usage and quota
+// @Summary Get own token usage
+// @Description Returns the calling user's (or API key's) token usage per model from the usage ledger, with the current daily/monthly quota. A quota of 0 means unlimited.
+// @Tags ai
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Produce json
+// @Param start_date query string false "RFC3339 start date"
+// @Param end_date query string false "RFC3339 end date"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Router /v1/ai/usage [get]
+func GetMyUsage(c *gin.Context) {
+	start, end, ok := parseDateRange(c)
+	if !ok {
+		return
+	}
+	subject := gwmiddleware.CallerSubject(c)
+	usage := subjectUsage{Subject: subject, ByModel: []modelUsage{}}
+	if s := summarizeUsage(start, end, func(r usageRecord) bool { return r.Subject == subject }); len(s) > 0 {
+		usage = s[0]
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"subject":     subject,
+		"totals":      usage.Totals,
+		"by_model":    usage.ByModel,
+		"quota":       quotaView(subject),
+		"time_period": gin.H{"start": c.Query("start_date"), "end": c.Query("end_date")},
+	})
+}
+
+// GetAllUsage returns token usage for every caller
+// @Summary Get token usage for all callers
+// @Description Admin view (service API key or admin user) of the usage ledger: token usage per subject ("user:<id>" or "api_key:<sha256 prefix>") and model, with each subject's quota.
+// @Tags admin
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Produce json
+// @Param start_date query string false "RFC3339 start date"
+// @Param end_date query string false "RFC3339 end date"
+// @Param subject query string false "Only this subject"
+// @Param model query string false "Only this model"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Router /v1/admin/usage [get]
+func GetAllUsage(c *gin.Context) {
+	if !isPrivilegedCaller(c) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "admin access required"})
+		return
+	}
+	start, end, ok := parseDateRange(c)
+	if !ok {
+		return
+	}
+	subject, model := strings.TrimSpace(c.Query("subject")), strings.TrimSpace(c.Query("model"))
+	subjects := summarizeUsage(start, end, func(r usageRecord) bool {
+		return (subject == "" || r.Subject == subject) && (model == "" || r.Model == model)
+	})
+	var totals usageTotals
+	out := make([]gin.H, 0, len(subjects))
+	for _, s := range subjects {
+		totals.Requests += s.Totals.Requests
+		totals.PromptTokens += s.Totals.PromptTokens
+		totals.CompletionTokens += s.Totals.CompletionTokens
+		totals.TotalTokens += s.Totals.TotalTokens
+		out = append(out, gin.H{"subject": s.Subject, "totals": s.Totals, "by_model": s.ByModel, "quota": quotaView(s.Subject)})
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"subjects":    out,
+		"totals":      totals,
+		"time_period": gin.H{"start": c.Query("start_date"), "end": c.Query("end_date")},
+	})
+}
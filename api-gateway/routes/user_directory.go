@@ -0,0 +1,86 @@
+package routes
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	users "github.com/DrWeltschmerz/users-core"
+	"github.com/gin-gonic/gin"
+)
+
+// userDirectory resolves users-core accounts, e.g. notification senders and
+// recipients.
+type userDirectory struct {
+	svc  *users.Service
+	repo users.UserRepository
+}
+
+// userDir is nil until main calls SetUserDirectory; identities then come from
+// the token alone.
+var userDir *userDirectory
+
+// SetUserDirectory wires the users-core service and repository used to
+// resolve user IDs and emails. Call it before serving requests.
+func SetUserDirectory(svc *users.Service, repo users.UserRepository) {
+	userDir = &userDirectory{svc: svc, repo: repo}
+}
+
+func lookupUser(id string) *users.User {
+	if userDir == nil || id == "" {
+		return nil
+	}
+	u, err := userDir.svc.GetUserByID(context.Background(), id)
+	if err != nil {
+		return nil
+	}
+	return u
+}
+
+func lookupUserByEmail(email string) *users.User {
+	if userDir == nil || email == "" {
+		return nil
+	}
+	u, err := userDir.repo.GetByEmail(context.Background(), strings.TrimSpace(email))
+	if err != nil {
+		return nil
+	}
+	return u
+}
+
+func isAdminUser(u *users.User) bool {
+	return userDir != nil && u != nil && userDir.svc.IsAdmin(u)
+}
+
+// isPrivilegedCaller reports whether the request comes with the service API
+// key or from an admin user.
+func isPrivilegedCaller(c *gin.Context) bool {
+	id := c.GetString("userID")
+	return id == "" || isAdminUser(lookupUser(id))
+}
+
+// RequireAdmin answers 403 unless the caller is the service API key or an
+// admin user. Mount it after JwtOrAPIKeyMiddleware.
+func RequireAdmin(c *gin.Context) {
+	if !isPrivilegedCaller(c) {
+		c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "admin access required"})
+		return
+	}
+	c.Next()
+}
+
+// resolveRecipient accepts an email or a users-core user ID and returns the
+// recipient's email and, when the account exists, its user ID.
+func resolveRecipient(r string) (email, userID string, ok bool) {
+	r = strings.TrimSpace(r)
+	if isValidEmail(r) {
+		if u := lookupUserByEmail(r); u != nil {
+			return r, u.ID, true
+		}
+		return r, "", true
+	}
+	if u := lookupUser(r); u != nil && u.Email != "" {
+		return u.Email, u.ID, true
+	}
+	return "", "", false
+}
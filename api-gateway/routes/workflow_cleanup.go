@@ -0,0 +1,292 @@
+package routes
+
+import (
+	"context"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// workflowRemoval reports what removing a workflow cleaned up.
+type workflowRemoval struct {
+	WorkflowID          string   `json:"workflow_id"`
+	CancelledExecutions []string `json:"cancelled_executions"`
+	DeletedExecutions   int      `json:"executions_deleted"`
+	RetainedExecutions  int      `json:"executions_retained"`
+	RetainedUntil       string   `json:"retained_until,omitempty"`
+	DeletedTriggers     []string `json:"triggers_deleted"`
+}
+
+// executionRetention is how long executions of a deleted workflow stay
+// readable, read live from workflows.execution_retention (seconds) in the
+// system config. 0 deletes them with the workflow.
+func executionRetention() time.Duration {
+	configMu.RLock()
+	defer configMu.RUnlock()
+	n, _ := toInt(systemConfig["workflows"].(gin.H)["execution_retention"])
+	return time.Duration(max(n, 0)) * time.Second
+}
+
+// runningExecutions returns the unfinished runs of a workflow, oldest first.
+// Caller holds execMu.
+func runningExecutions(workflowID string) []*workflowRun {
+	var active []*workflowRun
+	for _, r := range runs {
+		if r.wf.ID == workflowID {
+			active = append(active, r)
+		}
+	}
+	sort.Slice(active, func(i, j int) bool { return active[i].ex.seq < active[j].ex.seq })
+	return active
+}
+
+func runIDs(active []*workflowRun) []string {
+	ids := make([]string, 0, len(active))
+	for _, r := range active {
+		ids = append(ids, r.ex.ExecutionID)
+	}
+	return ids
+}
+
+// removeWorkflow deletes a workflow with its versions and triggers, and
+// deletes or retains its executions per executionRetention. Running
+// executions block the removal (their IDs are returned) unless force is set,
+// in which case they are cancelled first. found is false for an unknown ID.
+func removeWorkflow(id string, force bool) (rem workflowRemoval, running []string, found bool) {
+	wfMu.RLock()
+	_, found = wfStore[id]
+	wfMu.RUnlock()
+	if !found {
+		return rem, nil, false
+	}
+	execMu.RLock()
+	active := runningExecutions(id)
+	execMu.RUnlock()
+	if len(active) > 0 && !force {
+		return rem, runIDs(active), true
+	}
+
+	rem = workflowRemoval{WorkflowID: id, CancelledExecutions: runIDs(active)}
+	for _, r := range active {
+		r.cancel()
+	}
+	wait, stop := context.WithTimeout(context.Background(), cancelWait)
+	for _, r := range active {
+		select {
+		case <-r.done:
+		case <-wait.Done():
+		}
+	}
+	stop()
+
+	// triggers go first so nothing starts the workflow while it is removed
+	rem.DeletedTriggers = removeTriggers(id)
+	wfMu.Lock()
+	delete(wfStore, id)
+	delete(wfVersions, id)
+	wfMu.Unlock()
+
+	retention := executionRetention()
+	until := time.Now().UTC().Add(retention)
+	execMu.Lock()
+	for execID, ex := range execStore {
+		if ex.WorkflowID != id || !ex.retainUntil.IsZero() {
+			continue
+		}
+		// a run started after the check above has no workflow left either
+		if r := runs[execID]; r != nil {
+			r.cancel()
+			rem.CancelledExecutions = append(rem.CancelledExecutions, execID)
+		}
+		if retention > 0 {
+			ex.retainUntil = until
+			ex.RetainedUntil = until.Format(time.RFC3339)
+			rem.RetainedExecutions++
+			continue
+		}
+		delete(execStore, execID)
+		rem.DeletedExecutions++
+	}
+	delete(latestExec, id)
+	execMu.Unlock()
+	if rem.RetainedExecutions > 0 {
+		rem.RetainedUntil = until.Format(time.RFC3339)
+		time.AfterFunc(retention, dropExpiredExecutions)
+	}
+	return rem, nil, true
+}
+
+// dropExpiredExecutions deletes retained executions whose retention is over.
+func dropExpiredExecutions() {
+	now := time.Now()
+	execMu.Lock()
+	defer execMu.Unlock()
+	for execID, ex := range execStore {
+		if !ex.retainUntil.IsZero() && !ex.retainUntil.After(now) {
+			delete(execStore, execID)
+		}
+	}
+}
+
+// DeleteWorkflow removes a workflow
+// @Summary Delete workflow
+// @Description Deletes the workflow with its versions and triggers. Its executions are deleted too, or kept readable for workflows.execution_retention seconds (system config). A workflow with running executions is refused with 409 unless force=true, which cancels them.
+// @Tags workflows
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Produce json
+// @Param workflowId path string true "Workflow ID"
+// @Param force query bool false "Cancel running executions"
+// @Success 200 {object} map[string]interface{}
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]interface{}
+// @Router /v1/workflows/{workflowId} [delete]
+func DeleteWorkflow(c *gin.Context) {
+	id := c.Param("workflowId")
+	rem, running, found := removeWorkflow(id, c.Query("force") == "true")
+	switch {
+	case !found:
+		c.JSON(http.StatusNotFound, gin.H{"error": "workflow not found"})
+	case running != nil:
+		c.JSON(http.StatusConflict, gin.H{"error": "workflow has running executions; use force=true to cancel them", "running_executions": running})
+	default:
+		c.JSON(http.StatusOK, rem)
+	}
+}
+
+// PurgeWorkflows removes many workflows at once
+// @Summary Purge workflows
+// @Description Deletes every workflow matching all given selectors (ids, name contains, status), or every workflow with all=true, like DELETE /v1/workflows/{id}. Workflows with running executions are skipped unless force is set.
+// @Tags workflows
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Router /v1/workflows/purge [post]
+func PurgeWorkflows(c *gin.Context) {
+	var payload struct {
+		IDs    []string `json:"ids"`
+		Name   string   `json:"name"`
+		Status string   `json:"status"`
+		All    bool     `json:"all"`
+		Force  bool     `json:"force"`
+	}
+	if err := c.BindJSON(&payload); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid payload"})
+		return
+	}
+	name, status := strings.ToLower(strings.TrimSpace(payload.Name)), strings.TrimSpace(payload.Status)
+	if payload.IDs == nil && name == "" && status == "" && !payload.All {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "give ids, name or status, or all=true"})
+		return
+	}
+	ids := map[string]bool{}
+	for _, id := range payload.IDs {
+		ids[id] = true
+	}
+	wfMu.RLock()
+	matched := make([]string, 0)
+	for id, wf := range wfStore {
+		if (payload.IDs != nil && !ids[id]) ||
+			(name != "" && !strings.Contains(strings.ToLower(wf.Name), name)) ||
+			(status != "" && !strings.EqualFold(wf.Status, status)) {
+			continue
+		}
+		matched = append(matched, id)
+	}
+	wfMu.RUnlock()
+	sort.Strings(matched)
+
+	deleted := make([]workflowRemoval, 0, len(matched))
+	skipped := make([]gin.H, 0)
+	executionsDeleted, executionsRetained := 0, 0
+	for _, id := range matched {
+		rem, running, found := removeWorkflow(id, payload.Force)
+		switch {
+		case !found: // removed concurrently
+		case running != nil:
+			skipped = append(skipped, gin.H{"workflow_id": id, "running_executions": running})
+		default:
+			deleted = append(deleted, rem)
+			executionsDeleted += rem.DeletedExecutions
+			executionsRetained += rem.RetainedExecutions
+		}
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"deleted":             deleted,
+		"skipped":             skipped,
+		"total_deleted":       len(deleted),
+		"executions_deleted":  executionsDeleted,
+		"executions_retained": executionsRetained,
+	})
+}
+
+// ArchiveWorkflow archives a workflow
+// @Summary Archive workflow
+// @Description Archived workflows are hidden from GET /v1/workflows (unless status=archived or include_archived=true), cannot be executed or changed, and their triggers do not fire. Running executions continue.
+// @Tags workflows
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Produce json
+// @Param workflowId path string true "Workflow ID"
+// @Success 200 {object} Workflow
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /v1/workflows/{workflowId}/archive [post]
+func ArchiveWorkflow(c *gin.Context) {
+	setArchived(c, true)
+}
+
+// UnarchiveWorkflow restores an archived workflow
+// @Summary Unarchive workflow
+// @Description Returns an archived workflow to active when it has a published version, to draft otherwise
+// @Tags workflows
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Produce json
+// @Param workflowId path string true "Workflow ID"
+// @Success 200 {object} Workflow
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /v1/workflows/{workflowId}/unarchive [post]
+func UnarchiveWorkflow(c *gin.Context) {
+	setArchived(c, false)
+}
+
+func setArchived(c *gin.Context, archived bool) {
+	id := c.Param("workflowId")
+	wfMu.Lock()
+	wf, ok := wfStore[id]
+	if !ok {
+		wfMu.Unlock()
+		c.JSON(http.StatusNotFound, gin.H{"error": "workflow not found"})
+		return
+	}
+	if (wf.Status == "archived") == archived {
+		wfMu.Unlock()
+		msg := "workflow is already archived"
+		if !archived {
+			msg = "workflow is not archived"
+		}
+		c.JSON(http.StatusConflict, gin.H{"error": msg, "status": wf.Status})
+		return
+	}
+	now := time.Now().UTC().Format(time.RFC3339)
+	switch {
+	case archived:
+		wf.Status, wf.ArchivedAt = "archived", now
+	case wf.PublishedVersion > 0:
+		wf.Status, wf.ArchivedAt = "active", ""
+	default:
+		wf.Status, wf.ArchivedAt = "draft", ""
+	}
+	wf.UpdatedAt = now
+	wfStore[id] = wf
+	wfMu.Unlock()
+	c.JSON(http.StatusOK, wf)
+}
@@ -0,0 +1,245 @@
+package routes
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gopkg.in/yaml.v3"
+)
+
+// workflowDocAPIVersion identifies the definition document format.
+const workflowDocAPIVersion = "qa-playground/workflow/v1"
+
+// maxWorkflowDocument bounds an imported document.
+const maxWorkflowDocument = 1 << 20
+
+// workflowSchemaJSON is the published JSON Schema of definition documents
+// (GET /v1/workflows/schema); imports are validated against it.
+//
+//go:embed workflow.schema.json
+var workflowSchemaJSON []byte
+
+var workflowSchema = mustParseSchema(workflowSchemaJSON)
+
+// workflowDocument is the portable form of a workflow definition.
+type workflowDocument struct {
+	APIVersion  string          `json:"apiVersion" yaml:"apiVersion"`
+	Kind        string          `json:"kind" yaml:"kind"`
+	Name        string          `json:"name" yaml:"name"`
+	Description string          `json:"description,omitempty" yaml:"description,omitempty"`
+	Metadata    map[string]any  `json:"metadata,omitempty" yaml:"metadata,omitempty"`
+	Steps       []WorkflowStep  `json:"steps" yaml:"steps"`
+	Source      *documentSource `json:"source,omitempty" yaml:"source,omitempty"`
+}
+
+// documentSource records where an exported document came from.
+type documentSource struct {
+	WorkflowID string `json:"workflow_id" yaml:"workflow_id"`
+	Version    int    `json:"version" yaml:"version"`
+	ExportedAt string `json:"exported_at" yaml:"exported_at"`
+}
+
+// GetWorkflowSchema returns the definition document schema
+// @Summary Workflow definition JSON Schema
+// @Description JSON Schema of the documents accepted by /v1/workflows/import and produced by /export
+// @Tags workflows
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Router /v1/workflows/schema [get]
+func GetWorkflowSchema(c *gin.Context) {
+	c.Data(http.StatusOK, "application/schema+json", workflowSchemaJSON)
+}
+
+// ExportWorkflow returns a workflow as a definition document
+// @Summary Export workflow
+// @Description The workflow definition (current version, or ?version=n) as a versioned document that /v1/workflows/import accepts
+// @Tags workflows
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Produce json
+// @Produce application/yaml
+// @Param workflowId path string true "Workflow ID"
+// @Param format query string false "json (default) or yaml"
+// @Param version query int false "Version to export"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /v1/workflows/{workflowId}/export [get]
+func ExportWorkflow(c *gin.Context) {
+	id := c.Param("workflowId")
+	format := strings.ToLower(c.DefaultQuery("format", "json"))
+	if format != "json" && format != "yaml" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "format must be json or yaml"})
+		return
+	}
+	wf, ok := workflowExists(c, id)
+	if !ok {
+		return
+	}
+	if raw := c.Query("version"); raw != "" {
+		v, ok := findVersion(c, id, raw)
+		if !ok {
+			return
+		}
+		wf.Name, wf.Description, wf.Steps, wf.Metadata, wf.Version = v.Name, v.Description, v.Steps, v.Metadata, v.Version
+	}
+	doc := workflowDocument{
+		APIVersion:  workflowDocAPIVersion,
+		Kind:        "Workflow",
+		Name:        wf.Name,
+		Description: wf.Description,
+		Metadata:    wf.Metadata,
+		Steps:       wf.Steps,
+		Source:      &documentSource{WorkflowID: wf.ID, Version: wf.Version, ExportedAt: time.Now().UTC().Format(time.RFC3339)},
+	}
+	if format == "yaml" {
+		out, err := yaml.Marshal(doc)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to encode workflow"})
+			return
+		}
+		c.Data(http.StatusOK, "application/yaml", out)
+		return
+	}
+	c.JSON(http.StatusOK, doc)
+}
+
+// ImportWorkflow creates a workflow from a definition document
+// @Summary Import workflow
+// @Description Validates a JSON or YAML definition document against the published schema (GET /v1/workflows/schema) and the step reference rules, reporting every error with its JSON pointer. Valid documents are saved as a new draft workflow unless dry_run is set.
+// @Tags workflows
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Accept json
+// @Accept application/yaml
+// @Produce json
+// @Param format query string false "json or yaml (default: from Content-Type)"
+// @Param dry_run query bool false "Validate only"
+// @Success 200 {object} map[string]interface{}
+// @Success 201 {object} Workflow
+// @Failure 400 {object} map[string]interface{}
+// @Router /v1/workflows/import [post]
+func ImportWorkflow(c *gin.Context) {
+	raw, err := io.ReadAll(io.LimitReader(c.Request.Body, maxWorkflowDocument+1))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "failed to read document"})
+		return
+	}
+	if len(raw) > maxWorkflowDocument {
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": "document too large"})
+		return
+	}
+	format := strings.ToLower(c.Query("format"))
+	if format == "" {
+		format = "json"
+		if strings.Contains(c.ContentType(), "yaml") {
+			format = "yaml"
+		}
+	}
+	var parsed any
+	switch format {
+	case "json":
+		err = json.Unmarshal(raw, &parsed)
+	case "yaml":
+		// decode, then round-trip through JSON so values have JSON types
+		var y any
+		if err = yaml.Unmarshal(raw, &y); err == nil {
+			var b []byte
+			if b, err = json.Marshal(y); err == nil {
+				err = json.Unmarshal(b, &parsed)
+			}
+		}
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "format must be json or yaml"})
+		return
+	}
+	if err != nil {
+		invalidDocument(c, []schemaError{{Pointer: "", Message: fmt.Sprintf("invalid %s: %v", format, err)}})
+		return
+	}
+
+	errs := workflowSchema.Validate(parsed)
+	var doc workflowDocument
+	b, _ := json.Marshal(parsed)
+	if json.Unmarshal(b, &doc) == nil {
+		errs = append(errs, stepReferenceErrors(doc.Steps, len(errs) == 0)...)
+	}
+	if len(errs) > 0 {
+		invalidDocument(c, errs)
+		return
+	}
+
+	wf := Workflow{Name: doc.Name, Description: doc.Description, Metadata: doc.Metadata, Steps: doc.Steps}
+	if dry, _ := strconv.ParseBool(c.Query("dry_run")); dry {
+		c.JSON(http.StatusOK, gin.H{"valid": true, "dry_run": true, "workflow": wf})
+		return
+	}
+	storeNewWorkflow(&wf, "imported")
+	c.JSON(http.StatusCreated, wf)
+}
+
+func invalidDocument(c *gin.Context, errs []schemaError) {
+	c.JSON(http.StatusBadRequest, gin.H{"error": "invalid workflow definition", "valid": false, "errors": errs})
+}
+
+// stepReferenceErrors reports the validateSteps rules the schema cannot
+// express: unique IDs, depends_on/on_reject/on_failure targets, quorum versus
+// approvers and template references. When full is set (the schema passed) a
+// remaining validateSteps failure, such as a cycle, is reported on /steps.
+func stepReferenceErrors(steps []WorkflowStep, full bool) []schemaError {
+	var errs []schemaError
+	add := func(ptr, format string, args ...any) {
+		errs = append(errs, schemaError{Pointer: ptr, Message: fmt.Sprintf(format, args...)})
+	}
+	byID := map[string]WorkflowStep{}
+	for i, s := range steps {
+		if _, dup := byID[s.ID]; dup && s.ID != "" {
+			add(fmt.Sprintf("/steps/%d/id", i), "duplicate step id %s", s.ID)
+		}
+		byID[s.ID] = s
+	}
+	branchTarget := func(ptr, field, target, self string) {
+		t, ok := byID[target]
+		switch {
+		case !ok || target == self:
+			add(ptr, "%s must name another step", field)
+		case len(t.DependsOn) > 0:
+			add(ptr, "%s step %s must not have depends_on", field, target)
+		}
+	}
+	for i, s := range steps {
+		ptr := fmt.Sprintf("/steps/%d", i)
+		for j, d := range s.DependsOn {
+			if _, ok := byID[d]; !ok {
+				add(fmt.Sprintf("%s/depends_on/%d", ptr, j), "unknown step %s", d)
+			}
+		}
+		if len(s.Approvers) > 0 && s.Quorum > len(s.Approvers) {
+			add(ptr+"/quorum", "exceeds the number of approvers")
+		}
+		if s.OnReject != "" {
+			branchTarget(ptr+"/on_reject", "on_reject", s.OnReject, s.ID)
+		}
+		if comp := s.compensatingStep(); comp != "" {
+			branchTarget(ptr+"/on_failure", "on_failure", comp, s.ID)
+		}
+		if err := validateTemplates(s, byID); err != nil {
+			add(ptr+"/config", "%s", err.Error())
+		}
+	}
+	if full && len(errs) == 0 {
+		if err := validateSteps(steps); err != nil {
+			add("/steps", "%s", err.Error())
+		}
+	}
+	return errs
+}
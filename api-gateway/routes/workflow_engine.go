@@ -0,0 +1,561 @@
+package routes
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Execution statuses: started -> in_progress | waiting_approval -> completed |
+// failed | timeout | cancelled.
+const (
+	execStarted         = "started"
+	execInProgress      = "in_progress"
+	execWaitingApproval = "waiting_approval"
+	execCompleted       = "completed"
+	execFailed          = "failed"
+	execTimeout         = "timeout"
+	execCancelled       = "cancelled"
+)
+
+// maxWorkflowHistory bounds the execution summaries kept on a workflow.
+const maxWorkflowHistory = 50
+
+var (
+	errStepTimeout     = errors.New("step timed out")
+	errStepRejected    = errors.New("rejected")
+	errNotApprover     = errors.New("approver is not allowed to decide this step")
+	errUnsupportedStep = errors.New("unsupported step type")
+)
+
+// stepState is the live state of one step within an execution.
+type stepState struct {
+	Status      string         `json:"status"` // pending|running|waiting|retrying|completed|failed|rejected|timeout|skipped|cancelled
+	Attempts    int            `json:"attempts,omitempty"`
+	WaitingFor  string         `json:"waiting_for,omitempty"`
+	StartedAt   string         `json:"started_at,omitempty"`
+	CompletedAt string         `json:"completed_at,omitempty"`
+	Input       map[string]any `json:"input,omitempty"` // config with templates resolved
+	Output      map[string]any `json:"output,omitempty"`
+	Error       string         `json:"error,omitempty"`
+
+	Approvals []approvalRecord `json:"approvals,omitempty"`
+	Required  int              `json:"approvals_required,omitempty"`
+}
+
+// approvalRecord is one approve/reject decision on a step.
+type approvalRecord struct {
+	Approver  string `json:"approver"`
+	Decision  string `json:"decision"` // approved|rejected
+	Comments  string `json:"comments,omitempty"`
+	Reason    string `json:"reason,omitempty"`
+	Timestamp string `json:"timestamp"`
+}
+
+// stepSignal resumes a step waiting for a human or external decision with
+// the decision that settled it.
+type stepSignal struct {
+	Approved bool
+	Decision approvalRecord
+}
+
+// decisionResult is what recordDecision reports back to the caller.
+type decisionResult struct {
+	ExecutionID string
+	StepID      string
+	Approvals   []approvalRecord
+	Required    int
+	Remaining   int
+	OnReject    string
+}
+
+// workflowRun is the executor state of a running execution. Guarded by execMu.
+type workflowRun struct {
+	wf      Workflow
+	ex      *workflowExecution
+	cancel  context.CancelFunc
+	done    chan struct{}              // closed once the execution has finished
+	signals map[string]chan stepSignal // steps waiting for a decision, by step ID
+
+	auth    string // Authorization of the caller that started the run
+	ns      string // notification tenant of that caller
+	subject string // usage subject of that caller
+}
+
+// runs holds the unfinished runs by execution ID. Guarded by execMu.
+var runs = map[string]*workflowRun{}
+
+// startExecution stores ex as the workflow's latest execution and runs it in
+// the background. Executions of the same workflow run independently.
+func startExecution(wf Workflow, ex *workflowExecution, auth, ns, subject string) {
+	ctx, cancel := context.WithCancel(context.Background())
+	run := &workflowRun{wf: wf, ex: ex, cancel: cancel, done: make(chan struct{}), signals: map[string]chan stepSignal{}, auth: auth, ns: ns, subject: subject}
+	ex.Steps = make(map[string]*stepState, len(wf.Steps))
+	for _, s := range wf.Steps {
+		ex.Steps[s.ID] = &stepState{Status: "pending"}
+	}
+	ex.Progress = "0%"
+	ex.appendHistory("execution_started", "", ex.Status, nil)
+
+	execMu.Lock()
+	execSeq++
+	ex.seq = execSeq
+	runs[ex.ExecutionID] = run
+	execStore[ex.ExecutionID] = ex
+	latestExec[wf.ID] = ex.ExecutionID
+	execMu.Unlock()
+
+	go run.execute(ctx)
+}
+
+type stepResult struct {
+	id     string
+	output map[string]any
+	err    error
+}
+
+// execute walks the step DAG: every step whose dependencies have completed is
+// started, independent steps in parallel. A step that still fails after its
+// retries stops the execution unless its on_failure continues or jumps to a
+// compensating step; steps that never started are marked skipped.
+func (r *workflowRun) execute(ctx context.Context) {
+	stepsCtx, stopSteps := context.WithCancel(ctx)
+	defer stopSteps()
+
+	results := make(chan stepResult)
+	started := map[string]bool{}
+	completed := map[string]bool{}
+	byID := map[string]WorkflowStep{}
+	// on_reject and on_failure targets only run once their step is
+	// rejected or fails
+	branch, activated := map[string]bool{}, map[string]bool{}
+	for _, s := range r.wf.Steps {
+		byID[s.ID] = s
+		if s.OnReject != "" {
+			branch[s.OnReject] = true
+		}
+		if comp := s.compensatingStep(); comp != "" {
+			branch[comp] = true
+		}
+	}
+	running := 0
+	var failure error
+	failedStep := ""
+
+	for {
+		if failure == nil {
+			for _, s := range r.wf.Steps {
+				if started[s.ID] || (branch[s.ID] && !activated[s.ID]) || !dependenciesMet(s, completed) {
+					continue
+				}
+				started[s.ID] = true
+				running++
+				var inputErr error
+				r.update(func(ex *workflowExecution) {
+					st := ex.Steps[s.ID]
+					st.Status = "running"
+					st.StartedAt = time.Now().UTC().Format(time.RFC3339)
+					ex.CurrentStep = s.ID
+					// resolve config templates against the outputs so far
+					s.Config, inputErr = resolveTemplates(s.Config, r.templateScope())
+					st.Input = s.Config
+					var details map[string]any
+					if s.Config != nil {
+						details = map[string]any{"input": s.Config}
+					}
+					ex.appendHistory("step_started", s.ID, st.Status, details)
+				})
+				go func(s WorkflowStep) {
+					if inputErr != nil {
+						results <- stepResult{id: s.ID, err: inputErr}
+						return
+					}
+					out, err := r.attemptStep(stepsCtx, s)
+					results <- stepResult{id: s.ID, output: out, err: err}
+				}(s)
+			}
+		}
+		if running == 0 {
+			break
+		}
+		res := <-results
+		running--
+		now := time.Now().UTC().Format(time.RFC3339)
+		switch {
+		case res.err == nil:
+			completed[res.id] = true
+			r.update(func(ex *workflowExecution) {
+				st := ex.Steps[res.id]
+				st.Status, st.CompletedAt, st.Output = "completed", now, res.output
+				ex.appendHistory("step_completed", res.id, st.Status, map[string]any{"output": res.output})
+			})
+		case errors.Is(res.err, errStepRejected) && byID[res.id].OnReject != "":
+			// rejected with a correction branch: dependents are skipped and
+			// the branch takes over
+			target := byID[res.id].OnReject
+			activated[target] = true
+			r.update(func(ex *workflowExecution) {
+				st := ex.Steps[res.id]
+				st.Status, st.CompletedAt, st.Error = "rejected", now, res.err.Error()
+				ex.appendHistory("step_rejected", res.id, st.Status, map[string]any{"error": res.err.Error(), "next_step": target})
+			})
+		case ctx.Err() != nil || (failure != nil && errors.Is(res.err, context.Canceled)):
+			// execution cancelled or stopped after another step failed
+			r.update(func(ex *workflowExecution) {
+				st := ex.Steps[res.id]
+				st.Status, st.CompletedAt = "cancelled", now
+				ex.appendHistory("step_cancelled", res.id, st.Status, nil)
+			})
+		default:
+			status, event := "failed", "step_failed"
+			switch {
+			case errors.Is(res.err, errStepTimeout):
+				status, event = "timeout", "step_timeout"
+			case errors.Is(res.err, errStepRejected):
+				status, event = "rejected", "step_rejected"
+			}
+			details := map[string]any{"error": res.err.Error()}
+			step := byID[res.id]
+			switch {
+			case step.OnFailure == onFailureContinue:
+				// dependents run as if the step had completed
+				completed[res.id] = true
+				details["on_failure"] = onFailureContinue
+			case step.compensatingStep() != "":
+				// dependents are skipped and the compensating step takes over
+				activated[step.OnFailure] = true
+				details["on_failure"] = step.OnFailure
+				details["next_step"] = step.OnFailure
+			case failure == nil:
+				failure, failedStep = res.err, res.id
+				stopSteps()
+			}
+			r.update(func(ex *workflowExecution) {
+				st := ex.Steps[res.id]
+				st.Status, st.CompletedAt, st.Error = status, now, res.err.Error()
+				ex.appendHistory(event, res.id, status, details)
+			})
+		}
+	}
+
+	r.finish(ctx, failure, failedStep)
+}
+
+// finish records the terminal execution status and releases the run.
+func (r *workflowRun) finish(ctx context.Context, failure error, failedStep string) {
+	status := execCompleted
+	switch {
+	case ctx.Err() != nil:
+		status = execCancelled
+	case errors.Is(failure, errStepTimeout):
+		status = execTimeout
+	case failure != nil:
+		status = execFailed
+	}
+	now := time.Now().UTC().Format(time.RFC3339)
+	var summary map[string]any
+	r.update(func(ex *workflowExecution) {
+		for _, s := range r.wf.Steps {
+			if st := ex.Steps[s.ID]; st.Status == "pending" {
+				st.Status = "skipped"
+			}
+		}
+		ex.Status = status
+		ex.CompletedAt = now
+		if failure != nil {
+			ex.Error = failure.Error()
+			ex.CurrentStep = failedStep
+		}
+		var details map[string]any
+		if ex.Error != "" {
+			details = map[string]any{"error": ex.Error}
+		}
+		ex.appendHistory("execution_"+status, "", status, details)
+		summary = map[string]any{
+			"execution_id": ex.ExecutionID,
+			"status":       status,
+			"started_at":   ex.StartedAt,
+			"completed_at": now,
+		}
+	})
+
+	execMu.Lock()
+	delete(runs, r.ex.ExecutionID)
+	execMu.Unlock()
+	defer close(r.done)
+
+	wfMu.Lock()
+	if wf, ok := wfStore[r.wf.ID]; ok {
+		wf.ExecutionHistory = append(wf.ExecutionHistory, summary)
+		if len(wf.ExecutionHistory) > maxWorkflowHistory {
+			wf.ExecutionHistory = wf.ExecutionHistory[len(wf.ExecutionHistory)-maxWorkflowHistory:]
+		}
+		wfStore[r.wf.ID] = wf
+	}
+	wfMu.Unlock()
+}
+
+// attemptStep runs the step, retrying failed attempts as its retry policy
+// allows. Rejections, cancellation and unsupported step types are not retried.
+func (r *workflowRun) attemptStep(ctx context.Context, s WorkflowStep) (map[string]any, error) {
+	for attempt := 1; ; attempt++ {
+		r.update(func(ex *workflowExecution) {
+			st := ex.Steps[s.ID]
+			st.Status, st.Attempts = "running", attempt
+		})
+		out, err := r.runStep(ctx, s)
+		if err == nil || attempt > s.retries() || ctx.Err() != nil ||
+			errors.Is(err, errStepRejected) || errors.Is(err, errUnsupportedStep) {
+			return out, err
+		}
+		delay := s.Retry.delay(attempt)
+		r.update(func(ex *workflowExecution) {
+			st := ex.Steps[s.ID]
+			st.Status = "retrying"
+			ex.appendHistory("step_retry", s.ID, st.Status, map[string]any{
+				"attempt":     attempt,
+				"error":       err.Error(),
+				"retry_in_ms": delay.Milliseconds(),
+			})
+		})
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
+
+// runStep runs one attempt of the step's handler, enforcing the step timeout.
+func (r *workflowRun) runStep(ctx context.Context, s WorkflowStep) (map[string]any, error) {
+	h, ok := stepHandlers[s.Type]
+	if !ok {
+		return nil, fmt.Errorf("%w %q", errUnsupportedStep, s.Type)
+	}
+	if s.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, time.Duration(s.Timeout)*time.Second)
+		defer cancel()
+	}
+	out, err := h(ctx, &stepRun{run: r, step: s})
+	if err != nil && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return nil, fmt.Errorf("%w after %ds", errStepTimeout, s.Timeout)
+	}
+	return out, err
+}
+
+// update applies fn to the execution under execMu and refreshes the derived
+// status and progress.
+func (r *workflowRun) update(fn func(ex *workflowExecution)) {
+	execMu.Lock()
+	defer execMu.Unlock()
+	fn(r.ex)
+	r.ex.refresh()
+}
+
+// stepRun is what a step handler sees of the execution.
+type stepRun struct {
+	run  *workflowRun
+	step WorkflowStep
+}
+
+// output returns a completed upstream step's output.
+func (sr *stepRun) output(stepID string) map[string]any {
+	execMu.RLock()
+	defer execMu.RUnlock()
+	if st, ok := sr.run.ex.Steps[stepID]; ok {
+		return st.Output
+	}
+	return nil
+}
+
+// decisions returns the approvals recorded on the step so far.
+func (sr *stepRun) decisions() []approvalRecord {
+	execMu.RLock()
+	defer execMu.RUnlock()
+	var out []approvalRecord
+	for _, a := range sr.run.ex.Steps[sr.step.ID].Approvals {
+		if a.Decision == "approved" {
+			out = append(out, a)
+		}
+	}
+	return out
+}
+
+// wait parks the step until Approve/Reject signals it or ctx ends. kind is
+// "approval" (the execution shows waiting_approval) or "external".
+func (sr *stepRun) wait(ctx context.Context, kind string) (stepSignal, error) {
+	ch := make(chan stepSignal, 1)
+	sr.run.update(func(ex *workflowExecution) {
+		sr.run.signals[sr.step.ID] = ch
+		st := ex.Steps[sr.step.ID]
+		st.Status, st.WaitingFor = "waiting", kind
+		st.Required = sr.step.requiredApprovals()
+		ex.CurrentStep = sr.step.ID
+		ex.appendHistory("step_waiting", sr.step.ID, st.Status, map[string]any{"waiting_for": kind})
+	})
+	defer sr.run.update(func(ex *workflowExecution) {
+		delete(sr.run.signals, sr.step.ID)
+		st := ex.Steps[sr.step.ID]
+		if st.Status == "waiting" {
+			st.Status = "running"
+		}
+		st.WaitingFor = ""
+	})
+	select {
+	case sig := <-ch:
+		return sig, nil
+	case <-ctx.Done():
+		return stepSignal{}, ctx.Err()
+	}
+}
+
+func dependenciesMet(s WorkflowStep, completed map[string]bool) bool {
+	for _, d := range s.DependsOn {
+		if !completed[d] {
+			return false
+		}
+	}
+	return true
+}
+
+// refresh derives status and progress from the step states. Caller holds execMu.
+func (ex *workflowExecution) refresh() {
+	done, waitingApproval, active := 0, false, false
+	for _, st := range ex.Steps {
+		switch st.Status {
+		case "completed":
+			done++
+		case "waiting":
+			active = true
+			if st.WaitingFor == "approval" {
+				waitingApproval = true
+			}
+		case "running", "retrying":
+			active = true
+		}
+	}
+	if len(ex.Steps) > 0 {
+		ex.Progress = fmt.Sprintf("%d%%", done*100/len(ex.Steps))
+	}
+	switch ex.Status {
+	case execCompleted, execFailed, execTimeout, execCancelled:
+		return
+	}
+	switch {
+	case waitingApproval:
+		ex.Status = execWaitingApproval
+	case active || done > 0:
+		ex.Status = execInProgress
+	}
+}
+
+// appendHistory adds an execution history entry. Caller holds execMu (or owns ex).
+func (ex *workflowExecution) appendHistory(event, stepID, status string, details map[string]any) {
+	entry := map[string]any{
+		"timestamp": time.Now().UTC().Format(time.RFC3339Nano),
+		"event":     event,
+		"status":    status,
+	}
+	if stepID != "" {
+		entry["step_id"] = stepID
+	}
+	for k, v := range details {
+		entry[k] = v
+	}
+	ex.History = append(ex.History, entry)
+}
+
+// snapshot returns a copy of ex that is safe to serialize after execMu is
+// released. Caller holds execMu.
+func (ex *workflowExecution) snapshot() workflowExecution {
+	cp := *ex
+	cp.History = append([]map[string]any(nil), ex.History...)
+	cp.Steps = make(map[string]*stepState, len(ex.Steps))
+	for id, st := range ex.Steps {
+		s := *st
+		s.Approvals = append([]approvalRecord(nil), st.Approvals...)
+		cp.Steps[id] = &s
+	}
+	return cp
+}
+
+// recordDecision applies a decision to stepID, or to the first step waiting
+// for one. Approvals are collected until the step's quorum is reached; a
+// single rejection settles the step. Caller holds execMu.
+func (r *workflowRun) recordDecision(stepID string, rec approvalRecord) (decisionResult, error) {
+	if stepID == "" {
+		for _, s := range r.wf.Steps {
+			if _, ok := r.signals[s.ID]; ok {
+				stepID = s.ID
+				break
+			}
+		}
+	}
+	ch, ok := r.signals[stepID]
+	if !ok {
+		return decisionResult{}, errors.New("no step is waiting for a decision")
+	}
+	var step WorkflowStep
+	for _, s := range r.wf.Steps {
+		if s.ID == stepID {
+			step = s
+		}
+	}
+	if len(step.Approvers) > 0 && !containsFold(step.Approvers, rec.Approver) {
+		return decisionResult{}, fmt.Errorf("%w: %s", errNotApprover, rec.Approver)
+	}
+	st := r.ex.Steps[stepID]
+	for _, a := range st.Approvals {
+		if strings.EqualFold(a.Approver, rec.Approver) {
+			return decisionResult{}, fmt.Errorf("%s already decided step %s", rec.Approver, stepID)
+		}
+	}
+	st.Approvals = append(st.Approvals, rec)
+	r.ex.appendHistory("decision_recorded", stepID, st.Status, map[string]any{
+		"approver":   rec.Approver,
+		"decision":   rec.Decision,
+		"comments":   rec.Comments,
+		"reason":     rec.Reason,
+		"decided_at": rec.Timestamp,
+	})
+
+	approvals := 0
+	for _, a := range st.Approvals {
+		if a.Decision == "approved" {
+			approvals++
+		}
+	}
+	res := decisionResult{
+		ExecutionID: r.ex.ExecutionID,
+		StepID:      stepID,
+		Approvals:   append([]approvalRecord(nil), st.Approvals...),
+		Required:    step.requiredApprovals(),
+	}
+	res.Remaining = max(res.Required-approvals, 0)
+	switch {
+	case rec.Decision == "rejected":
+		res.Remaining = 0
+		res.OnReject = step.OnReject
+		ch <- stepSignal{Decision: rec}
+	case res.Remaining == 0:
+		ch <- stepSignal{Approved: true, Decision: rec}
+	default:
+		return res, nil
+	}
+	// the step stops waiting once signalled; removing it here keeps a
+	// further decision from queueing behind this one
+	delete(r.signals, stepID)
+	return res, nil
+}
+
+func containsFold(list []string, v string) bool {
+	for _, s := range list {
+		if strings.EqualFold(strings.TrimSpace(s), v) {
+			return true
+		}
+	}
+	return false
+}
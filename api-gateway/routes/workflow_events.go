@@ -0,0 +1,166 @@
+package routes
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Event sources an event trigger can listen to.
+const (
+	eventSourceAnalytics = "analytics" // POST /v1/analytics/events
+	eventSourceAudit     = "audit"     // POST /v1/audit/logs
+)
+
+// maxEventDedupKeys bounds the remembered idempotency keys; the oldest are
+// forgotten first.
+const maxEventDedupKeys = 10000
+
+// eventMatch selects the analytics events or audit logs that start a
+// workflow: the event type (analytics) or action (audit) must match, and
+// every filter path (dotted, e.g. properties.plan) must equal its value.
+type eventMatch struct {
+	Source    string         `json:"source"` // analytics|audit
+	EventType string         `json:"event_type,omitempty"`
+	Action    string         `json:"action,omitempty"`
+	Filters   map[string]any `json:"filters,omitempty"`
+}
+
+func (m *eventMatch) validate() error {
+	m.Source = strings.TrimSpace(m.Source)
+	if m.Source == "" {
+		m.Source = eventSourceAnalytics
+		if m.Action != "" {
+			m.Source = eventSourceAudit
+		}
+	}
+	switch m.Source {
+	case eventSourceAnalytics:
+		if strings.TrimSpace(m.EventType) == "" {
+			return errors.New("event_type is required for analytics events")
+		}
+	case eventSourceAudit:
+		if strings.TrimSpace(m.Action) == "" {
+			return errors.New("action is required for audit logs")
+		}
+	default:
+		return fmt.Errorf("source must be %s or %s", eventSourceAnalytics, eventSourceAudit)
+	}
+	for path := range m.Filters {
+		if path == "" || strings.Contains(path, "..") {
+			return fmt.Errorf("invalid filter path %q", path)
+		}
+	}
+	return nil
+}
+
+func (m *eventMatch) matches(source string, event map[string]any) bool {
+	if m.Source != source {
+		return false
+	}
+	if source == eventSourceAnalytics && event["event_type"] != m.EventType {
+		return false
+	}
+	if source == eventSourceAudit && event["action"] != m.Action {
+		return false
+	}
+	for path, want := range m.Filters {
+		got, err := lookupPath(event, path)
+		if err != nil || !reflect.DeepEqual(jsonShape(got), jsonShape(want)) {
+			return false
+		}
+	}
+	return true
+}
+
+// eventDedup remembers which execution each idempotency key started, per
+// trigger. Guarded by trigMu.
+var (
+	eventDedup      = map[string]string{} // trigger ID + "\x00" + key -> execution ID
+	eventDedupOrder []string
+)
+
+// dispatchEvent starts an execution of every workflow with an event trigger
+// matching the event, with the event as its parameters. A trigger that has
+// seen the event's idempotency key (its dedup_key field, else idemKey) does
+// not start another one. It returns what was started or deduplicated.
+func dispatchEvent(source string, event any, idemKey string) []gin.H {
+	ev, _ := jsonShape(event).(map[string]any)
+	if ev == nil {
+		return nil
+	}
+	type pending struct {
+		wf                Workflow
+		ex                *workflowExecution
+		auth, ns, subject string
+	}
+	var start []pending
+	results := []gin.H{}
+
+	trigMu.Lock()
+	matched := make([]*workflowTrigger, 0)
+	for _, t := range triggerStore {
+		if t.Event != nil && t.Event.matches(source, ev) {
+			matched = append(matched, t)
+		}
+	}
+	sort.Slice(matched, func(i, j int) bool { return matched[i].ID < matched[j].ID })
+	for _, t := range matched {
+		key := idemKey
+		if t.DedupKey != "" {
+			key = ""
+			if v, err := lookupPath(ev, t.DedupKey); err == nil && v != nil {
+				key = fmt.Sprint(v)
+			}
+		}
+		res := gin.H{"workflow_id": t.WorkflowID, "trigger_id": t.ID}
+		if key != "" {
+			res["idempotency_key"] = key
+			if execID, seen := eventDedup[t.ID+"\x00"+key]; seen {
+				res["execution_id"], res["duplicate"] = execID, true
+				results = append(results, res)
+				continue
+			}
+		}
+		wfMu.RLock()
+		wf, ok := wfStore[t.WorkflowID]
+		wf = runnableWorkflow(wf)
+		wfMu.RUnlock()
+		if !ok || wf.Status == "archived" {
+			continue
+		}
+		execCtx := map[string]any{"event_source": source}
+		if key != "" {
+			execCtx["idempotency_key"] = key
+		}
+		ex := t.newExecution(wf, ev, execCtx)
+		t.LastFiredAt = time.Now().UTC().Format(time.RFC3339)
+		if key != "" {
+			rememberEventKey(t.ID+"\x00"+key, ex.ExecutionID)
+		}
+		res["execution_id"], res["duplicate"] = ex.ExecutionID, false
+		results = append(results, res)
+		start = append(start, pending{wf: wf, ex: ex, auth: t.auth, ns: t.ns, subject: t.subject})
+	}
+	trigMu.Unlock()
+
+	for _, p := range start {
+		startExecution(p.wf, p.ex, p.auth, p.ns, p.subject)
+	}
+	return results
+}
+
+// rememberEventKey records a dedup key. Caller holds trigMu.
+func rememberEventKey(key, execID string) {
+	eventDedup[key] = execID
+	eventDedupOrder = append(eventDedupOrder, key)
+	if len(eventDedupOrder) > maxEventDedupKeys {
+		delete(eventDedup, eventDedupOrder[0])
+		eventDedupOrder = eventDedupOrder[1:]
+	}
+}
@@ -0,0 +1,216 @@
+package routes
+
+import (
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// cancelWait bounds how long CancelExecution waits for the run to stop.
+const cancelWait = 5 * time.Second
+
+// executionSummary is the list view of an execution.
+func executionSummary(ex *workflowExecution) gin.H {
+	return gin.H{
+		"execution_id":     ex.ExecutionID,
+		"workflow_id":      ex.WorkflowID,
+		"workflow_version": ex.WorkflowVersion,
+		"status":           ex.Status,
+		"progress":         ex.Progress,
+		"current_step":     ex.CurrentStep,
+		"triggered_by":     ex.TriggeredBy,
+		"started_at":       ex.StartedAt,
+		"completed_at":     ex.CompletedAt,
+		"error":            ex.Error,
+		"retry_of":         ex.RetryOf,
+	}
+}
+
+// workflowExists answers 404 and returns false when the workflow is unknown.
+func workflowExists(c *gin.Context, id string) (Workflow, bool) {
+	wfMu.RLock()
+	wf, ok := wfStore[id]
+	wfMu.RUnlock()
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": "workflow not found"})
+	}
+	return wf, ok
+}
+
+// lookupExecution returns a snapshot of the workflow's execution execId,
+// answering 404 when there is none.
+func lookupExecution(c *gin.Context) (workflowExecution, bool) {
+	id, execID := c.Param("workflowId"), c.Param("execId")
+	execMu.RLock()
+	cur, ok := execStore[execID]
+	var ex workflowExecution
+	if ok && cur.WorkflowID == id {
+		ex = cur.snapshot()
+	}
+	execMu.RUnlock()
+	if !ok || ex.WorkflowID != id {
+		c.JSON(http.StatusNotFound, gin.H{"error": "execution not found"})
+		return ex, false
+	}
+	return ex, true
+}
+
+// ListWorkflowExecutions lists a workflow's executions
+// @Summary List workflow executions
+// @Description Executions of the workflow, newest first
+// @Tags workflows
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Produce json
+// @Param workflowId path string true "Workflow ID"
+// @Param status query string false "Filter by status"
+// @Param page query int false "Page number"
+// @Param limit query int false "Items per page"
+// @Success 200 {object} map[string]interface{}
+// @Failure 404 {object} map[string]string
+// @Router /v1/workflows/{workflowId}/executions [get]
+func ListWorkflowExecutions(c *gin.Context) {
+	id := c.Param("workflowId")
+	if _, ok := workflowExists(c, id); !ok {
+		return
+	}
+	statusFilter := strings.TrimSpace(c.Query("status"))
+	execMu.RLock()
+	matched := make([]*workflowExecution, 0)
+	for _, ex := range execStore {
+		if ex.WorkflowID != id || (statusFilter != "" && !strings.EqualFold(ex.Status, statusFilter)) {
+			continue
+		}
+		matched = append(matched, ex)
+	}
+	sort.Slice(matched, func(i, j int) bool { return matched[i].seq > matched[j].seq })
+	items := make([]gin.H, 0, len(matched))
+	for _, ex := range matched {
+		items = append(items, executionSummary(ex))
+	}
+	execMu.RUnlock()
+
+	page, limit := 1, 10
+	if n, err := strconv.Atoi(c.Query("page")); err == nil && n > 0 {
+		page = n
+	}
+	if n, err := strconv.Atoi(c.Query("limit")); err == nil && n > 0 {
+		limit = n
+	}
+	total := len(items)
+	start := min((page-1)*limit, total)
+	end := min(start+limit, total)
+	c.JSON(http.StatusOK, gin.H{"executions": items[start:end], "total": total, "page": page, "limit": limit})
+}
+
+// GetWorkflowExecution returns one execution
+// @Summary Get workflow execution
+// @Description Full state of one execution: per-step state and outputs, decisions and history
+// @Tags workflows
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Produce json
+// @Param workflowId path string true "Workflow ID"
+// @Param execId path string true "Execution ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 404 {object} map[string]string
+// @Router /v1/workflows/{workflowId}/executions/{execId} [get]
+func GetWorkflowExecution(c *gin.Context) {
+	ex, ok := lookupExecution(c)
+	if !ok {
+		return
+	}
+	c.JSON(http.StatusOK, ex)
+}
+
+// CancelWorkflowExecution cancels a running execution
+// @Summary Cancel workflow execution
+// @Description Cancels a running execution: running and waiting steps are stopped and the execution ends as cancelled.
+// @Tags workflows
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Produce json
+// @Param workflowId path string true "Workflow ID"
+// @Param execId path string true "Execution ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /v1/workflows/{workflowId}/executions/{execId}/cancel [post]
+func CancelWorkflowExecution(c *gin.Context) {
+	ex, ok := lookupExecution(c)
+	if !ok {
+		return
+	}
+	execMu.Lock()
+	run := runs[ex.ExecutionID]
+	if run != nil {
+		run.cancel()
+	}
+	execMu.Unlock()
+	if run == nil {
+		c.JSON(http.StatusConflict, gin.H{"error": "execution is not running", "status": ex.Status})
+		return
+	}
+	select {
+	case <-run.done:
+	case <-time.After(cancelWait):
+	}
+	if ex, ok = lookupExecution(c); ok {
+		c.JSON(http.StatusOK, executionSummary(&ex))
+	}
+}
+
+// RetryWorkflowExecution re-runs a failed execution
+// @Summary Retry workflow execution
+// @Description Starts a new execution with the workflow version, parameters and context of a failed, timed out or cancelled one. The new execution records retry_of.
+// @Tags workflows
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param workflowId path string true "Workflow ID"
+// @Param execId path string true "Execution ID"
+// @Success 202 {object} map[string]interface{}
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /v1/workflows/{workflowId}/executions/{execId}/retry [post]
+func RetryWorkflowExecution(c *gin.Context) {
+	prev, ok := lookupExecution(c)
+	if !ok {
+		return
+	}
+	if prev.Status != execFailed && prev.Status != execTimeout && prev.Status != execCancelled {
+		c.JSON(http.StatusConflict, gin.H{"error": "only failed, timed out or cancelled executions can be retried", "status": prev.Status})
+		return
+	}
+	if _, ok := workflowExists(c, prev.WorkflowID); !ok {
+		return
+	}
+	wfMu.RLock()
+	cur := wfStore[prev.WorkflowID]
+	wf, ok := workflowAtVersion(cur, prev.WorkflowVersion)
+	wfMu.RUnlock()
+	if cur.Status == "archived" {
+		c.JSON(http.StatusConflict, gin.H{"error": "workflow is archived", "status": cur.Status})
+		return
+	}
+	if !ok {
+		c.JSON(http.StatusConflict, gin.H{"error": "workflow version no longer exists"})
+		return
+	}
+	var payload struct {
+		TriggeredBy string `json:"triggered_by"`
+	}
+	_ = c.ShouldBindJSON(&payload)
+	triggeredBy := payload.TriggeredBy
+	if triggeredBy == "" {
+		triggeredBy = prev.TriggeredBy
+	}
+	ex := newExecution(wf, triggeredBy, prev.Parameters, prev.Context)
+	ex.RetryOf = prev.ExecutionID
+	launchExecution(c, wf, ex)
+}
@@ -0,0 +1,233 @@
+package routes
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// stepStatusColors are the node fill colors per step status; nodes without
+// an execution use the pending color.
+var stepStatusColors = map[string]string{
+	"pending":   "#e5e7eb",
+	"running":   "#93c5fd",
+	"waiting":   "#fde68a",
+	"retrying":  "#fdba74",
+	"completed": "#86efac",
+	"failed":    "#fca5a5",
+	"rejected":  "#f9a8d4",
+	"timeout":   "#c4b5fd",
+	"skipped":   "#f3f4f6",
+	"cancelled": "#d1d5db",
+}
+
+// graphNode is one step in the rendered graph. Level is the length of the
+// longest depends_on chain leading to the step.
+type graphNode struct {
+	ID     string `json:"id"`
+	Name   string `json:"name"`
+	Type   string `json:"type"`
+	Level  int    `json:"level"`
+	Status string `json:"status,omitempty"`
+	Color  string `json:"color"`
+}
+
+// graphEdge points from a step to one it starts: kind is depends_on, or
+// on_reject/on_failure for branch steps.
+type graphEdge struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+	Kind string `json:"kind"`
+}
+
+type workflowGraph struct {
+	WorkflowID      string      `json:"workflow_id"`
+	WorkflowVersion int         `json:"workflow_version"`
+	ExecutionID     string      `json:"execution_id,omitempty"`
+	Nodes           []graphNode `json:"nodes"`
+	Edges           []graphEdge `json:"edges"`
+}
+
+// buildGraph lays out the steps of wf; statuses (by step ID) colors them.
+func buildGraph(wf Workflow, statuses map[string]string) workflowGraph {
+	deps := dependencyGraph(wf.Steps)
+	levels := map[string]int{}
+	var level func(id string, seen map[string]bool) int
+	level = func(id string, seen map[string]bool) int {
+		if l, ok := levels[id]; ok {
+			return l
+		}
+		if seen[id] { // stored workflows are acyclic; don't loop if not
+			return 0
+		}
+		seen[id] = true
+		l := 0
+		for _, d := range deps[id] {
+			l = max(l, level(d, seen)+1)
+		}
+		levels[id] = l
+		return l
+	}
+
+	g := workflowGraph{WorkflowID: wf.ID, WorkflowVersion: wf.Version, Nodes: []graphNode{}, Edges: []graphEdge{}}
+	for _, s := range wf.Steps {
+		n := graphNode{ID: s.ID, Name: s.Name, Type: s.Type, Level: level(s.ID, map[string]bool{}), Status: statuses[s.ID]}
+		n.Color = stepStatusColors["pending"]
+		if c, ok := stepStatusColors[n.Status]; ok {
+			n.Color = c
+		}
+		g.Nodes = append(g.Nodes, n)
+		for _, d := range s.DependsOn {
+			g.Edges = append(g.Edges, graphEdge{From: d, To: s.ID, Kind: "depends_on"})
+		}
+		if s.OnReject != "" {
+			g.Edges = append(g.Edges, graphEdge{From: s.ID, To: s.OnReject, Kind: "on_reject"})
+		}
+		if comp := s.compensatingStep(); comp != "" {
+			g.Edges = append(g.Edges, graphEdge{From: s.ID, To: comp, Kind: "on_failure"})
+		}
+	}
+	return g
+}
+
+// dot renders the graph in Graphviz DOT.
+func (g workflowGraph) dot() string {
+	quote := func(s string) string {
+		return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(s) + `"`
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "digraph %s {\n", quote(g.WorkflowID))
+	b.WriteString("  rankdir=LR;\n")
+	b.WriteString("  node [shape=box, style=\"rounded,filled\", fontname=\"Helvetica\"];\n")
+	for _, n := range g.Nodes {
+		label := quote(n.Name + "\n" + n.Type)
+		fmt.Fprintf(&b, "  %s [label=%s, fillcolor=%s", quote(n.ID), label, quote(n.Color))
+		if n.Status != "" {
+			fmt.Fprintf(&b, ", tooltip=%s", quote(n.Status))
+		}
+		b.WriteString("];\n")
+	}
+	for _, e := range g.Edges {
+		fmt.Fprintf(&b, "  %s -> %s", quote(e.From), quote(e.To))
+		if e.Kind != "depends_on" {
+			fmt.Fprintf(&b, " [style=dashed, label=%s]", quote(e.Kind))
+		}
+		b.WriteString(";\n")
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
+
+// mermaid renders the graph as a Mermaid flowchart. Steps get positional
+// node IDs (n0, n1, ...) since step IDs may contain characters Mermaid does
+// not accept; statuses become classes.
+func (g workflowGraph) mermaid() string {
+	label := strings.NewReplacer(`"`, "#quot;", "<", "#lt;", ">", "#gt;", "\n", " ")
+	ids := make(map[string]string, len(g.Nodes))
+	var b strings.Builder
+	b.WriteString("flowchart LR\n")
+	for i, n := range g.Nodes {
+		ids[n.ID] = fmt.Sprintf("n%d", i)
+		fmt.Fprintf(&b, "  %s[\"%s<br/>%s\"]\n", ids[n.ID], label.Replace(n.Name), label.Replace(n.Type))
+	}
+	for _, e := range g.Edges {
+		if e.Kind == "depends_on" {
+			fmt.Fprintf(&b, "  %s --> %s\n", ids[e.From], ids[e.To])
+		} else {
+			fmt.Fprintf(&b, "  %s -. %s .-> %s\n", ids[e.From], e.Kind, ids[e.To])
+		}
+	}
+	byStatus := map[string][]string{}
+	for _, n := range g.Nodes {
+		if n.Status != "" {
+			byStatus[n.Status] = append(byStatus[n.Status], ids[n.ID])
+		}
+	}
+	statuses := make([]string, 0, len(byStatus))
+	for s := range byStatus {
+		statuses = append(statuses, s)
+	}
+	sort.Strings(statuses)
+	for _, s := range statuses {
+		color, ok := stepStatusColors[s]
+		if !ok {
+			color = stepStatusColors["pending"]
+		}
+		fmt.Fprintf(&b, "  classDef %s fill:%s\n", s, color)
+		fmt.Fprintf(&b, "  class %s %s\n", strings.Join(byStatus[s], ","), s)
+	}
+	return b.String()
+}
+
+// GetWorkflowGraph renders the step graph
+// @Summary Workflow graph
+// @Description The workflow's steps and their depends_on edges (on_reject/on_failure branches dashed) as JSON, Graphviz DOT or a Mermaid flowchart. With execution_id (or "latest") the graph shows the version that execution runs, with nodes colored by step status.
+// @Tags workflows
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Produce json
+// @Produce text/vnd.graphviz
+// @Produce text/plain
+// @Param workflowId path string true "Workflow ID"
+// @Param format query string false "json (default), dot or mermaid"
+// @Param execution_id query string false "Execution ID, or latest"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /v1/workflows/{workflowId}/graph [get]
+func GetWorkflowGraph(c *gin.Context) {
+	id := c.Param("workflowId")
+	format := strings.ToLower(c.DefaultQuery("format", "json"))
+	if format != "json" && format != "dot" && format != "mermaid" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "format must be json, dot or mermaid"})
+		return
+	}
+	wf, ok := workflowExists(c, id)
+	if !ok {
+		return
+	}
+
+	var statuses map[string]string
+	execID := strings.TrimSpace(c.Query("execution_id"))
+	if execID != "" {
+		execMu.RLock()
+		if execID == "latest" {
+			execID = latestExec[id]
+		}
+		ex, found := execStore[execID]
+		var version int
+		if found && ex.WorkflowID == id {
+			version = ex.WorkflowVersion
+			statuses = make(map[string]string, len(ex.Steps))
+			for stepID, st := range ex.Steps {
+				statuses[stepID] = st.Status
+			}
+		}
+		execMu.RUnlock()
+		if statuses == nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "execution not found"})
+			return
+		}
+		wfMu.RLock()
+		wf, ok = workflowAtVersion(wf, version)
+		wfMu.RUnlock()
+		if !ok {
+			c.JSON(http.StatusNotFound, gin.H{"error": "workflow version no longer exists"})
+			return
+		}
+	}
+
+	g := buildGraph(wf, statuses)
+	g.ExecutionID = execID
+	switch format {
+	case "dot":
+		c.Data(http.StatusOK, "text/vnd.graphviz; charset=utf-8", []byte(g.dot()))
+	case "mermaid":
+		c.Data(http.StatusOK, "text/plain; charset=utf-8", []byte(g.mermaid()))
+	default:
+		c.JSON(http.StatusOK, g)
+	}
+}
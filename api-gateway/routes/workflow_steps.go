@@ -0,0 +1,220 @@
+package routes
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/weltschmerz/QA-Playground/api-gateway/adapters"
+)
+
+// stepHandler runs one step and returns its output. ctx ends when the step
+// times out or the execution is cancelled.
+type stepHandler func(ctx context.Context, sr *stepRun) (map[string]any, error)
+
+// stepHandlers maps step types to their implementation. Steps of any other
+// type fail at run time.
+var stepHandlers = map[string]stepHandler{}
+
+func registerStepHandler(stepType string, h stepHandler) {
+	stepHandlers[stepType] = h
+}
+
+func init() {
+	registerStepHandler("ai_completion", runCompletionStep)
+	registerStepHandler("delay", runDelayStep)
+	registerStepHandler("http_call", runHTTPStep)
+	registerStepHandler("notify", runNotifyStep)
+	registerStepHandler("approval", decisionStep("approval", "approved_by"))
+	for _, t := range []string{"manual", "task", "automated"} {
+		registerStepHandler(t, decisionStep("external", "completed_by"))
+	}
+}
+
+// maxStepResponseBody bounds the http_call response body kept as output.
+const maxStepResponseBody = 64 << 10
+
+var stepHTTPClient = &http.Client{Timeout: 30 * time.Second}
+
+func configString(cfg map[string]any, key string) string {
+	s, _ := cfg[key].(string)
+	return strings.TrimSpace(s)
+}
+
+// runCompletionStep sends config.prompt to config.model (or the step's
+// adapter, default adapter-a) with the fallback chain of the model, charging
+// the tokens to the caller that started the execution.
+func runCompletionStep(ctx context.Context, sr *stepRun) (map[string]any, error) {
+	cfg := sr.step.Config
+	prompt := configString(cfg, "prompt")
+	if prompt == "" {
+		return nil, errors.New("config.prompt is required")
+	}
+	model := configString(cfg, "model")
+	if model == "" {
+		model = sr.step.Adapter
+	}
+	if model == "" {
+		model = "adapter-a"
+	}
+	if qe := checkQuota(sr.run.subject); qe != nil {
+		return nil, fmt.Errorf("token quota exceeded (%s limit %d)", qe.Period, qe.Limit)
+	}
+	req := adapters.CompletionRequest{Prompt: prompt, Model: model, SystemPrompt: configString(cfg, "system_prompt")}
+	if n, ok := toInt(cfg["max_tokens"]); ok {
+		req.MaxTokens = n
+	}
+	if t, ok := cfg["temperature"].(float64); ok {
+		req.Temperature = &t
+	}
+	res, servedBy, _, err := completeWithFallback(ctx, sr.run.auth, "", req, fallbackChain(model, nil))
+	if err != nil {
+		return nil, err
+	}
+	if res.Usage.TotalTokens == 0 {
+		res.Usage = estimateUsage(prompt, res.Text)
+	}
+	recordUsage(sr.run.subject, servedBy, "/v1/workflows/:workflowId/execute", res.Usage)
+	return map[string]any{"text": res.Text, "model": servedBy, "usage": res.Usage}, nil
+}
+
+// runDelayStep waits config.duration_ms milliseconds or config.seconds seconds.
+func runDelayStep(ctx context.Context, sr *stepRun) (map[string]any, error) {
+	d := time.Duration(0)
+	if ms, ok := toInt(sr.step.Config["duration_ms"]); ok {
+		d = time.Duration(ms) * time.Millisecond
+	} else if s, ok := toInt(sr.step.Config["seconds"]); ok {
+		d = time.Duration(s) * time.Second
+	}
+	if d < 0 {
+		return nil, errors.New("delay must not be negative")
+	}
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return map[string]any{"delayed_ms": d.Milliseconds()}, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// runHTTPStep calls config.url with config.method (default GET), headers and
+// body. Responses with status >= 400 fail the step.
+func runHTTPStep(ctx context.Context, sr *stepRun) (map[string]any, error) {
+	cfg := sr.step.Config
+	target := configString(cfg, "url")
+	if !validCallbackURL(target) {
+		return nil, errors.New("config.url must be an http(s) URL")
+	}
+	method := strings.ToUpper(configString(cfg, "method"))
+	if method == "" {
+		method = http.MethodGet
+	}
+	var body io.Reader
+	if b, ok := cfg["body"]; ok && b != nil {
+		if s, ok := b.(string); ok {
+			body = strings.NewReader(s)
+		} else {
+			raw, err := json.Marshal(b)
+			if err != nil {
+				return nil, fmt.Errorf("config.body: %w", err)
+			}
+			body = bytes.NewReader(raw)
+		}
+	}
+	req, err := http.NewRequestWithContext(ctx, method, target, body)
+	if err != nil {
+		return nil, err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if headers, ok := cfg["headers"].(map[string]any); ok {
+		for k, v := range headers {
+			req.Header.Set(k, fmt.Sprint(v))
+		}
+	}
+	resp, err := stepHTTPClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 400 {
+		return nil, fmt.Errorf("%s %s returned %d", method, target, resp.StatusCode)
+	}
+	raw, _ := io.ReadAll(io.LimitReader(resp.Body, maxStepResponseBody))
+	var parsed any = string(raw)
+	var v any
+	if json.Unmarshal(raw, &v) == nil {
+		parsed = v
+	}
+	return map[string]any{"status_code": resp.StatusCode, "body": parsed}, nil
+}
+
+// runNotifyStep creates a notification for config.recipient in the
+// notification tenant of the caller that started the execution.
+func runNotifyStep(_ context.Context, sr *stepRun) (map[string]any, error) {
+	cfg := sr.step.Config
+	recipient := configString(cfg, "recipient")
+	if !isValidEmail(recipient) {
+		return nil, errors.New("config.recipient must be an email address")
+	}
+	title := configString(cfg, "title")
+	if title == "" {
+		title = sr.step.Name
+	}
+	message := configString(cfg, "message")
+	if message == "" {
+		message = fmt.Sprintf("Workflow %s reached step %s", sr.run.wf.Name, sr.step.ID)
+	}
+	now := time.Now().UTC().Format(time.RFC3339)
+	_, recipientID, _ := resolveRecipient(recipient)
+	n := Notification{
+		ID:          newNotificationID(),
+		Title:       title,
+		Message:     message,
+		Type:        defaultOrAllowed(configString(cfg, "type"), []string{"info", "warning", "alert", "error"}, "info"),
+		Recipient:   recipient,
+		RecipientID: recipientID,
+		Sender:      "workflow:" + sr.run.wf.ID,
+		Priority:    defaultOrAllowed(configString(cfg, "priority"), []string{"low", "medium", "normal", "high", "critical"}, "normal"),
+		Status:      "unread",
+		CreatedAt:   now,
+		UpdatedAt:   now,
+		Metadata:    map[string]any{"workflow_id": sr.run.wf.ID, "execution_id": sr.run.ex.ExecutionID, "step_id": sr.step.ID},
+	}
+	notifMu.Lock()
+	tenantStore(sr.run.ns).put("created", n)
+	notifMu.Unlock()
+	return map[string]any{"notification_id": n.ID, "recipient": recipient}, nil
+}
+
+// decisionStep returns a handler that parks the step until it is approved or
+// rejected. kind "approval" puts the execution in waiting_approval; "external"
+// steps are human or external work signed off the same way.
+func decisionStep(kind, byKey string) stepHandler {
+	return func(ctx context.Context, sr *stepRun) (map[string]any, error) {
+		sig, err := sr.wait(ctx, kind)
+		if err != nil {
+			return nil, err
+		}
+		if !sig.Approved {
+			if sig.Decision.Reason != "" {
+				return nil, fmt.Errorf("%w by %s: %s", errStepRejected, sig.Decision.Approver, sig.Decision.Reason)
+			}
+			return nil, fmt.Errorf("%w by %s", errStepRejected, sig.Decision.Approver)
+		}
+		approvers := []string{}
+		for _, a := range sr.decisions() {
+			approvers = append(approvers, a.Approver)
+		}
+		return map[string]any{byKey: sig.Decision.Approver, "approvers": approvers, "comments": sig.Decision.Comments}, nil
+	}
+}
@@ -0,0 +1,225 @@
+package routes
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Step config values may contain {{path}} expressions, resolved when the step
+// starts. Paths start at one of:
+//
+//	parameters.<key>...           execution parameters
+//	context.<key>...              execution context
+//	steps.<id>.output.<key>...    output of an upstream step
+//	steps.<id>.status
+//	workflow.id|name|version
+//	execution.id|triggered_by
+//
+// A value that is exactly one expression keeps the referenced value's type;
+// otherwise expressions are interpolated into the string.
+var templateExpr = regexp.MustCompile(`\{\{\s*([^{}]*?)\s*\}\}`)
+
+var templateRoots = map[string]bool{"parameters": true, "context": true, "steps": true, "workflow": true, "execution": true}
+
+// templateRefs returns the expressions used anywhere in v.
+func templateRefs(v any) ([]string, error) {
+	var refs []string
+	var walk func(v any) error
+	walk = func(v any) error {
+		switch t := v.(type) {
+		case string:
+			for _, m := range templateExpr.FindAllStringSubmatch(t, -1) {
+				refs = append(refs, m[1])
+			}
+			if strings.Contains(templateExpr.ReplaceAllString(t, ""), "{{") {
+				return fmt.Errorf("unterminated template in %q", t)
+			}
+		case map[string]any:
+			for _, e := range t {
+				if err := walk(e); err != nil {
+					return err
+				}
+			}
+		case []any:
+			for _, e := range t {
+				if err := walk(e); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	}
+	err := walk(v)
+	return refs, err
+}
+
+// validateTemplates checks the references in a step's config: known roots,
+// and step references only to steps the step (transitively) depends on, so
+// their output exists when it starts.
+func validateTemplates(s WorkflowStep, byID map[string]WorkflowStep) error {
+	refs, err := templateRefs(map[string]any(s.Config))
+	if err != nil {
+		return fmt.Errorf("step %s: %w", s.ID, err)
+	}
+	for _, ref := range refs {
+		parts := strings.Split(ref, ".")
+		if !templateRoots[parts[0]] || len(parts) < 2 || slices.Contains(parts, "") {
+			return fmt.Errorf("step %s: unknown template reference {{%s}}", s.ID, ref)
+		}
+		if parts[0] != "steps" {
+			continue
+		}
+		dep := parts[1]
+		if _, ok := byID[dep]; !ok {
+			return fmt.Errorf("step %s: template references unknown step %s", s.ID, dep)
+		}
+		if !dependsOn(s, dep, byID) {
+			return fmt.Errorf("step %s: template references step %s which it does not depend on", s.ID, dep)
+		}
+		if len(parts) < 3 || (parts[2] != "output" && parts[2] != "status") {
+			return fmt.Errorf("step %s: unknown template reference {{%s}}", s.ID, ref)
+		}
+	}
+	return nil
+}
+
+// dependsOn reports whether s depends on step id directly or transitively.
+func dependsOn(s WorkflowStep, id string, byID map[string]WorkflowStep) bool {
+	seen := map[string]bool{}
+	stack := append([]string(nil), s.DependsOn...)
+	for len(stack) > 0 {
+		cur := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if cur == id {
+			return true
+		}
+		if seen[cur] {
+			continue
+		}
+		seen[cur] = true
+		stack = append(stack, byID[cur].DependsOn...)
+	}
+	return false
+}
+
+// templateScope is the data step templates resolve against. Caller holds
+// execMu.
+func (r *workflowRun) templateScope() map[string]any {
+	steps := map[string]any{}
+	for id, st := range r.ex.Steps {
+		steps[id] = map[string]any{"output": st.Output, "status": st.Status}
+	}
+	return map[string]any{
+		"parameters": r.ex.Parameters,
+		"context":    r.ex.Context,
+		"steps":      steps,
+		"workflow":   map[string]any{"id": r.wf.ID, "name": r.wf.Name, "version": r.wf.Version},
+		"execution":  map[string]any{"id": r.ex.ExecutionID, "triggered_by": r.ex.TriggeredBy},
+	}
+}
+
+// resolveTemplates returns a copy of cfg with every expression replaced.
+func resolveTemplates(cfg map[string]any, scope map[string]any) (map[string]any, error) {
+	if cfg == nil {
+		return nil, nil
+	}
+	out, err := resolveValue(cfg, scope)
+	if err != nil {
+		return nil, err
+	}
+	return out.(map[string]any), nil
+}
+
+func resolveValue(v any, scope map[string]any) (any, error) {
+	switch t := v.(type) {
+	case string:
+		return resolveString(t, scope)
+	case map[string]any:
+		out := make(map[string]any, len(t))
+		for k, e := range t {
+			r, err := resolveValue(e, scope)
+			if err != nil {
+				return nil, err
+			}
+			out[k] = r
+		}
+		return out, nil
+	case []any:
+		out := make([]any, len(t))
+		for i, e := range t {
+			r, err := resolveValue(e, scope)
+			if err != nil {
+				return nil, err
+			}
+			out[i] = r
+		}
+		return out, nil
+	}
+	return v, nil
+}
+
+func resolveString(s string, scope map[string]any) (any, error) {
+	if m := templateExpr.FindStringSubmatch(s); m != nil && m[0] == s {
+		return lookupPath(scope, m[1])
+	}
+	var firstErr error
+	out := templateExpr.ReplaceAllStringFunc(s, func(expr string) string {
+		v, err := lookupPath(scope, templateExpr.FindStringSubmatch(expr)[1])
+		if err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
+			return ""
+		}
+		if str, ok := v.(string); ok {
+			return str
+		}
+		b, _ := json.Marshal(v)
+		return string(b)
+	})
+	return out, firstErr
+}
+
+// lookupPath walks a dotted path through maps and lists.
+func lookupPath(scope map[string]any, path string) (any, error) {
+	var cur any = scope
+	for _, part := range strings.Split(path, ".") {
+		switch t := jsonShape(cur).(type) {
+		case map[string]any:
+			v, ok := t[part]
+			if !ok {
+				return nil, fmt.Errorf("template {{%s}}: %s is not set", path, part)
+			}
+			cur = v
+		case []any:
+			i, err := strconv.Atoi(part)
+			if err != nil || i < 0 || i >= len(t) {
+				return nil, fmt.Errorf("template {{%s}}: no element %s", path, part)
+			}
+			cur = t[i]
+		default:
+			return nil, fmt.Errorf("template {{%s}}: %s is not set", path, part)
+		}
+	}
+	return cur, nil
+}
+
+// jsonShape returns v as decoded JSON so values that are not plain maps or
+// lists (e.g. adapter usage structs) can be walked too.
+func jsonShape(v any) any {
+	switch v.(type) {
+	case map[string]any, []any, nil:
+		return v
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil
+	}
+	var out any
+	_ = json.Unmarshal(b, &out)
+	return out
+}
@@ -0,0 +1,449 @@
+package routes
+
+import (
+	"maps"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	gwmiddleware "github.com/weltschmerz/QA-Playground/api-gateway/middleware"
+	"github.com/weltschmerz/QA-Playground/api-gateway/utils"
+)
+
+// Missed-run policies: what the scheduler does with fire times it finds more
+// than misfireThreshold in the past (the scheduler was paused, or the clock
+// moved forward). Triggers live in memory only, so a restart drops them
+// rather than replaying missed runs. On-time fire times always run.
+const (
+	missedSkip    = "skip"     // drop them
+	missedRunOnce = "run_once" // one run for all of them (default)
+	missedRunAll  = "run_all"  // one run each, up to maxCatchUpRuns
+)
+
+const (
+	triggerTick      = time.Second
+	misfireThreshold = time.Minute
+	maxCatchUpRuns   = 100
+)
+
+// workflowTrigger starts executions of a workflow on a cron schedule, once
+// at run_at, or for every matching analytics event or audit log.
+type workflowTrigger struct {
+	ID              string         `json:"id"`
+	WorkflowID      string         `json:"workflow_id"`
+	Type            string         `json:"type"` // cron|run_at|event
+	Cron            string         `json:"cron,omitempty"`
+	RunAt           string         `json:"run_at,omitempty"`
+	Event           *eventMatch    `json:"event,omitempty"`
+	DedupKey        string         `json:"dedup_key,omitempty"` // event path used as idempotency key
+	MissedRunPolicy string         `json:"missed_run_policy,omitempty"`
+	Parameters      map[string]any `json:"parameters,omitempty"`
+	Context         map[string]any `json:"context,omitempty"`
+	Status          string         `json:"status"` // active|completed
+	NextFireAt      string         `json:"next_fire_at,omitempty"`
+	LastFiredAt     string         `json:"last_fired_at,omitempty"`
+	LastExecutionID string         `json:"last_execution_id,omitempty"`
+	FireCount       int            `json:"fire_count"`
+	MissedCount     int            `json:"missed_count"`
+	CreatedAt       string         `json:"created_at"`
+
+	schedule *cronSchedule
+	next     time.Time // zero once a run_at trigger has fired
+
+	// identity of the creator, used for the executions it starts
+	auth, ns, subject string
+}
+
+var (
+	triggerStore  = map[string]*workflowTrigger{} // by trigger ID
+	trigMu        sync.Mutex                      // taken before wfMu when both are needed
+	schedulerOnce sync.Once
+)
+
+// startTriggerScheduler launches the scheduler loop once.
+func startTriggerScheduler() {
+	schedulerOnce.Do(func() {
+		moved := schedClock.subscribe()
+		go func() {
+			tick := time.NewTicker(triggerTick)
+			defer tick.Stop()
+			last := schedClock.Now()
+			for {
+				select {
+				case <-tick.C:
+				case <-moved:
+				}
+				now := schedClock.Now()
+				fireDueTriggers(now, now.Before(last))
+				last = now
+			}
+		}()
+	})
+}
+
+// triggerFire is one execution a trigger is about to start.
+type triggerFire struct {
+	trigger      *workflowTrigger
+	scheduledFor time.Time
+}
+
+// fireDueTriggers starts the executions that are due at now. When the clock
+// went backwards, cron triggers are rescheduled from now.
+func fireDueTriggers(now time.Time, rewound bool) {
+	var fires []triggerFire
+	changed := map[string]bool{}
+	trigMu.Lock()
+	for _, t := range triggerStore {
+		if t.next.IsZero() {
+			continue
+		}
+		if rewound && t.schedule != nil {
+			if next, ok := t.schedule.next(now); ok && next.Before(t.next) {
+				t.next = next
+				t.NextFireAt = next.Format(time.RFC3339)
+				changed[t.WorkflowID] = true
+			}
+		}
+		if t.next.After(now) {
+			continue
+		}
+		var due []time.Time
+		for at, ok := t.next, true; ok && !at.After(now) && len(due) < maxCatchUpRuns; {
+			due = append(due, at)
+			if t.schedule == nil {
+				break
+			}
+			at, ok = t.schedule.next(at)
+		}
+		var missed []time.Time
+		for len(due) > 0 && now.Sub(due[0]) > misfireThreshold {
+			missed, due = append(missed, due[0]), due[1:]
+		}
+		switch t.MissedRunPolicy {
+		case missedRunAll:
+			due = append(missed, due...)
+		case missedSkip:
+			t.MissedCount += len(missed)
+		default:
+			if len(missed) > 0 {
+				t.MissedCount += len(missed) - 1
+				due = append([]time.Time{missed[len(missed)-1]}, due...)
+			}
+		}
+		for _, at := range due {
+			fires = append(fires, triggerFire{trigger: t, scheduledFor: at})
+		}
+		t.next = time.Time{}
+		if t.schedule != nil {
+			t.next, _ = t.schedule.next(now)
+		}
+		t.NextFireAt = ""
+		if !t.next.IsZero() {
+			t.NextFireAt = t.next.Format(time.RFC3339)
+		} else {
+			t.Status = "completed"
+		}
+		changed[t.WorkflowID] = true
+	}
+	trigMu.Unlock()
+
+	for _, f := range fires {
+		fireTrigger(f)
+	}
+	for id := range changed {
+		publishNextFires(id)
+	}
+}
+
+// fireTrigger starts one execution of the trigger's workflow; a trigger
+// whose workflow is gone is dropped, and one of an archived workflow counts
+// the fire as missed.
+func fireTrigger(f triggerFire) {
+	t := f.trigger
+	wfMu.RLock()
+	wf, ok := wfStore[t.WorkflowID]
+	wf = runnableWorkflow(wf)
+	wfMu.RUnlock()
+	if !ok {
+		trigMu.Lock()
+		delete(triggerStore, t.ID)
+		trigMu.Unlock()
+		return
+	}
+	trigMu.Lock()
+	if wf.Status == "archived" {
+		t.MissedCount++
+		trigMu.Unlock()
+		return
+	}
+	scheduledFor := f.scheduledFor.Format(time.RFC3339)
+	ex := t.newExecution(wf, nil, map[string]any{"scheduled_for": scheduledFor})
+	t.LastFiredAt = scheduledFor
+	auth, ns, subject := t.auth, t.ns, t.subject
+	trigMu.Unlock()
+	startExecution(wf, ex, auth, ns, subject)
+}
+
+// newExecution prepares an execution started by t with the trigger's
+// parameters overlaid by params, and counts the fire. Caller holds trigMu.
+func (t *workflowTrigger) newExecution(wf Workflow, params, execCtx map[string]any) *workflowExecution {
+	merged := maps.Clone(t.Parameters)
+	if merged == nil && params != nil {
+		merged = map[string]any{}
+	}
+	maps.Copy(merged, params)
+	ctx := maps.Clone(t.Context)
+	if ctx == nil {
+		ctx = map[string]any{}
+	}
+	maps.Copy(ctx, execCtx)
+	ctx["trigger_id"] = t.ID
+	ex := newExecution(wf, "trigger:"+t.ID, merged, ctx)
+	t.FireCount++
+	t.LastExecutionID = ex.ExecutionID
+	return ex
+}
+
+// publishNextFires copies the workflow's upcoming fire times onto it, so they
+// show in the workflow's JSON.
+func publishNextFires(workflowID string) {
+	trigMu.Lock()
+	defer trigMu.Unlock()
+	var next []string
+	for _, t := range triggerStore {
+		if t.WorkflowID == workflowID && t.NextFireAt != "" {
+			next = append(next, t.NextFireAt)
+		}
+	}
+	sort.Strings(next)
+	wfMu.Lock()
+	if wf, ok := wfStore[workflowID]; ok {
+		wf.NextFireTimes = next
+		wfStore[workflowID] = wf
+	}
+	wfMu.Unlock()
+}
+
+func workflowTriggers(workflowID string) []workflowTrigger {
+	trigMu.Lock()
+	defer trigMu.Unlock()
+	items := make([]workflowTrigger, 0)
+	for _, t := range triggerStore {
+		if t.WorkflowID == workflowID {
+			items = append(items, *t)
+		}
+	}
+	sort.Slice(items, func(i, j int) bool { return items[i].CreatedAt+items[i].ID < items[j].CreatedAt+items[j].ID })
+	return items
+}
+
+// ListWorkflowTriggers lists a workflow's triggers
+// @Summary List workflow triggers
+// @Description Cron, run_at and event triggers of the workflow with their next fire time and fire counts
+// @Tags workflows
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Produce json
+// @Param workflowId path string true "Workflow ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 404 {object} map[string]string
+// @Router /v1/workflows/{workflowId}/triggers [get]
+func ListWorkflowTriggers(c *gin.Context) {
+	id := c.Param("workflowId")
+	if _, ok := workflowExists(c, id); !ok {
+		return
+	}
+	items := workflowTriggers(id)
+	c.JSON(http.StatusOK, gin.H{"triggers": items, "total": len(items)})
+}
+
+// CreateWorkflowTrigger schedules a workflow
+// @Summary Create workflow trigger
+// @Description Starts executions on a five-field UTC `cron` expression (or @hourly, @daily, ...), once at `run_at` (RFC3339), or for each analytics event or audit log matching `event`, with optional parameters and context. `missed_run_policy` (skip|run_once|run_all, default run_once) decides what happens to scheduled fire times that were missed by more than a minute while the scheduler was paused or the clock moved forward (triggers are kept in memory and do not survive a restart); `dedup_key` names the event field whose value dedups event runs (the Idempotency-Key header is used otherwise).
+// @Tags workflows
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param workflowId path string true "Workflow ID"
+// @Success 201 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /v1/workflows/{workflowId}/triggers [post]
+func CreateWorkflowTrigger(c *gin.Context) {
+	id := c.Param("workflowId")
+	if _, ok := workflowExists(c, id); !ok {
+		return
+	}
+	var payload struct {
+		Type            string         `json:"type"`
+		Cron            string         `json:"cron"`
+		RunAt           string         `json:"run_at"`
+		Event           *eventMatch    `json:"event"`
+		DedupKey        string         `json:"dedup_key"`
+		MissedRunPolicy string         `json:"missed_run_policy"`
+		Parameters      map[string]any `json:"parameters"`
+		Context         map[string]any `json:"context"`
+	}
+	if err := c.BindJSON(&payload); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid payload"})
+		return
+	}
+	now := schedClock.Now()
+	t := &workflowTrigger{
+		ID:              "trg-" + utils.GenID()[:12],
+		WorkflowID:      id,
+		MissedRunPolicy: strings.TrimSpace(payload.MissedRunPolicy),
+		Parameters:      payload.Parameters,
+		Context:         payload.Context,
+		Status:          "active",
+		CreatedAt:       now.Format(time.RFC3339),
+		auth:            c.GetHeader("Authorization"),
+		ns:              notifTenant(c),
+		subject:         gwmiddleware.CallerSubject(c),
+	}
+	cronSet, runAtSet, eventSet := strings.TrimSpace(payload.Cron) != "", strings.TrimSpace(payload.RunAt) != "", payload.Event != nil
+	set := 0
+	for _, b := range []bool{cronSet, runAtSet, eventSet} {
+		if b {
+			set++
+		}
+	}
+	if set != 1 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "exactly one of cron, run_at and event is required"})
+		return
+	}
+	switch t.MissedRunPolicy {
+	case "":
+		if !eventSet {
+			t.MissedRunPolicy = missedRunOnce
+		}
+	case missedSkip, missedRunOnce, missedRunAll:
+		if eventSet {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "missed_run_policy does not apply to event triggers"})
+			return
+		}
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "missed_run_policy must be skip, run_once or run_all"})
+		return
+	}
+	if payload.DedupKey != "" && !eventSet {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "dedup_key only applies to event triggers"})
+		return
+	}
+	switch {
+	case eventSet:
+		if err := payload.Event.validate(); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid event: " + err.Error()})
+			return
+		}
+		t.Type, t.Event, t.DedupKey = "event", payload.Event, strings.TrimSpace(payload.DedupKey)
+	case cronSet:
+		t.Type, t.Cron = "cron", strings.TrimSpace(payload.Cron)
+		s, err := parseCron(t.Cron)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid cron expression: " + err.Error()})
+			return
+		}
+		next, ok := s.next(now)
+		if !ok {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "cron expression never fires"})
+			return
+		}
+		t.schedule, t.next = s, next
+	default:
+		t.Type = "run_at"
+		at, err := time.Parse(time.RFC3339, strings.TrimSpace(payload.RunAt))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "run_at must be an RFC3339 timestamp"})
+			return
+		}
+		if !at.After(now) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "run_at must be in the future"})
+			return
+		}
+		t.RunAt, t.next = at.UTC().Format(time.RFC3339), at.UTC()
+	}
+	if payload.Type != "" && payload.Type != t.Type {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "type does not match the schedule given"})
+		return
+	}
+	if !t.next.IsZero() {
+		t.NextFireAt = t.next.Format(time.RFC3339)
+	}
+
+	trigMu.Lock()
+	triggerStore[t.ID] = t
+	view := *t
+	trigMu.Unlock()
+	if t.Type != "event" {
+		publishNextFires(id)
+		startTriggerScheduler()
+	}
+	c.JSON(http.StatusCreated, view)
+}
+
+// DeleteWorkflowTrigger removes one trigger
+// @Summary Delete workflow trigger
+// @Tags workflows
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Produce json
+// @Param workflowId path string true "Workflow ID"
+// @Param triggerId path string true "Trigger ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 404 {object} map[string]string
+// @Router /v1/workflows/{workflowId}/triggers/{triggerId} [delete]
+func DeleteWorkflowTrigger(c *gin.Context) {
+	id, triggerID := c.Param("workflowId"), c.Param("triggerId")
+	trigMu.Lock()
+	t, ok := triggerStore[triggerID]
+	if ok && t.WorkflowID == id {
+		delete(triggerStore, triggerID)
+	}
+	trigMu.Unlock()
+	if !ok || t.WorkflowID != id {
+		c.JSON(http.StatusNotFound, gin.H{"error": "trigger not found"})
+		return
+	}
+	publishNextFires(id)
+	c.JSON(http.StatusOK, gin.H{"deleted": triggerID})
+}
+
+// DeleteWorkflowTriggers removes all of a workflow's triggers
+// @Summary Delete all workflow triggers
+// @Tags workflows
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Produce json
+// @Param workflowId path string true "Workflow ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 404 {object} map[string]string
+// @Router /v1/workflows/{workflowId}/triggers [delete]
+func DeleteWorkflowTriggers(c *gin.Context) {
+	id := c.Param("workflowId")
+	if _, ok := workflowExists(c, id); !ok {
+		return
+	}
+	deleted := removeTriggers(id)
+	publishNextFires(id)
+	c.JSON(http.StatusOK, gin.H{"deleted": deleted})
+}
+
+// removeTriggers drops every trigger of the workflow and returns their IDs.
+func removeTriggers(workflowID string) []string {
+	trigMu.Lock()
+	defer trigMu.Unlock()
+	deleted := make([]string, 0)
+	for tid, t := range triggerStore {
+		if t.WorkflowID == workflowID {
+			delete(triggerStore, tid)
+			deleted = append(deleted, tid)
+		}
+	}
+	sort.Strings(deleted)
+	return deleted
+}
@@ -0,0 +1,316 @@
+package routes
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// workflowVersion is an immutable snapshot of a workflow definition. Every
+// create, update and rollback adds one.
+type workflowVersion struct {
+	Version     int            `json:"version"`
+	Name        string         `json:"name"`
+	Description string         `json:"description,omitempty"`
+	Steps       []WorkflowStep `json:"steps"`
+	Metadata    map[string]any `json:"metadata,omitempty"`
+	Change      string         `json:"change"` // created|imported|updated|rollback
+	RolledBack  int            `json:"rolled_back_to,omitempty"`
+	CreatedAt   string         `json:"created_at"`
+}
+
+// wfVersions holds every version per workflow ID, oldest first. Guarded by wfMu.
+var wfVersions = map[string][]workflowVersion{}
+
+// addVersion snapshots wf as its next version and bumps wf.Version. Caller
+// holds wfMu.
+func addVersion(wf *Workflow, change string, rolledBack int) {
+	wf.Version++
+	wfVersions[wf.ID] = append(wfVersions[wf.ID], workflowVersion{
+		Version:     wf.Version,
+		Name:        wf.Name,
+		Description: wf.Description,
+		Steps:       wf.Steps,
+		Metadata:    wf.Metadata,
+		Change:      change,
+		RolledBack:  rolledBack,
+		CreatedAt:   time.Now().UTC().Format(time.RFC3339),
+	})
+}
+
+// workflowAtVersion returns wf with the definition of version v. Caller holds
+// wfMu (read).
+func workflowAtVersion(wf Workflow, v int) (Workflow, bool) {
+	for _, ver := range wfVersions[wf.ID] {
+		if ver.Version == v {
+			wf.Name, wf.Description, wf.Steps, wf.Metadata, wf.Version = ver.Name, ver.Description, ver.Steps, ver.Metadata, ver.Version
+			return wf, true
+		}
+	}
+	return wf, false
+}
+
+// runnableWorkflow returns the definition new executions of wf run: the
+// published version once the workflow is active, else the current one.
+// Caller holds wfMu (read).
+func runnableWorkflow(wf Workflow) Workflow {
+	if wf.Status != "active" || wf.PublishedVersion == 0 {
+		return wf
+	}
+	if pub, ok := workflowAtVersion(wf, wf.PublishedVersion); ok {
+		return pub
+	}
+	return wf
+}
+
+// findVersion parses a version number and returns that version, answering
+// 400/404 when it is invalid or unknown.
+func findVersion(c *gin.Context, id, raw string) (workflowVersion, bool) {
+	n, err := strconv.Atoi(raw)
+	if err != nil || n < 1 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid version"})
+		return workflowVersion{}, false
+	}
+	wfMu.RLock()
+	defer wfMu.RUnlock()
+	for _, v := range wfVersions[id] {
+		if v.Version == n {
+			return v, true
+		}
+	}
+	c.JSON(http.StatusNotFound, gin.H{"error": "version not found"})
+	return workflowVersion{}, false
+}
+
+// ListWorkflowVersions lists a workflow's versions
+// @Summary List workflow versions
+// @Description Every version of the workflow definition, oldest first
+// @Tags workflows
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Produce json
+// @Param workflowId path string true "Workflow ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 404 {object} map[string]string
+// @Router /v1/workflows/{workflowId}/versions [get]
+func ListWorkflowVersions(c *gin.Context) {
+	id := c.Param("workflowId")
+	wfMu.RLock()
+	wf, ok := wfStore[id]
+	versions := append([]workflowVersion(nil), wfVersions[id]...)
+	wfMu.RUnlock()
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": "workflow not found"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"workflow_id":       id,
+		"current_version":   wf.Version,
+		"published_version": wf.PublishedVersion,
+		"versions":          versions,
+	})
+}
+
+// GetWorkflowVersion returns one version
+// @Summary Get workflow version
+// @Tags workflows
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Produce json
+// @Param workflowId path string true "Workflow ID"
+// @Param version path int true "Version"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /v1/workflows/{workflowId}/versions/{version} [get]
+func GetWorkflowVersion(c *gin.Context) {
+	if v, ok := findVersion(c, c.Param("workflowId"), c.Param("version")); ok {
+		c.JSON(http.StatusOK, v)
+	}
+}
+
+// DiffWorkflowVersions compares two versions
+// @Summary Diff workflow versions
+// @Description Steps added, removed and changed (per field) and metadata keys added, removed and changed between two versions
+// @Tags workflows
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Produce json
+// @Param workflowId path string true "Workflow ID"
+// @Param from query int true "Base version"
+// @Param to query int true "Compared version"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /v1/workflows/{workflowId}/versions/diff [get]
+func DiffWorkflowVersions(c *gin.Context) {
+	id := c.Param("workflowId")
+	from, ok := findVersion(c, id, c.Query("from"))
+	if !ok {
+		return
+	}
+	to, ok := findVersion(c, id, c.Query("to"))
+	if !ok {
+		return
+	}
+	fields := gin.H{}
+	if from.Name != to.Name {
+		fields["name"] = gin.H{"from": from.Name, "to": to.Name}
+	}
+	if from.Description != to.Description {
+		fields["description"] = gin.H{"from": from.Description, "to": to.Description}
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"workflow_id": id,
+		"from":        from.Version,
+		"to":          to.Version,
+		"fields":      fields,
+		"steps":       diffSteps(from.Steps, to.Steps),
+		"metadata":    diffMaps(from.Metadata, to.Metadata),
+	})
+}
+
+// diffSteps matches steps by ID and reports which were added, removed or
+// changed, with the changed fields of each.
+func diffSteps(from, to []WorkflowStep) gin.H {
+	old := map[string]map[string]any{}
+	for _, s := range from {
+		old[s.ID] = stepFields(s)
+	}
+	added, removed, changed := []string{}, []string{}, []gin.H{}
+	seen := map[string]bool{}
+	for _, s := range to {
+		seen[s.ID] = true
+		prev, ok := old[s.ID]
+		if !ok {
+			added = append(added, s.ID)
+			continue
+		}
+		if d := diffMaps(prev, stepFields(s)); len(d["changed"].(gin.H))+len(d["added"].(gin.H))+len(d["removed"].(gin.H)) > 0 {
+			changed = append(changed, gin.H{"id": s.ID, "fields": d})
+		}
+	}
+	for _, s := range from {
+		if !seen[s.ID] {
+			removed = append(removed, s.ID)
+		}
+	}
+	return gin.H{"added": added, "removed": removed, "changed": changed}
+}
+
+// stepFields is the step as its JSON object, for field-wise comparison.
+func stepFields(s WorkflowStep) map[string]any {
+	b, _ := json.Marshal(s)
+	var m map[string]any
+	_ = json.Unmarshal(b, &m)
+	return m
+}
+
+// diffMaps reports the keys added to, removed from and changed between two maps.
+func diffMaps(from, to map[string]any) gin.H {
+	added, removed, changed := gin.H{}, gin.H{}, gin.H{}
+	for k, v := range to {
+		prev, ok := from[k]
+		switch {
+		case !ok:
+			added[k] = v
+		case !reflect.DeepEqual(prev, v):
+			changed[k] = gin.H{"from": prev, "to": v}
+		}
+	}
+	for k, v := range from {
+		if _, ok := to[k]; !ok {
+			removed[k] = v
+		}
+	}
+	return gin.H{"added": added, "removed": removed, "changed": changed}
+}
+
+// PublishWorkflow publishes the current version
+// @Summary Publish workflow
+// @Description Publishes the current version: a draft workflow becomes active, an active one promotes its edits made since the last publish. New executions of an active workflow run the published version.
+// @Tags workflows
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Produce json
+// @Param workflowId path string true "Workflow ID"
+// @Success 200 {object} Workflow
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /v1/workflows/{workflowId}/publish [post]
+func PublishWorkflow(c *gin.Context) {
+	id := c.Param("workflowId")
+	wfMu.Lock()
+	wf, ok := wfStore[id]
+	if !ok {
+		wfMu.Unlock()
+		c.JSON(http.StatusNotFound, gin.H{"error": "workflow not found"})
+		return
+	}
+	if wf.Status == "archived" {
+		wfMu.Unlock()
+		c.JSON(http.StatusConflict, gin.H{"error": "workflow is archived", "status": wf.Status})
+		return
+	}
+	if wf.Status == "active" && wf.PublishedVersion == wf.Version {
+		wfMu.Unlock()
+		c.JSON(http.StatusConflict, gin.H{"error": "current version is already published", "published_version": wf.PublishedVersion})
+		return
+	}
+	wf.Status = "active"
+	wf.PublishedVersion = wf.Version
+	wf.UpdatedAt = time.Now().UTC().Format(time.RFC3339)
+	wfStore[id] = wf
+	wfMu.Unlock()
+	c.JSON(http.StatusOK, wf)
+}
+
+// RollbackWorkflow restores an earlier version
+// @Summary Roll back workflow
+// @Description Creates a new version with the definition of an earlier one. Running executions keep the version they started on.
+// @Tags workflows
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param workflowId path string true "Workflow ID"
+// @Success 200 {object} Workflow
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /v1/workflows/{workflowId}/rollback [post]
+func RollbackWorkflow(c *gin.Context) {
+	id := c.Param("workflowId")
+	var payload struct {
+		Version int `json:"version"`
+	}
+	if err := c.BindJSON(&payload); err != nil || payload.Version < 1 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "version is required"})
+		return
+	}
+	wfMu.Lock()
+	defer wfMu.Unlock()
+	wf, ok := wfStore[id]
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": "workflow not found"})
+		return
+	}
+	if wf.Status == "archived" {
+		c.JSON(http.StatusConflict, gin.H{"error": "workflow is archived", "status": wf.Status})
+		return
+	}
+	target, ok := workflowAtVersion(wf, payload.Version)
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": "version not found"})
+		return
+	}
+	target.Version = wf.Version
+	addVersion(&target, "rollback", payload.Version)
+	target.UpdatedAt = time.Now().UTC().Format(time.RFC3339)
+	wfStore[id] = target
+	c.JSON(http.StatusOK, target)
+}
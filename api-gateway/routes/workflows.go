@@ -2,6 +2,7 @@ package routes
 
 import (
 	"errors"
+	"fmt"
 	"net/http"
 	"sort"
 	"strconv"
@@ -10,14 +11,92 @@ import (
 	"time"
 
 	"github.com/gin-gonic/gin"
+	gwmiddleware "github.com/weltschmerz/QA-Playground/api-gateway/middleware"
 	"github.com/weltschmerz/QA-Playground/api-gateway/utils"
 )
 
 type WorkflowStep struct {
-	ID        string   `json:"id"`
-	Name      string   `json:"name"`
-	Type      string   `json:"type"`
-	DependsOn []string `json:"depends_on,omitempty"`
+	ID        string         `json:"id" yaml:"id"`
+	Name      string         `json:"name" yaml:"name"`
+	Type      string         `json:"type" yaml:"type"`
+	DependsOn []string       `json:"depends_on,omitempty" yaml:"depends_on,omitempty"`
+	Adapter   string         `json:"adapter,omitempty" yaml:"adapter,omitempty"`
+	Config    map[string]any `json:"config,omitempty" yaml:"config,omitempty"`
+	Timeout   int            `json:"timeout,omitempty" yaml:"timeout,omitempty"` // seconds per attempt, 0 = none
+
+	// Failure handling: failed attempts are retried per Retry; once they are
+	// used up, OnFailure decides what happens next.
+	Retry     *StepRetry `json:"retry,omitempty" yaml:"retry,omitempty"`
+	OnFailure string     `json:"on_failure,omitempty" yaml:"on_failure,omitempty"` // fail (default)|continue|<step id>
+
+	// Decision steps (approval, manual, task, automated): who may decide, how
+	// many approvals are needed and which step to route to on rejection.
+	Approvers   []string `json:"approvers,omitempty" yaml:"approvers,omitempty"`
+	Quorum      int      `json:"quorum,omitempty" yaml:"quorum,omitempty"`
+	RequiresAll bool     `json:"requires_all,omitempty" yaml:"requires_all,omitempty"`
+	OnReject    string   `json:"on_reject,omitempty" yaml:"on_reject,omitempty"`
+}
+
+// StepRetry is a step's retry policy: up to Count more attempts, waiting
+// BackoffMs before the first retry, doubled per retry when Backoff is
+// exponential.
+type StepRetry struct {
+	Count     int    `json:"count" yaml:"count"`
+	BackoffMs int    `json:"backoff_ms,omitempty" yaml:"backoff_ms,omitempty"`
+	Backoff   string `json:"backoff,omitempty" yaml:"backoff,omitempty"` // fixed (default)|exponential
+}
+
+// on_failure actions other than jumping to a compensating step.
+const (
+	onFailureFail     = "fail"
+	onFailureContinue = "continue"
+)
+
+// Retry policy limits.
+const (
+	maxStepRetries  = 10
+	maxRetryBackoff = 5 * time.Minute
+)
+
+// delay is the wait before retry number n (1-based).
+func (r *StepRetry) delay(n int) time.Duration {
+	d := time.Duration(r.BackoffMs) * time.Millisecond
+	if r.Backoff == "exponential" {
+		for i := 1; i < n && d < maxRetryBackoff; i++ {
+			d *= 2
+		}
+	}
+	return min(d, maxRetryBackoff)
+}
+
+// retries is the number of retries allowed after the first attempt.
+func (s WorkflowStep) retries() int {
+	if s.Retry == nil {
+		return 0
+	}
+	return s.Retry.Count
+}
+
+// compensatingStep is the step on_failure jumps to, if any.
+func (s WorkflowStep) compensatingStep() string {
+	switch s.OnFailure {
+	case "", onFailureFail, onFailureContinue:
+		return ""
+	}
+	return s.OnFailure
+}
+
+// requiredApprovals is the number of distinct approvals that complete a
+// decision step: every listed approver with requires_all, else the quorum,
+// else one.
+func (s WorkflowStep) requiredApprovals() int {
+	switch {
+	case s.RequiresAll && len(s.Approvers) > 0:
+		return len(s.Approvers)
+	case s.Quorum > 0:
+		return s.Quorum
+	}
+	return 1
 }
 
 type Workflow struct {
@@ -26,27 +105,52 @@ type Workflow struct {
 	Description      string                 `json:"description,omitempty"`
 	Steps            []WorkflowStep         `json:"steps"`
 	Metadata         map[string]interface{} `json:"metadata,omitempty"`
-	Status           string                 `json:"status"`
+	Status           string                 `json:"status"` // draft|active|archived
+	Version          int                    `json:"version"`
+	PublishedVersion int                    `json:"published_version,omitempty"`
 	CreatedAt        string                 `json:"created_at"`
 	UpdatedAt        string                 `json:"updated_at"`
 	ExecutionHistory []map[string]any       `json:"execution_history"`
 	CurrentStep      string                 `json:"current_step"`
+	// NextFireTimes are the upcoming fire times of the workflow's triggers,
+	// kept up to date by the trigger scheduler.
+	NextFireTimes []string `json:"next_fire_times,omitempty"`
+	ArchivedAt    string   `json:"archived_at,omitempty"`
 }
 
 type workflowExecution struct {
-	ExecutionID string           `json:"execution_id"`
-	WorkflowID  string           `json:"workflow_id"`
-	Status      string           `json:"status"`
-	CurrentStep string           `json:"current_step"`
-	StartedAt   string           `json:"started_at"`
-	TriggeredBy string           `json:"triggered_by,omitempty"`
-	History     []map[string]any `json:"history,omitempty"`
+	ExecutionID string `json:"execution_id"`
+	WorkflowID  string `json:"workflow_id"`
+	// WorkflowVersion is the definition version the execution runs; later
+	// updates do not affect it.
+	WorkflowVersion int              `json:"workflow_version"`
+	Status          string           `json:"status"`
+	CurrentStep     string           `json:"current_step"`
+	StartedAt       string           `json:"started_at"`
+	TriggeredBy     string           `json:"triggered_by,omitempty"`
+	History         []map[string]any `json:"history,omitempty"`
+
+	Steps       map[string]*stepState `json:"steps"`
+	Progress    string                `json:"progress"`
+	CompletedAt string                `json:"completed_at,omitempty"`
+	Error       string                `json:"error,omitempty"`
+	Parameters  map[string]any        `json:"parameters,omitempty"`
+	Context     map[string]any        `json:"context,omitempty"`
+	RetryOf     string                `json:"retry_of,omitempty"`
+	// RetainedUntil is set once the workflow is deleted: the execution is
+	// dropped at that time.
+	RetainedUntil string `json:"retained_until,omitempty"`
+
+	seq         uint64    // start order, for listing
+	retainUntil time.Time // RetainedUntil, zero while the workflow exists
 }
 
 // In-memory stores (kept here to keep main.go light; replace with DB in real app)
 var (
-	wfStore   = map[string]Workflow{}
-	execStore = map[string]workflowExecution{}
+	wfStore    = map[string]Workflow{}
+	execStore  = map[string]*workflowExecution{} // by execution ID
+	latestExec = map[string]string{}             // workflow ID -> latest execution ID
+	execSeq    uint64
 )
 
 // Guards for concurrent access
@@ -63,6 +167,7 @@ var (
 // @Produce json
 // @Param name query string false "Filter by name contains"
 // @Param status query string false "Filter by status"
+// @Param include_archived query bool false "Include archived workflows (hidden unless status=archived)"
 // @Param sort_by query string false "Sort field: created_at|name"
 // @Param order query string false "asc|desc"
 // @Param page query int false "Page number"
@@ -73,6 +178,7 @@ func ListWorkflows(c *gin.Context) {
 	// Optional filters and sorting
 	nameFilter := strings.TrimSpace(c.Query("name"))
 	statusFilter := strings.TrimSpace(c.Query("status"))
+	includeArchived := c.Query("include_archived") == "true"
 	sortBy := c.DefaultQuery("sort_by", "created_at")
 	order := strings.ToLower(c.DefaultQuery("order", "desc"))
 
@@ -85,6 +191,9 @@ func ListWorkflows(c *gin.Context) {
 		if statusFilter != "" && !strings.EqualFold(wf.Status, statusFilter) {
 			continue
 		}
+		if statusFilter == "" && !includeArchived && wf.Status == "archived" {
+			continue
+		}
 		items = append(items, wf)
 	}
 	wfMu.RUnlock()
@@ -155,6 +264,13 @@ func CreateWorkflow(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
+	storeNewWorkflow(&wf, "created")
+	c.JSON(http.StatusCreated, wf)
+}
+
+// storeNewWorkflow saves wf as a new draft with its first version, recording
+// change (created|imported) on that version.
+func storeNewWorkflow(wf *Workflow, change string) {
 	now := time.Now().UTC().Format(time.RFC3339)
 	if wf.ID == "" {
 		wf.ID = "wf-" + utils.GenID()[:12]
@@ -162,10 +278,13 @@ func CreateWorkflow(c *gin.Context) {
 	wf.Status = "draft"
 	wf.CreatedAt = now
 	wf.UpdatedAt = now
+	wf.Version, wf.PublishedVersion = 0, 0
+	wf.NextFireTimes = nil
 	wfMu.Lock()
-	wfStore[wf.ID] = wf
+	delete(wfVersions, wf.ID)
+	addVersion(wf, change, 0)
+	wfStore[wf.ID] = *wf
 	wfMu.Unlock()
-	c.JSON(http.StatusCreated, wf)
 }
 
 // GetWorkflow returns a single workflow
@@ -192,6 +311,7 @@ func GetWorkflow(c *gin.Context) {
 
 // UpdateWorkflow updates a workflow
 // @Summary Update workflow
+// @Description Every update creates a new version (see /versions); running executions keep the version they started on. Edits to an active workflow take effect once published.
 // @Tags workflows
 // @Security BearerAuth
 // @Security ApiKeyAuth
@@ -201,11 +321,12 @@ func GetWorkflow(c *gin.Context) {
 // @Success 200 {object} Workflow
 // @Failure 400 {object} map[string]string
 // @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
 // @Router /v1/workflows/{workflowId} [put]
 func UpdateWorkflow(c *gin.Context) {
 	id := c.Param("workflowId")
 	wfMu.RLock()
-	old, ok := wfStore[id]
+	_, ok := wfStore[id]
 	wfMu.RUnlock()
 	if !ok {
 		c.JSON(http.StatusNotFound, gin.H{"error": "workflow not found"})
@@ -222,6 +343,18 @@ func UpdateWorkflow(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid payload"})
 		return
 	}
+	// read-modify-write under the lock so concurrent updates each get a version
+	wfMu.Lock()
+	defer wfMu.Unlock()
+	old, ok := wfStore[id]
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": "workflow not found"})
+		return
+	}
+	if old.Status == "archived" {
+		c.JSON(http.StatusConflict, gin.H{"error": "workflow is archived", "status": old.Status})
+		return
+	}
 	if patch.Name != nil {
 		if strings.TrimSpace(*patch.Name) == "" {
 			c.JSON(http.StatusBadRequest, gin.H{"error": "name is required"})
@@ -248,14 +381,14 @@ func UpdateWorkflow(c *gin.Context) {
 		old.Metadata = patch.Metadata
 	}
 	old.UpdatedAt = time.Now().UTC().Format(time.RFC3339)
-	wfMu.Lock()
+	addVersion(&old, "updated", 0)
 	wfStore[id] = old
-	wfMu.Unlock()
 	c.JSON(http.StatusOK, old)
 }
 
 // ExecuteWorkflow triggers a workflow execution
 // @Summary Execute workflow
+// @Description Starts running the step DAG in the background: steps start once all their depends_on steps completed, independent steps run in parallel. Executions of the same workflow run independently; see /executions to list or cancel them.
 // @Tags workflows
 // @Security BearerAuth
 // @Security ApiKeyAuth
@@ -264,45 +397,68 @@ func UpdateWorkflow(c *gin.Context) {
 // @Param workflowId path string true "Workflow ID"
 // @Success 202 {object} map[string]interface{}
 // @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
 // @Router /v1/workflows/{workflowId}/execute [post]
 func ExecuteWorkflow(c *gin.Context) {
 	id := c.Param("workflowId")
 	wfMu.RLock()
 	wf, ok := wfStore[id]
+	wf = runnableWorkflow(wf)
 	wfMu.RUnlock()
 	if !ok {
 		c.JSON(http.StatusNotFound, gin.H{"error": "workflow not found"})
 		return
 	}
+	if wf.Status == "archived" {
+		c.JSON(http.StatusConflict, gin.H{"error": "workflow is archived", "status": wf.Status})
+		return
+	}
 	var payload struct {
 		TriggeredBy string                 `json:"triggered_by"`
 		Context     map[string]interface{} `json:"context"`
 		Parameters  map[string]interface{} `json:"parameters"`
 	}
 	_ = c.BindJSON(&payload)
-	ex := workflowExecution{
-		ExecutionID: "exec-" + time.Now().UTC().Format("20060102T150405Z"),
-		WorkflowID:  id,
-		Status:      "started",
-		CurrentStep: firstStep(wf),
-		StartedAt:   time.Now().UTC().Format(time.RFC3339),
-		TriggeredBy: payload.TriggeredBy,
+	ex := newExecution(wf, payload.TriggeredBy, payload.Parameters, payload.Context)
+	launchExecution(c, wf, ex)
+}
+
+// newExecution prepares an execution of wf; startExecution runs it.
+func newExecution(wf Workflow, triggeredBy string, params, execCtx map[string]any) *workflowExecution {
+	return &workflowExecution{
+		ExecutionID:     "exec-" + utils.GenID()[:12],
+		WorkflowID:      wf.ID,
+		WorkflowVersion: wf.Version,
+		Status:          execStarted,
+		CurrentStep:     firstStep(wf),
+		StartedAt:       time.Now().UTC().Format(time.RFC3339),
+		TriggeredBy:     triggeredBy,
+		Parameters:      params,
+		Context:         execCtx,
 	}
-	execMu.Lock()
-	execStore[id] = ex
-	execMu.Unlock()
-	c.JSON(http.StatusAccepted, gin.H{
-		"execution_id": ex.ExecutionID,
-		"workflow_id":  ex.WorkflowID,
-		"status":       ex.Status,
-		"current_step": ex.CurrentStep,
-		"triggered_by": ex.TriggeredBy,
-		"started_at":   ex.StartedAt,
-	})
+}
+
+// launchExecution starts ex on behalf of the caller and answers 202.
+func launchExecution(c *gin.Context, wf Workflow, ex *workflowExecution) {
+	resp := gin.H{
+		"execution_id":     ex.ExecutionID,
+		"workflow_id":      ex.WorkflowID,
+		"workflow_version": ex.WorkflowVersion,
+		"status":           ex.Status,
+		"current_step":     ex.CurrentStep,
+		"triggered_by":     ex.TriggeredBy,
+		"started_at":       ex.StartedAt,
+	}
+	if ex.RetryOf != "" {
+		resp["retry_of"] = ex.RetryOf
+	}
+	startExecution(wf, ex, c.GetHeader("Authorization"), notifTenant(c), gwmiddleware.CallerSubject(c))
+	c.JSON(http.StatusAccepted, resp)
 }
 
 // GetWorkflowStatus returns execution status
 // @Summary Get workflow status
+// @Description Status of the workflow's latest execution (see /executions for all of them): overall status (started|in_progress|waiting_approval|completed|failed|timeout|cancelled), progress as the share of completed steps, per-step state and the execution history.
 // @Tags workflows
 // @Security BearerAuth
 // @Security ApiKeyAuth
@@ -314,51 +470,83 @@ func ExecuteWorkflow(c *gin.Context) {
 func GetWorkflowStatus(c *gin.Context) {
 	id := c.Param("workflowId")
 	execMu.RLock()
-	ex, ok := execStore[id]
-	execMu.RUnlock()
+	cur, ok := execStore[latestExec[id]]
+	var ex workflowExecution
 	if ok {
-		// Detect timeout scenario if special step is present
-		wfMu.RLock()
-		wf, ok := wfStore[id]
-		wfMu.RUnlock()
-		if ok {
-			hasTimeout := false
-			for _, s := range wf.Steps {
-				if s.ID == "timeout_step" {
-					hasTimeout = true
-					break
-				}
-			}
-			if hasTimeout {
-				if t, err := time.Parse(time.RFC3339, ex.StartedAt); err == nil {
-					if time.Since(t) > 1500*time.Millisecond {
-						ex.Status = "timeout"
-						execMu.Lock()
-						execStore[id] = ex
-						execMu.Unlock()
-					}
-				}
-			}
-		}
-		// Build a richer status payload
-		c.JSON(http.StatusOK, gin.H{
-			"workflow_id":          id,
-			"status":               ex.Status,
-			"current_step":         ex.CurrentStep,
-			"progress":             "0%",
-			"execution_history":    ex.History,
-			"next_actions":         []string{"continue", "pause"},
-			"total_steps":          func() int { wfMu.RLock(); defer wfMu.RUnlock(); return len(wfStore[id].Steps) }(),
-			"started_at":           ex.StartedAt,
-			"estimated_completion": time.Now().Add(5 * time.Minute).Format(time.RFC3339),
-		})
+		ex = cur.snapshot()
+	}
+	execMu.RUnlock()
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": "no execution found"})
 		return
 	}
-	c.JSON(http.StatusNotFound, gin.H{"error": "no execution found"})
+	c.JSON(http.StatusOK, gin.H{
+		"workflow_id":       id,
+		"workflow_version":  ex.WorkflowVersion,
+		"execution_id":      ex.ExecutionID,
+		"status":            ex.Status,
+		"current_step":      ex.CurrentStep,
+		"progress":          ex.Progress,
+		"steps":             ex.Steps,
+		"execution_history": ex.History,
+		"next_actions":      nextActions(ex.Status),
+		"total_steps":       len(ex.Steps),
+		"started_at":        ex.StartedAt,
+		"completed_at":      ex.CompletedAt,
+		"error":             ex.Error,
+	})
+}
+
+func nextActions(status string) []string {
+	switch status {
+	case execWaitingApproval:
+		return []string{"approve", "reject"}
+	case execStarted, execInProgress:
+		return []string{"wait"}
+	}
+	return []string{"execute"}
+}
+
+// decisionTime returns ts when it is a valid RFC3339 timestamp, else now.
+func decisionTime(ts string) string {
+	if t, err := time.Parse(time.RFC3339, strings.TrimSpace(ts)); err == nil {
+		return t.UTC().Format(time.RFC3339)
+	}
+	return time.Now().UTC().Format(time.RFC3339)
+}
+
+// decide records an approve/reject decision on a waiting step of execID, or
+// of the workflow's latest execution, answering 403/404/409 on failure.
+func decide(c *gin.Context, id, execID, stepID string, rec approvalRecord) (decisionResult, bool) {
+	execMu.Lock()
+	defer execMu.Unlock()
+	if execID == "" {
+		execID = latestExec[id]
+	}
+	if ex, ok := execStore[execID]; !ok || ex.WorkflowID != id {
+		c.JSON(http.StatusNotFound, gin.H{"error": "no execution found"})
+		return decisionResult{}, false
+	}
+	run := runs[execID]
+	if run == nil {
+		c.JSON(http.StatusConflict, gin.H{"error": "no step is waiting for a decision"})
+		return decisionResult{}, false
+	}
+	res, err := run.recordDecision(stepID, rec)
+	switch {
+	case errors.Is(err, errNotApprover):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		return res, false
+	case err != nil:
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return res, false
+	}
+	return res, true
 }
 
 // ApproveWorkflow approves a workflow step
 // @Summary Approve workflow step
+// @Description Records an approval for the waiting step given by step_id, or the first step waiting for a decision, of the execution given by execution_id (default: the latest) (approval steps, and manual/task/automated steps that are signed off this way). When the step lists approvers only they may decide; the step resumes once its quorum (requires_all: every approver) of distinct approvals is reached.
 // @Tags workflows
 // @Security BearerAuth
 // @Security ApiKeyAuth
@@ -367,15 +555,19 @@ func GetWorkflowStatus(c *gin.Context) {
 // @Param workflowId path string true "Workflow ID"
 // @Success 200 {object} map[string]interface{}
 // @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
 // @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
 // @Router /v1/workflows/{workflowId}/approve [post]
 func ApproveWorkflow(c *gin.Context) {
 	id := c.Param("workflowId")
 	var payload struct {
-		Approver  string `json:"approver"`
-		Decision  string `json:"decision"`
-		Comments  string `json:"comments"`
-		Timestamp string `json:"timestamp"`
+		ExecutionID string `json:"execution_id"`
+		StepID      string `json:"step_id"`
+		Approver    string `json:"approver"`
+		Decision    string `json:"decision"`
+		Comments    string `json:"comments"`
+		Timestamp   string `json:"timestamp"`
 	}
 	if err := c.BindJSON(&payload); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid approval data"})
@@ -389,22 +581,37 @@ func ApproveWorkflow(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "decision invalid"})
 		return
 	}
-	if _, ok := execStore[id]; !ok {
-		c.JSON(http.StatusNotFound, gin.H{"error": "no execution found"})
+	res, ok := decide(c, id, payload.ExecutionID, payload.StepID, approvalRecord{
+		Approver:  strings.TrimSpace(payload.Approver),
+		Decision:  "approved",
+		Comments:  payload.Comments,
+		Timestamp: decisionTime(payload.Timestamp),
+	})
+	if !ok {
 		return
 	}
+	nextStep, status := "proceed", execInProgress
+	if res.Remaining > 0 {
+		nextStep, status = "awaiting_approvals", execWaitingApproval
+	}
 	c.JSON(http.StatusOK, gin.H{
-		"workflow_id":    id,
-		"approver":       payload.Approver,
-		"decision":       "approved",
-		"comments":       payload.Comments,
-		"next_step":      "proceed",
-		"updated_status": "in_progress",
+		"workflow_id":         id,
+		"execution_id":        res.ExecutionID,
+		"step_id":             res.StepID,
+		"approver":            payload.Approver,
+		"decision":            "approved",
+		"comments":            payload.Comments,
+		"approvals":           res.Approvals,
+		"approvals_required":  res.Required,
+		"approvals_remaining": res.Remaining,
+		"next_step":           nextStep,
+		"updated_status":      status,
 	})
 }
 
 // RejectWorkflow rejects a workflow step
 // @Summary Reject workflow step
+// @Description Rejects the waiting step given by step_id, or the first step waiting for a decision, of the execution given by execution_id (default: the latest). A single rejection decides the step. With on_reject the execution continues with that correction branch, otherwise it fails.
 // @Tags workflows
 // @Security BearerAuth
 // @Security ApiKeyAuth
@@ -413,35 +620,57 @@ func ApproveWorkflow(c *gin.Context) {
 // @Param workflowId path string true "Workflow ID"
 // @Success 200 {object} map[string]interface{}
 // @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
 // @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
 // @Router /v1/workflows/{workflowId}/reject [post]
 func RejectWorkflow(c *gin.Context) {
 	id := c.Param("workflowId")
 	var payload struct {
-		Approver string `json:"approver"`
-		Decision string `json:"decision"`
-		Comments string `json:"comments"`
-		Reason   string `json:"reason"`
+		ExecutionID string `json:"execution_id"`
+		StepID      string `json:"step_id"`
+		Approver    string `json:"approver"`
+		Decision    string `json:"decision"`
+		Comments    string `json:"comments"`
+		Reason      string `json:"reason"`
+		Timestamp   string `json:"timestamp"`
 	}
 	if err := c.BindJSON(&payload); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid rejection data"})
 		return
 	}
+	if strings.TrimSpace(payload.Approver) == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "approver is required"})
+		return
+	}
 	if strings.ToLower(payload.Decision) != "rejected" {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "decision invalid"})
 		return
 	}
-	if _, ok := execStore[id]; !ok {
-		c.JSON(http.StatusNotFound, gin.H{"error": "no execution found"})
+	res, ok := decide(c, id, payload.ExecutionID, payload.StepID, approvalRecord{
+		Approver:  strings.TrimSpace(payload.Approver),
+		Decision:  "rejected",
+		Comments:  payload.Comments,
+		Reason:    payload.Reason,
+		Timestamp: decisionTime(payload.Timestamp),
+	})
+	if !ok {
 		return
 	}
+	status := execFailed
+	if res.OnReject != "" {
+		status = execInProgress
+	}
 	c.JSON(http.StatusOK, gin.H{
 		"workflow_id":     id,
+		"execution_id":    res.ExecutionID,
+		"step_id":         res.StepID,
 		"approver":        payload.Approver,
 		"decision":        "rejected",
 		"comments":        payload.Comments,
 		"reason":          payload.Reason,
-		"workflow_status": "waiting_correction",
+		"next_step":       res.OnReject,
+		"workflow_status": status,
 	})
 }
 
@@ -464,7 +693,6 @@ func validateSteps(steps []WorkflowStep) error {
 		return errors.New("steps must not be empty")
 	}
 	ids := map[string]bool{}
-	graph := map[string][]string{}
 	for _, s := range steps {
 		if strings.TrimSpace(s.ID) == "" || strings.TrimSpace(s.Name) == "" || strings.TrimSpace(s.Type) == "" {
 			return errors.New("step missing required fields")
@@ -472,10 +700,64 @@ func validateSteps(steps []WorkflowStep) error {
 		if ids[s.ID] {
 			return errors.New("duplicate step id")
 		}
+		if s.Timeout < 0 {
+			return errors.New("step timeout must not be negative")
+		}
+		if r := s.Retry; r != nil {
+			if r.Count < 0 || r.Count > maxStepRetries {
+				return fmt.Errorf("step %s retry count must be between 0 and %d", s.ID, maxStepRetries)
+			}
+			if r.BackoffMs < 0 {
+				return fmt.Errorf("step %s retry backoff_ms must not be negative", s.ID)
+			}
+			if r.Backoff != "" && r.Backoff != "fixed" && r.Backoff != "exponential" {
+				return fmt.Errorf("step %s retry backoff must be fixed or exponential", s.ID)
+			}
+		}
+		if s.Quorum < 0 {
+			return fmt.Errorf("step %s quorum must not be negative", s.ID)
+		}
+		if len(s.Approvers) > 0 && s.Quorum > len(s.Approvers) {
+			return fmt.Errorf("step %s quorum exceeds the number of approvers", s.ID)
+		}
 		ids[s.ID] = true
-		graph[s.ID] = append(graph[s.ID], s.DependsOn...)
+	}
+	byID := map[string]WorkflowStep{}
+	for _, s := range steps {
+		byID[s.ID] = s
+	}
+	for _, s := range steps {
+		for _, d := range s.DependsOn {
+			if !ids[d] {
+				return fmt.Errorf("step %s depends on unknown step %s", s.ID, d)
+			}
+		}
+		if err := validateTemplates(s, byID); err != nil {
+			return err
+		}
+		if s.OnReject != "" {
+			target, ok := byID[s.OnReject]
+			if !ok || s.OnReject == s.ID {
+				return fmt.Errorf("step %s on_reject must name another step", s.ID)
+			}
+			// branch steps are started by the rejection, not by dependencies
+			if len(target.DependsOn) > 0 {
+				return fmt.Errorf("on_reject step %s must not have depends_on", target.ID)
+			}
+		}
+		if comp := s.compensatingStep(); comp != "" {
+			target, ok := byID[comp]
+			if !ok || comp == s.ID {
+				return fmt.Errorf("step %s on_failure must be fail, continue or another step", s.ID)
+			}
+			// compensating steps are started by the failure, not by dependencies
+			if len(target.DependsOn) > 0 {
+				return fmt.Errorf("on_failure step %s must not have depends_on", target.ID)
+			}
+		}
 	}
 	// simple cycle detect
+	graph := dependencyGraph(steps)
 	visited := map[string]int{}
 	var dfs func(string) bool
 	dfs = func(u string) bool {
@@ -501,3 +783,12 @@ func validateSteps(steps []WorkflowStep) error {
 	}
 	return nil
 }
+
+// dependencyGraph maps each step ID to the IDs of the steps it depends on.
+func dependencyGraph(steps []WorkflowStep) map[string][]string {
+	graph := make(map[string][]string, len(steps))
+	for _, s := range steps {
+		graph[s.ID] = append(graph[s.ID], s.DependsOn...)
+	}
+	return graph
+}
@@ -257,7 +257,7 @@
       const inputs = JSON.parse(document.getElementById('ai-batch-inputs').value || '["hello","world"]');
   const { data } = await fetchJSON(`${state.base}/v1/ai/batch`, { method: 'POST', headers: headers(), body: JSON.stringify({ inputs }) });
   out('ai-out', data);
-  const id = data?.jobId || data?.id; if (id) { localStorage.setItem('qa.aiJobId', id); if ($('ai-job-id')) $('ai-job-id').value = id; }
+  const id = data?.job_id || data?.jobId || data?.id; if (id) { localStorage.setItem('qa.aiJobId', id); if ($('ai-job-id')) $('ai-job-id').value = id; }
     } catch (e) {
       out('ai-out', { error: String(e) });
     }
@@ -32,6 +32,7 @@ export default defineConfig({
         "tests/ui/**",
         "tests/notifications/**",
         "tests/exercises/**",
+        "tests/workflows/workflow-triggers.spec.ts",
       ],
       // keep excluding swagger-tagged tests from this project
       grepInvert: /@swagger/,
@@ -52,10 +53,21 @@ export default defineConfig({
     {
       name: "notifications-single",
       testDir: "tests",
-      testMatch: /notifications\/notifications\.spec\.ts/,
+      testMatch: /notifications\/notification(s|-inbox|-stream)\.spec\.ts/,
       testIgnore: ["tests/ui/**", "tests/exercises/**"],
       fullyParallel: false,
     },
+    {
+      // specs that move the global scheduler clock must never overlap
+      name: "scheduler-clock",
+      testDir: "tests",
+      testMatch: [
+        /workflows\/workflow-triggers\.spec\.ts/,
+        /notifications\/notification-broadcasts\.spec\.ts/,
+      ],
+      fullyParallel: false,
+      workers: 1,
+    },
     {
       name: "exercises",
       testDir: "tests/exercises",
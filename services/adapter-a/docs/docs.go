@@ -325,6 +325,49 @@ const docTemplate = `{
                 }
             }
         },
+        "/completions/stream": {
+            "post": {
+                "description": "Generate a streaming text completion as server-sent events; the final chunk carries finish_reason and usage",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "text/event-stream"
+                ],
+                "tags": [
+                    "completion"
+                ],
+                "summary": "Stream completion",
+                "parameters": [
+                    {
+                        "description": "Streaming completion request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/main.completeRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Server-sent events stream",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
         "/config": {
             "get": {
                 "description": "Get current service configuration",
@@ -758,6 +801,12 @@ const docTemplate = `{
                     "type": "string",
                     "example": "active"
                 },
+                "supported_features": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
                 "type": {
                     "type": "string",
                     "example": "text-completion"
@@ -800,6 +849,16 @@ const docTemplate = `{
                     "type": "string",
                     "example": "Hello world"
                 },
+                "stop_sequences": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "system_prompt": {
+                    "type": "string",
+                    "example": "You are a helpful assistant"
+                },
                 "temperature": {
                     "type": "number",
                     "example": 0.7
@@ -821,6 +880,10 @@ const docTemplate = `{
                     "type": "string",
                     "example": "2025-09-16T10:30:00Z"
                 },
+                "finish_reason": {
+                    "type": "string",
+                    "example": "stop"
+                },
                 "model": {
                     "type": "string",
                     "example": "adapter-a"
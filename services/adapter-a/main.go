@@ -1,6 +1,7 @@
 package main
 
 import (
+	"encoding/json"
 	"fmt"
 	"math/rand"
 	"net/http"
@@ -14,6 +15,7 @@ import (
 	ginSwagger "github.com/swaggo/gin-swagger"
 
 	_ "github.com/weltschmerz/QA-Playground/services/adapter-a/docs"
+	"github.com/weltschmerz/QA-Playground/services/shared/limits"
 )
 
 // @title AI Adapter A API
@@ -22,19 +24,22 @@ import (
 // @BasePath /
 
 type completeRequest struct {
-	Prompt      string  `json:"prompt" example:"Hello world"`
-	Model       string  `json:"model" example:"adapter-a"`
-	Temperature float32 `json:"temperature,omitempty" example:"0.7"`
-	MaxTokens   int     `json:"max_tokens,omitempty" example:"100"`
-	TopP        float32 `json:"top_p,omitempty" example:"0.9"`
+	Prompt        string   `json:"prompt" example:"Hello world"`
+	Model         string   `json:"model" example:"adapter-a"`
+	Temperature   float32  `json:"temperature,omitempty" example:"0.7"`
+	MaxTokens     int      `json:"max_tokens,omitempty" example:"100"`
+	TopP          float32  `json:"top_p,omitempty" example:"0.9"`
+	SystemPrompt  string   `json:"system_prompt,omitempty" example:"You are a helpful assistant"`
+	StopSequences []string `json:"stop_sequences,omitempty"`
 }
 
 type completeResponse struct {
-	Model      string    `json:"model" example:"adapter-a"`
-	Completion string    `json:"completion" example:"A: Hello world response"`
-	Usage      UsageInfo `json:"usage"`
-	RequestID  string    `json:"request_id" example:"req-123"`
-	CreatedAt  string    `json:"created_at" example:"2025-09-16T10:30:00Z"`
+	Model        string    `json:"model" example:"adapter-a"`
+	Completion   string    `json:"completion" example:"A: Hello world response"`
+	Usage        UsageInfo `json:"usage"`
+	RequestID    string    `json:"request_id" example:"req-123"`
+	CreatedAt    string    `json:"created_at" example:"2025-09-16T10:30:00Z"`
+	FinishReason string    `json:"finish_reason" example:"stop"`
 }
 
 type UsageInfo struct {
@@ -43,6 +48,21 @@ type UsageInfo struct {
 	TotalTokens      int `json:"total_tokens" example:"35"`
 }
 
+type StreamingResponse struct {
+	ID      string         `json:"id"`
+	Object  string         `json:"object"`
+	Created int64          `json:"created"`
+	Model   string         `json:"model"`
+	Choices []StreamChoice `json:"choices"`
+	Usage   *UsageInfo     `json:"usage,omitempty"`
+}
+
+type StreamChoice struct {
+	Index        int                    `json:"index"`
+	Delta        map[string]interface{} `json:"delta"`
+	FinishReason *string                `json:"finish_reason"`
+}
+
 type ModelInfo struct {
 	Name         string                 `json:"name" example:"adapter-a"`
 	Version      string                 `json:"version" example:"1.2.0"`
@@ -116,6 +136,13 @@ func main() {
 		}
 		complete(c)
 	})
+	r.POST("/completions/stream", func(c *gin.Context) {
+		if strings.TrimSpace(c.GetHeader("Authorization")) == "" {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+			return
+		}
+		streamComplete(c)
+	})
 
 	// Model management endpoints
 	r.GET("/model", getModelInfo)
@@ -194,8 +221,7 @@ func complete(c *gin.Context) {
 	}
 
 	// Generate completion with variable length
-	completionLength := 20 + rand.Intn(100)
-	completion := fmt.Sprintf("A: %s [Generated response with %d chars]", req.Prompt, completionLength)
+	completion, finishReason := limits.Apply(generateCompletion(req), req.MaxTokens, 4, req.StopSequences)
 
 	requestID := fmt.Sprintf("req-%d-%d", time.Now().Unix(), rand.Intn(10000))
 
@@ -207,13 +233,82 @@ func complete(c *gin.Context) {
 			CompletionTokens: len(completion) / 4,
 			TotalTokens:      (len(req.Prompt) + len(completion)) / 4,
 		},
-		RequestID: requestID,
-		CreatedAt: time.Now().Format(time.RFC3339),
+		RequestID:    requestID,
+		CreatedAt:    time.Now().Format(time.RFC3339),
+		FinishReason: finishReason,
 	}
 
 	c.JSON(http.StatusOK, response)
 }
 
+// @Summary Stream completion
+// @Description Generate a streaming text completion as server-sent events; the final chunk carries finish_reason and usage
+// @Tags completion
+// @Accept json
+// @Produce text/event-stream
+// @Param request body completeRequest true "Streaming completion request"
+// @Success 200 {string} string "Server-sent events stream"
+// @Failure 400 {object} map[string]string
+// @Router /completions/stream [post]
+func streamComplete(c *gin.Context) {
+	var req completeRequest
+	if err := c.ShouldBindJSON(&req); err != nil || req.Prompt == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid payload"})
+		return
+	}
+	if len(req.Prompt) > 5000 {
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": "prompt too long"})
+		return
+	}
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+
+	streamID := fmt.Sprintf("stream-a-%d-%d", time.Now().Unix(), rand.Intn(10000))
+	completion, finishReason := limits.Apply(generateCompletion(req), req.MaxTokens, 4, req.StopSequences)
+	chunk := func(delta map[string]interface{}) StreamingResponse {
+		return StreamingResponse{
+			ID:      streamID,
+			Object:  "text_completion.chunk",
+			Created: time.Now().Unix(),
+			Model:   "adapter-a",
+			Choices: []StreamChoice{{Index: 0, Delta: delta}},
+		}
+	}
+	for _, word := range strings.Fields(completion) {
+		data, _ := json.Marshal(chunk(map[string]interface{}{"content": word + " "}))
+		c.SSEvent("", string(data))
+		c.Writer.Flush()
+		select {
+		case <-c.Request.Context().Done():
+			// client disconnected
+			return
+		case <-time.After(30 * time.Millisecond):
+		}
+	}
+	// the final chunk is sent even when a stop sequence left no words
+	final := chunk(map[string]interface{}{})
+	final.Choices[0].FinishReason = &finishReason
+	final.Usage = &UsageInfo{
+		PromptTokens:     len(req.Prompt) / 4,
+		CompletionTokens: len(completion) / 4,
+		TotalTokens:      (len(req.Prompt) + len(completion)) / 4,
+	}
+	data, _ := json.Marshal(final)
+	c.SSEvent("", string(data))
+	c.SSEvent("", "[DONE]")
+	c.Writer.Flush()
+}
+
+func generateCompletion(req completeRequest) string {
+	completionLength := 20 + rand.Intn(100)
+	if req.SystemPrompt != "" {
+		return fmt.Sprintf("A: [System: %s] %s [Generated response with %d chars]", req.SystemPrompt, req.Prompt, completionLength)
+	}
+	return fmt.Sprintf("A: %s [Generated response with %d chars]", req.Prompt, completionLength)
+}
+
 // @Summary Get model information
 // @Description Get detailed information about the AI model
 // @Tags model
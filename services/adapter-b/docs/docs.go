@@ -624,6 +624,12 @@ const docTemplate = `{
                     "type": "string",
                     "example": "adapter-b"
                 },
+                "stop": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
                 "stream": {
                     "type": "boolean",
                     "example": false
@@ -631,6 +637,10 @@ const docTemplate = `{
                 "temperature": {
                     "type": "number",
                     "example": 0.7
+                },
+                "top_p": {
+                    "type": "number",
+                    "example": 0.9
                 }
             }
         },
@@ -795,6 +805,9 @@ const docTemplate = `{
                     "type": "string",
                     "example": "2025-09-16T10:30:00Z"
                 },
+                "finish_reason": {
+                    "type": "string"
+                },
                 "metadata": {
                     "$ref": "#/definitions/main.ResponseMetadata"
                 },
@@ -7,6 +7,7 @@ require (
 	github.com/swaggo/files v1.0.1
 	github.com/swaggo/gin-swagger v1.6.1
 	github.com/swaggo/swag v1.8.12
+	github.com/weltschmerz/QA-Playground/services/shared v0.0.0
 )
 
 require (
@@ -48,3 +49,5 @@ require (
 	gopkg.in/yaml.v2 v2.4.0 // indirect
 	gopkg.in/yaml.v3 v3.0.1 // indirect
 )
+
+replace github.com/weltschmerz/QA-Playground/services/shared => ../shared
@@ -13,6 +13,7 @@ import (
 	ginSwagger "github.com/swaggo/gin-swagger"
 
 	_ "github.com/weltschmerz/QA-Playground/services/adapter-b/docs"
+	"github.com/weltschmerz/QA-Playground/services/shared/limits"
 )
 
 // @title AI Adapter B API
@@ -51,6 +52,8 @@ type ChatRequest struct {
 	Model       string        `json:"model" example:"adapter-b"`
 	Temperature float32       `json:"temperature,omitempty" example:"0.7"`
 	MaxTokens   int           `json:"max_tokens,omitempty" example:"150"`
+	TopP        float32       `json:"top_p,omitempty" example:"0.9"`
+	Stop        []string      `json:"stop,omitempty"`
 	Stream      bool          `json:"stream,omitempty" example:"false"`
 }
 
@@ -231,7 +234,7 @@ func complete(c *gin.Context) {
 	}
 
 	// Generate more sophisticated completion
-	completion := generateAdvancedCompletion(req.Prompt, req.SystemPrompt)
+	completion, finishReason := limits.Apply(generateAdvancedCompletion(req.Prompt, req.SystemPrompt), req.MaxTokens, 3, req.StopSequences)
 
 	requestID := fmt.Sprintf("req-b-%d-%d", time.Now().Unix(), rand.Intn(10000))
 
@@ -249,7 +252,7 @@ func complete(c *gin.Context) {
 			ProcessingTime:  fmt.Sprintf("%.0fms", time.Since(start).Seconds()*1000),
 			ModelVersion:    "2.1.0",
 			ContentFiltered: false,
-			FinishReason:    "stop",
+			FinishReason:    finishReason,
 			Confidence:      0.85 + rand.Float32()*0.14, // 0.85-0.99
 			Tags:            []string{"completion", "text-generation"},
 			CustomData:      map[string]interface{}{"node_id": "node-2"},
@@ -280,7 +283,7 @@ func chatCompletion(c *gin.Context) {
 	time.Sleep(time.Duration(120+rand.Intn(200)) * time.Millisecond)
 
 	lastMessage := req.Messages[len(req.Messages)-1]
-	assistantResponse := generateChatResponse(lastMessage.Content)
+	assistantResponse, finishReason := limits.Apply(generateChatResponse(lastMessage.Content), req.MaxTokens, 3, req.Stop)
 
 	response := ChatResponse{
 		ID:      fmt.Sprintf("chatcmpl-%d", time.Now().Unix()),
@@ -294,7 +297,7 @@ func chatCompletion(c *gin.Context) {
 					Role:    "assistant",
 					Content: assistantResponse,
 				},
-				FinishReason: "stop",
+				FinishReason: finishReason,
 			},
 		},
 		Usage: UsageInfo{
@@ -329,25 +332,16 @@ func streamComplete(c *gin.Context) {
 	c.Header("Connection", "keep-alive")
 
 	streamID := fmt.Sprintf("stream-%d", time.Now().Unix())
-	completion := generateAdvancedCompletion(req.Prompt, req.SystemPrompt)
+	completion, finishReason := limits.Apply(generateAdvancedCompletion(req.Prompt, req.SystemPrompt), req.MaxTokens, 3, req.StopSequences)
 
-	// Stream the response word by word
-	words := strings.Fields(completion)
-	for i, word := range words {
-		streamResponse := StreamingResponse{
+	write := func(delta map[string]interface{}, finishReason *string) {
+		data, _ := json.Marshal(StreamingResponse{
 			ID:      streamID,
 			Object:  "text_completion.chunk",
 			Created: time.Now().Unix(),
 			Model:   "adapter-b",
-			Choices: []StreamChoice{
-				{Index: 0, Delta: map[string]interface{}{"content": word + " "}},
-			},
-		}
-		if i == len(words)-1 {
-			reason := "stop"
-			streamResponse.Choices[0].FinishReason = &reason
-		}
-		data, _ := json.Marshal(streamResponse)
+			Choices: []StreamChoice{{Index: 0, Delta: delta, FinishReason: finishReason}},
+		})
 		// Write as SSE-compatible line but with our custom content-type
 		_, _ = c.Writer.Write([]byte("data: "))
 		_, _ = c.Writer.Write(data)
@@ -355,8 +349,15 @@ func streamComplete(c *gin.Context) {
 		if f, ok := c.Writer.(http.Flusher); ok {
 			f.Flush()
 		}
+	}
+
+	// Stream the response word by word
+	for _, word := range strings.Fields(completion) {
+		write(map[string]interface{}{"content": word + " "}, nil)
 		time.Sleep(50 * time.Millisecond)
 	}
+	// the final chunk is sent even when a stop sequence left no words
+	write(map[string]interface{}{}, &finishReason)
 }
 
 // @Summary Analyze sentiment
@@ -0,0 +1,3 @@
+module github.com/weltschmerz/QA-Playground/services/shared
+
+go 1.23.0
@@ -0,0 +1,24 @@
+// Package limits applies the generation limits shared by the mock adapters.
+package limits
+
+import "strings"
+
+// Apply cuts a completion at the earliest stop sequence or after
+// maxTokens tokens of charsPerToken characters each, and reports the finish
+// reason ("stop" or "length").
+func Apply(text string, maxTokens, charsPerToken int, stops []string) (string, string) {
+	cut := -1
+	for _, s := range stops {
+		if i := strings.Index(text, s); s != "" && i >= 0 && (cut < 0 || i < cut) {
+			cut = i
+		}
+	}
+	if cut >= 0 {
+		text = text[:cut]
+	}
+	// count runes, not bytes, so the cut never splits a UTF-8 character
+	if r := []rune(text); maxTokens > 0 && len(r) > maxTokens*charsPerToken {
+		return string(r[:maxTokens*charsPerToken]), "length"
+	}
+	return text, "stop"
+}
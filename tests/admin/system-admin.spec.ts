@@ -370,6 +370,22 @@ test.describe("@contract System Administration", () => {
       expect(response.status()).toBe(401);
     });
 
+    test("should deny admin writes to non-admin users", async ({
+      userRequest,
+    }) => {
+      const writes = [
+        userRequest.post(`${baseURL}/v1/admin/system/maintenance`, {
+          data: { enabled: true },
+        }),
+        userRequest.post(`${baseURL}/v1/admin/system/backup`, {
+          data: { backup_type: "full" },
+        }),
+      ];
+      for (const response of await Promise.all(writes)) {
+        expect(response.status()).toBe(403);
+      }
+    });
+
     test("should rate limit admin API calls", async ({ request }) => {
       // Make many rapid admin requests
       const promises = Array.from({ length: 15 }, () =>
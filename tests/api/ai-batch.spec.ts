@@ -0,0 +1,113 @@
+import { expect } from "@playwright/test";
+import { test } from "../fixtures/api-fixtures";
+import { generateTestUser, registerAndLoginUser } from "../utils/test-helpers";
+
+async function pollJob(
+  svcRequest: any,
+  apiBase: string,
+  jobId: string,
+  done: (job: any) => boolean
+) {
+  for (let i = 0; i < 40; i++) {
+    const res = await svcRequest.get(`${apiBase}/v1/ai/jobs/${jobId}`);
+    expect(res.status()).toBe(200);
+    const job = await res.json();
+    if (done(job)) return job;
+    await new Promise((r) => setTimeout(r, 250));
+  }
+  throw new Error(`job ${jobId} did not reach expected state`);
+}
+
+test.describe("AI batch jobs", () => {
+  test("runs every request and reports results", async ({
+    svcRequest,
+    apiBase,
+  }) => {
+    const res = await svcRequest.post(`${apiBase}/v1/ai/batch`, {
+      data: {
+        model: "adapter-a",
+        requests: [
+          { id: "first", prompt: "one" },
+          { id: "second", prompt: "two", model: "adapter-b" },
+        ],
+      },
+    });
+    expect(res.status()).toBe(202);
+    const accepted = await res.json();
+    expect(accepted.total_requests).toBe(2);
+
+    const job = await pollJob(svcRequest, apiBase, accepted.job_id, (j) =>
+      ["completed", "failed"].includes(j.status)
+    );
+    expect(job.progress.total).toBe(2);
+    expect(job.progress.completed).toBe(2);
+    expect(job.results.map((r: any) => r.id)).toEqual(["first", "second"]);
+    expect(job.results[1].model).toBe("adapter-b");
+    for (const r of job.results) {
+      expect(["success", "failed"]).toContain(r.status);
+    }
+  });
+
+  test("unknown job is 404", async ({ svcRequest, apiBase }) => {
+    const res = await svcRequest.get(`${apiBase}/v1/ai/jobs/job-does-not-exist`);
+    expect(res.status()).toBe(404);
+  });
+
+  test("jobs are only visible to their submitter and admins", async ({
+    userRequest,
+    svcRequest,
+    request,
+    apiBase,
+  }) => {
+    const res = await userRequest.post(`${apiBase}/v1/ai/batch`, {
+      data: { model: "adapter-a", inputs: ["mine"] },
+    });
+    expect(res.status()).toBe(202);
+    const { job_id } = await res.json();
+
+    expect((await userRequest.get(`${apiBase}/v1/ai/jobs/${job_id}`)).status()).toBe(200);
+    expect((await svcRequest.get(`${apiBase}/v1/ai/jobs/${job_id}`)).status()).toBe(200);
+
+    const { token } = await registerAndLoginUser(request, generateTestUser("other"), apiBase);
+    const headers = { Authorization: `Bearer ${token}` };
+    expect((await request.get(`${apiBase}/v1/ai/jobs/${job_id}`, { headers })).status()).toBe(404);
+    expect((await request.delete(`${apiBase}/v1/ai/jobs/${job_id}`, { headers })).status()).toBe(404);
+  });
+
+  test("rejects empty prompts and unknown models", async ({
+    svcRequest,
+    apiBase,
+  }) => {
+    const empty = await svcRequest.post(`${apiBase}/v1/ai/batch`, {
+      data: { requests: [{ prompt: "" }] },
+    });
+    expect(empty.status()).toBe(400);
+    const unknown = await svcRequest.post(`${apiBase}/v1/ai/batch`, {
+      data: { requests: [{ prompt: "hi", model: "nope" }] },
+    });
+    expect(unknown.status()).toBe(400);
+  });
+
+  test("cancelling a job stops pending items", async ({
+    svcRequest,
+    apiBase,
+  }) => {
+    const inputs = Array.from({ length: 30 }, (_, i) => `prompt ${i} `.repeat(40));
+    const res = await svcRequest.post(`${apiBase}/v1/ai/batch`, {
+      data: { model: "adapter-a", inputs },
+    });
+    expect(res.status()).toBe(202);
+    const { job_id } = await res.json();
+
+    const cancel = await svcRequest.delete(`${apiBase}/v1/ai/jobs/${job_id}`);
+    expect(cancel.status()).toBe(200);
+    const cancelled = await cancel.json();
+    expect(cancelled.status).toBe("cancelled");
+    expect(
+      cancelled.results.some((r: any) => r.status === "cancelled")
+    ).toBe(true);
+
+    const again = await svcRequest.delete(`${apiBase}/v1/ai/jobs/${job_id}`);
+    expect(again.status()).toBe(409);
+  });
+});
@@ -0,0 +1,116 @@
+import { expect } from "@playwright/test";
+import { test } from "../fixtures/api-fixtures";
+
+// Unique prompts keep parallel tests from sharing cache entries.
+const uniquePrompt = (label: string) =>
+  `cache ${label} ${Date.now()}-${Math.random().toString(36).slice(2)}`;
+
+test.describe("Completion cache", () => {
+  test("serves repeated temperature-0 requests from the cache", async ({
+    svcRequest,
+    apiBase,
+  }) => {
+    const data = { prompt: uniquePrompt("hit"), model: "adapter-a", temperature: 0 };
+    const first = await svcRequest.post(`${apiBase}/v1/ai/complete`, { data });
+    expect(first.status()).toBe(200);
+    expect(first.headers()["x-cache"]).toBe("MISS");
+
+    const second = await svcRequest.post(`${apiBase}/v1/ai/complete`, { data });
+    expect(second.status()).toBe(200);
+    expect(second.headers()["x-cache"]).toBe("HIT");
+    const a = await first.json();
+    const b = await second.json();
+    expect(b.completion).toBe(a.completion);
+    expect(b.usage).toEqual(a.usage);
+    expect(b.traceId).not.toBe(a.traceId);
+  });
+
+  test("does not cache non-deterministic requests", async ({
+    svcRequest,
+    apiBase,
+  }) => {
+    const data = { prompt: uniquePrompt("warm"), model: "adapter-a", temperature: 0.7 };
+    await svcRequest.post(`${apiBase}/v1/ai/complete`, { data });
+    const res = await svcRequest.post(`${apiBase}/v1/ai/complete`, { data });
+    expect(res.headers()["x-cache"]).toBe("BYPASS");
+  });
+
+  test("Cache-Control: no-cache bypasses the lookup", async ({
+    svcRequest,
+    apiBase,
+  }) => {
+    const data = { prompt: uniquePrompt("bypass"), model: "adapter-a", temperature: 0 };
+    await svcRequest.post(`${apiBase}/v1/ai/complete`, { data });
+    const res = await svcRequest.post(`${apiBase}/v1/ai/complete`, {
+      data,
+      headers: { "Cache-Control": "no-cache" },
+    });
+    expect(res.status()).toBe(200);
+    expect(res.headers()["x-cache"]).toBe("BYPASS");
+  });
+
+  test("failure-injection requests skip the cache", async ({
+    svcRequest,
+    apiBase,
+  }) => {
+    const data = { prompt: uniquePrompt("fail"), model: "adapter-a", temperature: 0 };
+    await svcRequest.post(`${apiBase}/v1/ai/complete`, { data });
+    const res = await svcRequest.post(`${apiBase}/v1/ai/complete`, {
+      data,
+      headers: { "x-test-fail": "adapter-a" },
+    });
+    expect(res.headers()["x-cache"]).toBe("BYPASS");
+  });
+
+  test("admins can inspect and delete cache entries", async ({
+    svcRequest,
+    apiBase,
+  }) => {
+    const prompt = uniquePrompt("admin");
+    const data = { prompt, model: "adapter-a", temperature: 0 };
+    await svcRequest.post(`${apiBase}/v1/ai/complete`, { data });
+    await svcRequest.post(`${apiBase}/v1/ai/complete`, { data });
+
+    const list = await svcRequest.get(`${apiBase}/v1/admin/cache?model=adapter-a`);
+    expect(list.status()).toBe(200);
+    const body = await list.json();
+    expect(body.enabled).toBe(true);
+    expect(body.ttl_seconds).toBeGreaterThan(0);
+    const entry = body.entries.find((e: any) => e.prompt === prompt);
+    expect(entry).toBeTruthy();
+    expect(entry.hits).toBe(1);
+    expect(entry.expires_at > entry.created_at).toBe(true);
+
+    const del = await svcRequest.delete(`${apiBase}/v1/admin/cache/${entry.key}`);
+    expect(del.status()).toBe(200);
+    expect((await del.json()).purged).toBe(1);
+    const again = await svcRequest.delete(`${apiBase}/v1/admin/cache/${entry.key}`);
+    expect(again.status()).toBe(404);
+
+    const res = await svcRequest.post(`${apiBase}/v1/ai/complete`, { data });
+    expect(res.headers()["x-cache"]).toBe("MISS");
+  });
+
+  test("exposes hit and miss counters", async ({ svcRequest, request, apiBase }) => {
+    const data = { prompt: uniquePrompt("metrics"), model: "adapter-a", temperature: 0 };
+    await svcRequest.post(`${apiBase}/v1/ai/complete`, { data });
+    await svcRequest.post(`${apiBase}/v1/ai/complete`, { data });
+    const metrics = await (await request.get(`${apiBase}/metrics`)).text();
+    expect(metrics).toMatch(/gateway_completion_cache_requests_total\{result="hit"\} [1-9]/);
+    expect(metrics).toMatch(/gateway_completion_cache_requests_total\{result="miss"\} [1-9]/);
+    expect(metrics).toContain("gateway_completion_cache_entries");
+  });
+
+  test("non-admins cannot read or purge the cache", async ({
+    userRequest,
+    apiBase,
+  }) => {
+    for (const res of [
+      await userRequest.get(`${apiBase}/v1/admin/cache`),
+      await userRequest.delete(`${apiBase}/v1/admin/cache`),
+      await userRequest.delete(`${apiBase}/v1/admin/cache/some-key`),
+    ]) {
+      expect(res.status()).toBe(403);
+    }
+  });
+});
@@ -0,0 +1,80 @@
+import { expect } from "@playwright/test";
+import { test } from "../fixtures/api-fixtures";
+
+test.describe("AI model fallback", () => {
+  let dead: string;
+
+  test.beforeEach(async ({ svcRequest, apiBase }) => {
+    dead = `adapter-down-${Date.now()}-${Math.floor(Math.random() * 1000)}`;
+    const put = await svcRequest.put(`${apiBase}/v1/admin/models/${dead}`, {
+      data: {
+        urls: ["http://127.0.0.1:9"],
+        resilience: { max_attempts: 1, breaker_threshold: 100 },
+      },
+    });
+    expect(put.status()).toBe(201);
+  });
+
+  test.afterEach(async ({ svcRequest, apiBase }) => {
+    await svcRequest.delete(`${apiBase}/v1/admin/models/${dead}`);
+  });
+
+  test("falls back to the next model and reports attempts", async ({
+    svcRequest,
+    apiBase,
+  }) => {
+    const res = await svcRequest.post(`${apiBase}/v1/ai/complete`, {
+      data: { prompt: "Hello", model: dead, fallback: ["adapter-a"] },
+    });
+    expect(res.status()).toBe(200);
+    const body = await res.json();
+    expect(body.model).toBe("adapter-a");
+    expect(body.requested_model).toBe(dead);
+    expect(body.fallback_used).toBe(true);
+    expect(body.attempts).toHaveLength(2);
+    expect(body.attempts[0]).toMatchObject({ model: dead, outcome: "failed" });
+    expect(body.attempts[1]).toMatchObject({
+      model: "adapter-a",
+      outcome: "success",
+    });
+  });
+
+  test("uses the registry fallback list", async ({ svcRequest, apiBase }) => {
+    const cfg = await svcRequest.post(
+      `${apiBase}/v1/ai/models/${dead}/configure`,
+      { data: { fallback: ["adapter-b"] } }
+    );
+    expect(cfg.status()).toBe(200);
+    const res = await svcRequest.post(`${apiBase}/v1/ai/complete`, {
+      data: { prompt: "Hello", model: dead },
+    });
+    expect(res.status()).toBe(200);
+    expect((await res.json()).model).toBe("adapter-b");
+  });
+
+  test("empty fallback list surfaces the failure", async ({
+    svcRequest,
+    apiBase,
+  }) => {
+    const res = await svcRequest.post(`${apiBase}/v1/ai/complete`, {
+      data: { prompt: "Hello", model: dead, fallback: [] },
+    });
+    expect(res.status()).toBe(502);
+    const body = await res.json();
+    expect(body.attempts).toHaveLength(1);
+    expect(body.attempts[0].model).toBe(dead);
+  });
+
+  test("plain completions report a single successful attempt", async ({
+    svcRequest,
+    apiBase,
+  }) => {
+    const res = await svcRequest.post(`${apiBase}/v1/ai/complete`, {
+      data: { prompt: "Hello", model: "adapter-a" },
+    });
+    expect(res.status()).toBe(200);
+    const body = await res.json();
+    expect(body.fallback_used).toBe(false);
+    expect(body.attempts).toEqual([{ model: "adapter-a", outcome: "success" }]);
+  });
+});
@@ -0,0 +1,86 @@
+import { expect } from "@playwright/test";
+import { test } from "../fixtures/api-fixtures";
+
+test.describe("AI model registry", () => {
+  test("lists registered models with limits", async ({
+    svcRequest,
+    apiBase,
+  }) => {
+    const res = await svcRequest.get(`${apiBase}/v1/ai/models`);
+    expect(res.status()).toBe(200);
+    const body = await res.json();
+    const names = body.models.map((m: any) => m.name);
+    expect(names).toEqual(expect.arrayContaining(["adapter-a", "adapter-b"]));
+    const a = body.models.find((m: any) => m.name === "adapter-a");
+    expect(a.max_tokens).toBe(4096);
+  });
+
+  test("unknown model status is 404", async ({ svcRequest, apiBase }) => {
+    const res = await svcRequest.get(
+      `${apiBase}/v1/ai/models/no-such-model/status`
+    );
+    expect(res.status()).toBe(404);
+  });
+
+  test("model registered via admin API serves completions", async ({
+    svcRequest,
+    apiBase,
+  }) => {
+    const reg = await svcRequest.get(`${apiBase}/v1/admin/models`);
+    expect(reg.status()).toBe(200);
+    const adapterA = (await reg.json()).models.find(
+      (m: any) => m.name === "adapter-a"
+    );
+    const name = `adapter-mirror-${Date.now()}`;
+
+    const put = await svcRequest.put(`${apiBase}/v1/admin/models/${name}`, {
+      data: { urls: adapterA.urls, max_tokens: 1024, status: "active" },
+    });
+    expect(put.status()).toBe(201);
+
+    const list = await (await svcRequest.get(`${apiBase}/v1/ai/models`)).json();
+    expect(list.models.map((m: any) => m.name)).toContain(name);
+
+    const res = await svcRequest.post(`${apiBase}/v1/ai/complete`, {
+      data: { prompt: "Hello mirror", model: name },
+    });
+    expect(res.status()).toBe(200);
+
+    const disable = await svcRequest.post(
+      `${apiBase}/v1/ai/models/${name}/configure`,
+      { data: { status: "disabled" } }
+    );
+    expect(disable.status()).toBe(200);
+    const blocked = await svcRequest.post(`${apiBase}/v1/ai/complete`, {
+      data: { prompt: "Hello mirror", model: name },
+    });
+    expect(blocked.status()).toBe(503);
+
+    const del = await svcRequest.delete(`${apiBase}/v1/admin/models/${name}`);
+    expect(del.status()).toBe(204);
+  });
+
+  test("only admins change the registry", async ({ userRequest, apiBase }) => {
+    const entry = { urls: ["http://example.com"], status: "active" };
+    const put = await userRequest.put(`${apiBase}/v1/admin/models/adapter-a`, {
+      data: entry,
+    });
+    expect(put.status()).toBe(403);
+    const del = await userRequest.delete(`${apiBase}/v1/admin/models/adapter-a`);
+    expect(del.status()).toBe(403);
+    const reload = await userRequest.post(`${apiBase}/v1/admin/models/reload`);
+    expect(reload.status()).toBe(403);
+    const configure = await userRequest.post(
+      `${apiBase}/v1/ai/models/adapter-a/configure`,
+      { data: entry }
+    );
+    expect(configure.status()).toBe(403);
+  });
+
+  test("rejects invalid registry entries", async ({ svcRequest, apiBase }) => {
+    const res = await svcRequest.put(`${apiBase}/v1/admin/models/broken`, {
+      data: { urls: ["not-a-url"] },
+    });
+    expect(res.status()).toBe(400);
+  });
+});
@@ -0,0 +1,111 @@
+import { expect } from "@playwright/test";
+import { test } from "../fixtures/api-fixtures";
+
+test.describe("AI generation parameters", () => {
+  test("forwards parameters and returns adapter usage", async ({
+    svcRequest,
+    apiBase,
+  }) => {
+    const res = await svcRequest.post(`${apiBase}/v1/ai/complete`, {
+      data: {
+        prompt: "Describe the playground",
+        model: "adapter-a",
+        temperature: 0,
+        top_p: 0.5,
+        max_tokens: 4,
+        system_prompt: "Be brief",
+      },
+    });
+    expect(res.status()).toBe(200);
+    const body = await res.json();
+    expect(body.finish_reason).toBe("length");
+    expect(body.completion.length).toBeLessThanOrEqual(16);
+    expect(body.usage.completion_tokens).toBeLessThanOrEqual(4);
+    expect(body.request_id).toMatch(/^req-/);
+    expect(body.traceId).toBeTruthy();
+  });
+
+  test("max_tokens cuts multi-byte text on character boundaries", async ({
+    svcRequest,
+    apiBase,
+  }) => {
+    for (const model of ["adapter-a", "adapter-b"]) {
+      const res = await svcRequest.post(`${apiBase}/v1/ai/complete`, {
+        data: { prompt: "žluťoučký kůň úpěl ďábelské ódy", model, max_tokens: 3 },
+      });
+      expect(res.status()).toBe(200);
+      const body = await res.json();
+      expect(body.finish_reason).toBe("length");
+      expect(body.completion).not.toContain("\uFFFD");
+    }
+  });
+
+  test("stop sequences end the completion", async ({
+    svcRequest,
+    apiBase,
+  }) => {
+    const res = await svcRequest.post(`${apiBase}/v1/ai/complete`, {
+      data: {
+        prompt: "alpha STOP beta",
+        model: "adapter-b",
+        stop_sequences: ["STOP"],
+      },
+    });
+    expect(res.status()).toBe(200);
+    const body = await res.json();
+    expect(body.completion).not.toContain("beta");
+    expect(body.finish_reason).toBe("stop");
+  });
+
+  test("a stream emptied by a stop sequence still finishes", async ({
+    svcRequest,
+    apiBase,
+  }) => {
+    for (const [model, stop] of [
+      ["adapter-a", "A:"],
+      ["adapter-b", "B-Advanced"],
+    ]) {
+      const res = await svcRequest.post(`${apiBase}/v1/ai/complete`, {
+        data: { prompt: "hi", model, stream: true, stop_sequences: [stop] },
+      });
+      expect(res.status()).toBe(200);
+      const usage = (await res.text())
+        .split("\n\n")
+        .find((block) => block.startsWith("event: usage"));
+      expect(usage).toBeTruthy();
+      const summary = JSON.parse(usage!.split("data:")[1]);
+      expect(summary.finish_reason).toBe("stop");
+      expect(summary.completion_tokens).toBe(0);
+    }
+  });
+
+  test("rejects max_tokens above the model limit", async ({
+    svcRequest,
+    apiBase,
+  }) => {
+    const over = await svcRequest.post(`${apiBase}/v1/ai/complete`, {
+      data: { prompt: "hi", model: "adapter-a", max_tokens: 5000 },
+    });
+    expect(over.status()).toBe(400);
+    expect((await over.json()).error).toContain("4096");
+
+    const ok = await svcRequest.post(`${apiBase}/v1/ai/complete`, {
+      data: { prompt: "hi", model: "adapter-b", max_tokens: 5000 },
+    });
+    expect(ok.status()).toBe(200);
+  });
+
+  for (const [field, value] of [
+    ["temperature", 2.5],
+    ["top_p", 0],
+    ["max_tokens", -1],
+    ["stop_sequences", ["a", "b", "c", "d", "e"]],
+  ] as const) {
+    test(`rejects invalid ${field}`, async ({ svcRequest, apiBase }) => {
+      const res = await svcRequest.post(`${apiBase}/v1/ai/complete`, {
+        data: { prompt: "hi", model: "adapter-a", [field]: value },
+      });
+      expect(res.status()).toBe(400);
+    });
+  }
+});
@@ -0,0 +1,63 @@
+import { expect } from "@playwright/test";
+import { test } from "../fixtures/api-fixtures";
+
+test.describe("AI adapter circuit breaker", () => {
+  test("opens after consecutive failures and short-circuits with 503", async ({
+    svcRequest,
+    apiBase,
+    request,
+  }) => {
+    const name = `adapter-dead-${Date.now()}`;
+    const put = await svcRequest.put(`${apiBase}/v1/admin/models/${name}`, {
+      data: {
+        urls: ["http://127.0.0.1:9"],
+        resilience: {
+          max_attempts: 1,
+          breaker_threshold: 2,
+          breaker_cooldown_ms: 60000,
+        },
+      },
+    });
+    expect(put.status()).toBe(201);
+
+    for (let i = 0; i < 2; i++) {
+      const res = await svcRequest.post(`${apiBase}/v1/ai/complete`, {
+        data: { prompt: "hello", model: name },
+      });
+      expect(res.status()).toBe(502);
+    }
+
+    const open = await svcRequest.post(`${apiBase}/v1/ai/complete`, {
+      data: { prompt: "hello", model: name },
+    });
+    expect(open.status()).toBe(503);
+    expect(Number(open.headers()["retry-after"])).toBeGreaterThan(0);
+    expect((await open.json()).circuit_breaker.state).toBe("open");
+
+    const status = await svcRequest.get(
+      `${apiBase}/v1/ai/models/${name}/status`
+    );
+    const body = await status.json();
+    expect(body.circuit_breaker.state).toBe("open");
+    expect(body.circuit_breaker.consecutive_failures).toBe(2);
+    expect(body.resilience.max_attempts).toBe(1);
+
+    const metrics = await (await request.get(`${apiBase}/metrics`)).text();
+    expect(metrics).toContain(`gateway_circuit_breaker_state{model="${name}"} 2`);
+
+    const del = await svcRequest.delete(`${apiBase}/v1/admin/models/${name}`);
+    expect(del.status()).toBe(204);
+    const after = await (await request.get(`${apiBase}/metrics`)).text();
+    expect(after).not.toContain(`gateway_circuit_breaker_state{model="${name}"}`);
+  });
+
+  test("rejects invalid resilience policies", async ({
+    svcRequest,
+    apiBase,
+  }) => {
+    const res = await svcRequest.put(`${apiBase}/v1/admin/models/bad-policy`, {
+      data: { urls: ["http://localhost:1"], resilience: { retry_on: [200] } },
+    });
+    expect(res.status()).toBe(400);
+  });
+});
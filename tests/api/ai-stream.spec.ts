@@ -0,0 +1,66 @@
+import { expect } from "@playwright/test";
+import { test } from "../fixtures/api-fixtures";
+
+function parseEvents(raw: string) {
+  return raw
+    .split("\n\n")
+    .filter((block) => block.trim() !== "")
+    .map((block) => {
+      const ev: { event?: string; data: string } = { data: "" };
+      for (const line of block.split("\n")) {
+        if (line.startsWith("event:")) ev.event = line.slice(6).trim();
+        if (line.startsWith("data:")) ev.data += line.slice(5).trim();
+      }
+      return ev;
+    });
+}
+
+test.describe("AI streaming completions", () => {
+  for (const model of ["adapter-a", "adapter-b"]) {
+    test(`streams ${model} chunks as server-sent events`, async ({
+      svcRequest,
+      apiBase,
+    }) => {
+      const res = await svcRequest.post(`${apiBase}/v1/ai/complete`, {
+        data: { prompt: "Stream me a reply", model, stream: true },
+      });
+      expect(res.status()).toBe(200);
+      expect(res.headers()["content-type"]).toContain("text/event-stream");
+
+      const events = parseEvents(await res.text());
+      expect(events.at(-1)?.data).toBe("[DONE]");
+      const usage = events.find((e) => e.event === "usage");
+      expect(usage).toBeTruthy();
+      const u = JSON.parse(usage!.data);
+      expect(u.model).toBe(model);
+      expect(u.total_tokens).toBeGreaterThan(0);
+
+      const text = events
+        .filter((e) => !e.event && e.data !== "[DONE]")
+        .map((e) => JSON.parse(e.data).choices[0].delta.content)
+        .join("");
+      expect(text.length).toBeGreaterThan(0);
+    });
+  }
+
+  test("dedicated stream route matches stream flag", async ({
+    svcRequest,
+    apiBase,
+  }) => {
+    const res = await svcRequest.post(`${apiBase}/v1/ai/complete/stream`, {
+      data: { prompt: "Hello", model: "adapter-a" },
+    });
+    expect(res.status()).toBe(200);
+    expect(res.headers()["content-type"]).toContain("text/event-stream");
+  });
+
+  test("unknown model is rejected before streaming", async ({
+    svcRequest,
+    apiBase,
+  }) => {
+    const res = await svcRequest.post(`${apiBase}/v1/ai/complete/stream`, {
+      data: { prompt: "Hello", model: "nope" },
+    });
+    expect(res.status()).toBe(400);
+  });
+});
@@ -0,0 +1,117 @@
+import { expect } from "@playwright/test";
+import { test } from "../fixtures/api-fixtures";
+import { generateTestUser, registerAndLoginUser } from "../utils/test-helpers";
+
+// The gateway POSTs to its own /healthz, which only accepts GET: a 4xx the
+// deliverer must not retry.
+const CALLBACK = "http://localhost:8080/healthz";
+
+async function pollDeliveries(
+  svcRequest: any,
+  apiBase: string,
+  jobId: string,
+  done: (d: any) => boolean
+) {
+  for (let i = 0; i < 40; i++) {
+    const res = await svcRequest.get(`${apiBase}/v1/ai/jobs/${jobId}/deliveries`);
+    expect(res.status()).toBe(200);
+    const body = await res.json();
+    if (done(body)) return body;
+    await new Promise((r) => setTimeout(r, 250));
+  }
+  throw new Error(`webhook for ${jobId} did not settle`);
+}
+
+test.describe("AI batch webhooks", () => {
+  test("records a non-retryable delivery failure and allows redelivery", async ({
+    svcRequest,
+    apiBase,
+  }) => {
+    const res = await svcRequest.post(`${apiBase}/v1/ai/batch`, {
+      data: { inputs: ["webhook me"], callback_url: CALLBACK },
+    });
+    expect(res.status()).toBe(202);
+    const { job_id } = await res.json();
+
+    const log = await pollDeliveries(svcRequest, apiBase, job_id, (d) =>
+      ["delivered", "failed"].includes(d.state)
+    );
+    expect(log.callback_url).toBe(CALLBACK);
+    expect(log.state).toBe("failed");
+    expect(log.deliveries).toHaveLength(1);
+    expect(log.deliveries[0].status_code).toBeGreaterThanOrEqual(400);
+    expect(log.deliveries[0].status_code).toBeLessThan(500);
+
+    const job = await (
+      await svcRequest.get(`${apiBase}/v1/ai/jobs/${job_id}`)
+    ).json();
+    expect(job.webhook_state).toBe("failed");
+
+    const again = await svcRequest.post(
+      `${apiBase}/v1/ai/jobs/${job_id}/redeliver`
+    );
+    expect(again.status()).toBe(202);
+    const { delivery_id } = await again.json();
+    const after = await pollDeliveries(svcRequest, apiBase, job_id, (d) =>
+      d.deliveries.some((a: any) => a.delivery_id === delivery_id)
+    );
+    const redelivered = after.deliveries.find(
+      (a: any) => a.delivery_id === delivery_id
+    );
+    expect(redelivered.redelivery).toBe(true);
+  });
+
+  test("refuses redelivery while a delivery is still retrying", async ({
+    svcRequest,
+    apiBase,
+  }) => {
+    // nothing listens on the discard port, so every attempt is retried
+    const res = await svcRequest.post(`${apiBase}/v1/ai/batch`, {
+      data: { inputs: ["retry me"], callback_url: "http://127.0.0.1:9/hook" },
+    });
+    const { job_id } = await res.json();
+    const log = await pollDeliveries(svcRequest, apiBase, job_id, (d) => d.deliveries.length > 0);
+    expect(log.state).toBe("pending");
+
+    const again = await svcRequest.post(`${apiBase}/v1/ai/jobs/${job_id}/redeliver`);
+    expect(again.status()).toBe(409);
+  });
+
+  test("deliveries of another caller's job are 404", async ({
+    userRequest,
+    request,
+    apiBase,
+  }) => {
+    const res = await userRequest.post(`${apiBase}/v1/ai/batch`, {
+      data: { inputs: ["private"], callback_url: CALLBACK },
+    });
+    const { job_id } = await res.json();
+    const { token } = await registerAndLoginUser(request, generateTestUser("other"), apiBase);
+    const headers = { Authorization: `Bearer ${token}` };
+    const url = `${apiBase}/v1/ai/jobs/${job_id}`;
+    expect((await request.get(`${url}/deliveries`, { headers })).status()).toBe(404);
+    expect((await request.post(`${url}/redeliver`, { headers })).status()).toBe(404);
+    expect((await userRequest.get(`${url}/deliveries`)).status()).toBe(200);
+  });
+
+  test("rejects invalid callback URLs", async ({ svcRequest, apiBase }) => {
+    const res = await svcRequest.post(`${apiBase}/v1/ai/batch`, {
+      data: { inputs: ["hi"], callback_url: "ftp://example.com/hook" },
+    });
+    expect(res.status()).toBe(400);
+  });
+
+  test("redeliver without a callback is 400", async ({
+    svcRequest,
+    apiBase,
+  }) => {
+    const res = await svcRequest.post(`${apiBase}/v1/ai/batch`, {
+      data: { inputs: ["no hook"] },
+    });
+    const { job_id } = await res.json();
+    const again = await svcRequest.post(
+      `${apiBase}/v1/ai/jobs/${job_id}/redeliver`
+    );
+    expect(again.status()).toBe(400);
+  });
+});
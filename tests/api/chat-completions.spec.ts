@@ -0,0 +1,114 @@
+import { expect } from "@playwright/test";
+import { test } from "../fixtures/api-fixtures";
+
+const messages = [
+  { role: "system", content: "You are terse" },
+  { role: "user", content: "Say hello" },
+];
+
+test.describe("OpenAI-compatible chat completions", () => {
+  for (const model of ["adapter-a", "adapter-b"]) {
+    test(`returns a chat.completion for ${model}`, async ({
+      svcRequest,
+      apiBase,
+    }) => {
+      const res = await svcRequest.post(`${apiBase}/v1/chat/completions`, {
+        data: { model, messages },
+      });
+      expect(res.status()).toBe(200);
+      const body = await res.json();
+      expect(body.object).toBe("chat.completion");
+      expect(body.id).toMatch(/^chatcmpl-/);
+      expect(body.model).toBe(model);
+      expect(body.choices[0].message.role).toBe("assistant");
+      expect(body.choices[0].message.content.length).toBeGreaterThan(0);
+      expect(body.choices[0].finish_reason).toBeTruthy();
+      expect(body.usage).toHaveProperty("total_tokens");
+    });
+  }
+
+  test("adapter-b applies stop and max_tokens natively", async ({
+    svcRequest,
+    apiBase,
+  }) => {
+    const stopped = await svcRequest.post(`${apiBase}/v1/chat/completions`, {
+      data: {
+        model: "adapter-b",
+        messages: [{ role: "user", content: "zebra crossing" }],
+        stop: "zebra",
+      },
+    });
+    expect(stopped.status()).toBe(200);
+    const body = await stopped.json();
+    expect(body.choices[0].message.content).not.toContain("zebra");
+    expect(body.choices[0].message.content).not.toContain("crossing");
+    expect(body.choices[0].finish_reason).toBe("stop");
+
+    const cut = await svcRequest.post(`${apiBase}/v1/chat/completions`, {
+      data: { model: "adapter-b", messages, max_tokens: 2, top_p: 0.5 },
+    });
+    expect(cut.status()).toBe(200);
+    const choice = (await cut.json()).choices[0];
+    expect(choice.finish_reason).toBe("length");
+    expect(choice.message.content.length).toBeLessThanOrEqual(6);
+  });
+
+  test("accepts the service key as a bearer token", async ({
+    request,
+    apiBase,
+  }) => {
+    const res = await request.post(`${apiBase}/v1/chat/completions`, {
+      headers: { Authorization: "Bearer service-secret" },
+      data: { model: "adapter-a", messages },
+    });
+    expect(res.status()).toBe(200);
+  });
+
+  test("streams chat.completion.chunk events", async ({
+    svcRequest,
+    apiBase,
+  }) => {
+    const res = await svcRequest.post(`${apiBase}/v1/chat/completions`, {
+      data: {
+        model: "adapter-a",
+        messages,
+        stream: true,
+        stream_options: { include_usage: true },
+      },
+    });
+    expect(res.status()).toBe(200);
+    expect(res.headers()["content-type"]).toContain("text/event-stream");
+    const events = (await res.text())
+      .split("\n\n")
+      .map((b) => b.replace(/^data:\s*/, "").trim())
+      .filter(Boolean);
+    expect(events.at(-1)).toBe("[DONE]");
+    const chunks = events.slice(0, -1).map((e) => JSON.parse(e));
+    expect(chunks[0].choices[0].delta.role).toBe("assistant");
+    for (const c of chunks) expect(c.object).toBe("chat.completion.chunk");
+    const finished = chunks.find((c) => c.choices[0]?.finish_reason);
+    expect(finished).toBeTruthy();
+    expect(chunks.at(-1).usage.total_tokens).toBeGreaterThan(0);
+  });
+
+  test("unknown model uses the OpenAI error envelope", async ({
+    svcRequest,
+    apiBase,
+  }) => {
+    const res = await svcRequest.post(`${apiBase}/v1/chat/completions`, {
+      data: { model: "gpt-nope", messages },
+    });
+    expect(res.status()).toBe(404);
+    const body = await res.json();
+    expect(body.error.message).toContain("gpt-nope");
+    expect(body.error.type).toBe("invalid_request_error");
+  });
+
+  test("lists models in OpenAI format", async ({ svcRequest, apiBase }) => {
+    const res = await svcRequest.get(`${apiBase}/v1/models`);
+    expect(res.status()).toBe(200);
+    const body = await res.json();
+    expect(body.object).toBe("list");
+    expect(body.data.map((m: any) => m.id)).toContain("adapter-a");
+  });
+});
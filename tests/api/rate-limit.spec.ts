@@ -0,0 +1,143 @@
+import { expect, type APIRequestContext } from "@playwright/test";
+import { test } from "../fixtures/api-fixtures";
+
+// Limits are lowered through a per-user override so only this test's freshly
+// registered user is affected; the shared service key stays unlimited.
+async function setOverride(
+  svcRequest: APIRequestContext,
+  apiBase: string,
+  subject: string,
+  override: { rate_limit_requests: number; rate_limit_window?: number } | null
+) {
+  const res = await svcRequest.put(`${apiBase}/v1/admin/system/config`, {
+    data: { security: { rate_limit_overrides: { [subject]: override } } },
+  });
+  expect(res.status()).toBe(200);
+}
+
+async function userSubject(userRequest: APIRequestContext, apiBase: string) {
+  const res = await userRequest.get(`${apiBase}/user/profile`);
+  expect(res.status()).toBe(200);
+  return `user:${(await res.json()).ID}`;
+}
+
+test.describe("Rate limiting", () => {
+  test("returns X-RateLimit headers on limited routes", async ({
+    userRequest,
+    apiBase,
+  }) => {
+    const res = await userRequest.get(`${apiBase}/v1/ai/models`);
+    expect(res.status()).toBe(200);
+    const h = res.headers();
+    expect(Number(h["x-ratelimit-limit"])).toBeGreaterThan(0);
+    expect(Number(h["x-ratelimit-remaining"])).toBeLessThan(
+      Number(h["x-ratelimit-limit"])
+    );
+    expect(h["x-ratelimit-reset"]).toBeDefined();
+  });
+
+  test("only admins change rate limits", async ({ userRequest, apiBase }) => {
+    const subject = await userSubject(userRequest, apiBase);
+    for (const security of [
+      { rate_limit_overrides: { [subject]: { rate_limit_requests: 1000 } } },
+      { api_key_requests: 1000 },
+    ]) {
+      const res = await userRequest.put(`${apiBase}/v1/admin/system/config`, {
+        data: { security },
+      });
+      expect(res.status()).toBe(403);
+    }
+  });
+
+  test("rejects a user over their limit with 429 and Retry-After", async ({
+    userRequest,
+    svcRequest,
+    apiBase,
+  }) => {
+    const subject = await userSubject(userRequest, apiBase);
+    await setOverride(svcRequest, apiBase, subject, {
+      rate_limit_requests: 3,
+      rate_limit_window: 60,
+    });
+    try {
+      const statuses: number[] = [];
+      let limited;
+      for (let i = 0; i < 5; i++) {
+        const res = await userRequest.get(`${apiBase}/v1/ai/models`);
+        statuses.push(res.status());
+        if (res.status() === 429) limited = res;
+      }
+      expect(statuses).toEqual([200, 200, 200, 429, 429]);
+      expect(Number(limited!.headers()["retry-after"])).toBeGreaterThan(0);
+      expect(limited!.headers()["x-ratelimit-limit"]).toBe("3");
+      expect(limited!.headers()["x-ratelimit-remaining"]).toBe("0");
+      const body = await limited!.json();
+      expect(body.error).toBe("rate limit exceeded");
+
+      // the budget is per caller, not per route group
+      const other = await userRequest.get(`${apiBase}/v1/workflows/`);
+      expect(other.status()).toBe(429);
+
+      // other callers are unaffected
+      const svc = await svcRequest.get(`${apiBase}/v1/ai/models`);
+      expect(svc.status()).toBe(200);
+    } finally {
+      await setOverride(svcRequest, apiBase, subject, null);
+    }
+  });
+
+  test("picks up config changes without a restart", async ({
+    userRequest,
+    svcRequest,
+    apiBase,
+  }) => {
+    const subject = await userSubject(userRequest, apiBase);
+    await setOverride(svcRequest, apiBase, subject, { rate_limit_requests: 1 });
+    try {
+      expect((await userRequest.get(`${apiBase}/v1/ai/models`)).status()).toBe(200);
+      expect((await userRequest.get(`${apiBase}/v1/ai/models`)).status()).toBe(429);
+      // 0 lifts the limit for this subject
+      await setOverride(svcRequest, apiBase, subject, { rate_limit_requests: 0 });
+      const res = await userRequest.get(`${apiBase}/v1/ai/models`);
+      expect(res.status()).toBe(200);
+      expect(res.headers()["x-ratelimit-limit"]).toBeUndefined();
+    } finally {
+      await setOverride(svcRequest, apiBase, subject, null);
+    }
+  });
+
+  test("counts rejections in Prometheus", async ({
+    userRequest,
+    svcRequest,
+    request,
+    apiBase,
+  }) => {
+    const subject = await userSubject(userRequest, apiBase);
+    await setOverride(svcRequest, apiBase, subject, { rate_limit_requests: 1 });
+    try {
+      await userRequest.get(`${apiBase}/v1/ai/models`);
+      expect((await userRequest.get(`${apiBase}/v1/ai/models`)).status()).toBe(429);
+    } finally {
+      await setOverride(svcRequest, apiBase, subject, null);
+    }
+    const metrics = await (await request.get(`${apiBase}/metrics`)).text();
+    expect(metrics).toMatch(
+      /gateway_rate_limit_rejections_total\{route="\/v1\/ai\/models",subject_type="user"\} [1-9]/
+    );
+  });
+
+  test("rejects invalid overrides", async ({ svcRequest, apiBase }) => {
+    const res = await svcRequest.put(`${apiBase}/v1/admin/system/config`, {
+      data: {
+        security: {
+          rate_limit_overrides: { "someone": { rate_limit_requests: -1 } },
+        },
+      },
+    });
+    expect(res.status()).toBe(400);
+    const body = await res.json();
+    expect(body.validation_errors).toContain(
+      "security.rate_limit_overrides.someone invalid"
+    );
+  });
+});
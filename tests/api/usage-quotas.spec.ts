@@ -0,0 +1,160 @@
+import { expect, type APIRequestContext } from "@playwright/test";
+import { test } from "../fixtures/api-fixtures";
+
+async function complete(userRequest: APIRequestContext, apiBase: string) {
+  return userRequest.post(`${apiBase}/v1/ai/complete`, {
+    data: { prompt: "Count my tokens please", model: "adapter-a" },
+  });
+}
+
+async function setQuota(
+  svcRequest: APIRequestContext,
+  apiBase: string,
+  subject: string,
+  quota: { daily_tokens?: number; monthly_tokens?: number } | null
+) {
+  const res = await svcRequest.put(`${apiBase}/v1/admin/system/config`, {
+    data: { quotas: { overrides: { [subject]: quota } } },
+  });
+  expect(res.status()).toBe(200);
+}
+
+test.describe("Token usage and quotas", () => {
+  test("records completion tokens in the caller's usage", async ({
+    userRequest,
+    apiBase,
+  }) => {
+    const res = await complete(userRequest, apiBase);
+    expect(res.status()).toBe(200);
+    const { usage } = await res.json();
+
+    const mine = await userRequest.get(`${apiBase}/v1/ai/usage`);
+    expect(mine.status()).toBe(200);
+    const body = await mine.json();
+    expect(body.subject).toMatch(/^user:/);
+    expect(body.totals.requests).toBe(1);
+    expect(body.totals.total_tokens).toBe(usage.total_tokens);
+    const a = body.by_model.find((m: any) => m.model === "adapter-a");
+    expect(a.prompt_tokens).toBe(usage.prompt_tokens);
+    expect(a.completion_tokens).toBe(usage.completion_tokens);
+    expect(body.quota.daily_used).toBe(usage.total_tokens);
+    expect(body.quota.daily_remaining).toBeNull(); // unlimited by default
+  });
+
+  test("users cannot lift their own quota", async ({
+    userRequest,
+    apiBase,
+  }) => {
+    const { subject } = await (
+      await userRequest.get(`${apiBase}/v1/ai/usage`)
+    ).json();
+    for (const quotas of [
+      { enabled: false },
+      { overrides: { [subject]: { daily_tokens: 1_000_000 } } },
+    ]) {
+      const res = await userRequest.put(`${apiBase}/v1/admin/system/config`, {
+        data: { quotas },
+      });
+      expect(res.status()).toBe(403);
+    }
+  });
+
+  test("rejects calls once the daily quota is used up", async ({
+    userRequest,
+    svcRequest,
+    apiBase,
+  }) => {
+    const { subject } = await (
+      await userRequest.get(`${apiBase}/v1/ai/usage`)
+    ).json();
+    await setQuota(svcRequest, apiBase, subject, { daily_tokens: 1 });
+    try {
+      // the first call is allowed and spends the budget
+      expect((await complete(userRequest, apiBase)).status()).toBe(200);
+
+      const res = await complete(userRequest, apiBase);
+      expect(res.status()).toBe(429);
+      expect(Number(res.headers()["retry-after"])).toBeGreaterThan(0);
+      const body = await res.json();
+      expect(body.error).toBe("token quota exceeded");
+      expect(body.quota.period).toBe("daily");
+      expect(body.quota.limit).toBe(1);
+      expect(body.quota.resets_at).toBeTruthy();
+
+      const chat = await userRequest.post(`${apiBase}/v1/chat/completions`, {
+        data: {
+          model: "adapter-a",
+          messages: [{ role: "user", content: "hi" }],
+        },
+      });
+      expect(chat.status()).toBe(429);
+      expect((await chat.json()).error.type).toBe("insufficient_quota");
+
+      const usage = await (await userRequest.get(`${apiBase}/v1/ai/usage`)).json();
+      expect(usage.quota.daily_tokens).toBe(1);
+      expect(usage.quota.daily_remaining).toBe(0);
+    } finally {
+      await setQuota(svcRequest, apiBase, subject, null);
+    }
+    expect((await complete(userRequest, apiBase)).status()).toBe(200);
+  });
+
+  test("admins can see every caller's usage", async ({
+    userRequest,
+    svcRequest,
+    apiBase,
+  }) => {
+    await complete(userRequest, apiBase);
+    const { subject } = await (
+      await userRequest.get(`${apiBase}/v1/ai/usage`)
+    ).json();
+
+    const res = await svcRequest.get(
+      `${apiBase}/v1/admin/usage?subject=${encodeURIComponent(subject)}`
+    );
+    expect(res.status()).toBe(200);
+    const body = await res.json();
+    expect(body.subjects).toHaveLength(1);
+    expect(body.subjects[0].subject).toBe(subject);
+    expect(body.subjects[0].totals.requests).toBeGreaterThanOrEqual(1);
+    expect(body.totals.total_tokens).toBe(body.subjects[0].totals.total_tokens);
+  });
+
+  test("regular users cannot see every caller's usage", async ({
+    userRequest,
+    adminRequest,
+    apiBase,
+  }) => {
+    expect((await userRequest.get(`${apiBase}/v1/admin/usage`)).status()).toBe(403);
+    expect((await adminRequest.get(`${apiBase}/v1/admin/usage`)).status()).toBe(200);
+  });
+
+  test("usage analytics report recorded requests and tokens", async ({
+    userRequest,
+    svcRequest,
+    apiBase,
+  }) => {
+    await complete(userRequest, apiBase);
+    const res = await svcRequest.get(
+      `${apiBase}/v1/analytics/usage?endpoint=/v1/ai/complete&group_by=hour`
+    );
+    expect(res.status()).toBe(200);
+    const body = await res.json();
+    expect(body.total_requests).toBeGreaterThan(0);
+    expect(body.unique_users).toBeGreaterThan(0);
+    expect(body.requests_by_endpoint[0].endpoint).toBe("/v1/ai/complete");
+    expect(body.token_usage.total_tokens).toBeGreaterThan(0);
+    expect(body.tokens_by_model["adapter-a"].requests).toBeGreaterThan(0);
+    expect(body.time_series.at(-1).total_tokens).toBeGreaterThan(0);
+  });
+
+  test("rejects invalid quota settings", async ({ svcRequest, apiBase }) => {
+    const res = await svcRequest.put(`${apiBase}/v1/admin/system/config`, {
+      data: { quotas: { daily_tokens: -5 } },
+    });
+    expect(res.status()).toBe(400);
+    expect((await res.json()).validation_errors).toContain(
+      "quotas.daily_tokens invalid"
+    );
+  });
+});
@@ -0,0 +1,121 @@
+import { expect, type APIRequestContext } from "@playwright/test";
+import { test } from "../fixtures/api-fixtures";
+import { setClock, uniqueSuffix } from "../utils/test-helpers";
+
+// Broadcasts are delivered by the global scheduler clock, so these run one at a
+// time and only in the scheduler-clock project (see playwright.config.ts).
+test.describe.serial("Scheduled broadcasts", () => {
+  const start = "2030-01-01T00:00:00Z";
+  const tenant = `broadcasts-${uniqueSuffix()}`;
+  const headers = { "X-Tenant-ID": tenant };
+
+  async function schedule(svcRequest: APIRequestContext, apiBase: string, scheduleFor: string) {
+    const res = await svcRequest.post(`${apiBase}/v1/notifications/broadcast`, {
+      headers,
+      data: {
+        title: "Maintenance",
+        message: "Down for an hour",
+        recipients: [`a-${uniqueSuffix()}@example.com`, `b-${uniqueSuffix()}@example.com`],
+        schedule_for: scheduleFor,
+      },
+    });
+    expect(res.status()).toBe(202);
+    return (await res.json()).broadcast_id as string;
+  }
+
+  async function broadcast(svcRequest: APIRequestContext, apiBase: string, id: string) {
+    const res = await svcRequest.get(`${apiBase}/v1/notifications/broadcasts/${id}`, { headers });
+    expect(res.status()).toBe(200);
+    return res.json();
+  }
+
+  test.beforeEach(async ({ svcRequest, apiBase }) => {
+    await setClock(svcRequest, { now: start, frozen: true }, apiBase);
+  });
+
+  test.afterAll(async ({ svcRequest, apiBase }) => {
+    await svcRequest.delete(`${apiBase}/v1/admin/clock`);
+  });
+
+  test("delivers notifications once the clock reaches schedule_for", async ({
+    svcRequest,
+    apiBase,
+  }) => {
+    const id = await schedule(svcRequest, apiBase, "2030-01-01T00:10:00Z");
+    expect(await broadcast(svcRequest, apiBase, id)).toMatchObject({
+      status: "scheduled",
+      scheduled_for: "2030-01-01T00:10:00Z",
+      recipients_count: 2,
+      delivered_count: 0,
+    });
+
+    await setClock(svcRequest, { advance_seconds: 300 }, apiBase);
+    expect((await broadcast(svcRequest, apiBase, id)).status).toBe("scheduled");
+
+    await setClock(svcRequest, { advance_seconds: 300 }, apiBase);
+    await expect
+      .poll(async () => (await broadcast(svcRequest, apiBase, id)).status)
+      .toBe("sent");
+    const sent = await broadcast(svcRequest, apiBase, id);
+    expect(sent).toMatchObject({ delivered_count: 2, read_count: 0, sent_at: "2030-01-01T00:10:00Z" });
+    expect(sent.notification_ids).toHaveLength(2);
+
+    const first = sent.notification_ids[0];
+    const read = await svcRequest.put(`${apiBase}/v1/notifications/${first}/read`, { headers, data: {} });
+    expect(read.status()).toBe(200);
+    expect((await broadcast(svcRequest, apiBase, id)).read_count).toBe(1);
+
+    const cancel = await svcRequest.post(`${apiBase}/v1/notifications/broadcasts/${id}/cancel`, { headers });
+    expect(cancel.status()).toBe(409);
+  });
+
+  test("cancelled broadcasts are never delivered", async ({ svcRequest, apiBase }) => {
+    const id = await schedule(svcRequest, apiBase, "2030-01-01T00:05:00Z");
+    const cancel = await svcRequest.post(`${apiBase}/v1/notifications/broadcasts/${id}/cancel`, { headers });
+    expect(cancel.status()).toBe(200);
+    expect((await cancel.json()).status).toBe("cancelled");
+
+    await setClock(svcRequest, { advance_seconds: 600 }, apiBase);
+    expect(await broadcast(svcRequest, apiBase, id)).toMatchObject({ status: "cancelled", delivered_count: 0 });
+
+    const reschedule = await svcRequest.post(`${apiBase}/v1/notifications/broadcasts/${id}/reschedule`, {
+      headers,
+      data: { schedule_for: "2030-01-02T00:00:00Z" },
+    });
+    expect(reschedule.status()).toBe(409);
+  });
+
+  test("rescheduling moves delivery", async ({ svcRequest, apiBase }) => {
+    const id = await schedule(svcRequest, apiBase, "2030-01-01T00:05:00Z");
+    const url = `${apiBase}/v1/notifications/broadcasts/${id}/reschedule`;
+    const bad = await svcRequest.post(url, { headers, data: { schedule_for: "tomorrow" } });
+    expect(bad.status()).toBe(400);
+    const moved = await svcRequest.post(url, { headers, data: { schedule_for: "2030-01-01T02:00:00Z" } });
+    expect(moved.status()).toBe(200);
+    expect((await moved.json()).scheduled_for).toBe("2030-01-01T02:00:00Z");
+
+    await setClock(svcRequest, { advance_seconds: 3600 }, apiBase);
+    expect((await broadcast(svcRequest, apiBase, id)).status).toBe("scheduled");
+    await setClock(svcRequest, { advance_seconds: 3600 }, apiBase);
+    await expect
+      .poll(async () => (await broadcast(svcRequest, apiBase, id)).status)
+      .toBe("sent");
+  });
+
+  test("lists broadcasts of the tenant by status", async ({ svcRequest, apiBase }) => {
+    const id = await schedule(svcRequest, apiBase, "2030-06-01T00:00:00Z");
+    const res = await svcRequest.get(`${apiBase}/v1/notifications/broadcasts?status=scheduled`, { headers });
+    expect(res.status()).toBe(200);
+    const body = await res.json();
+    expect(body.broadcasts.map((b: any) => b.id)).toContain(id);
+    expect(body.broadcasts.every((b: any) => b.status === "scheduled")).toBe(true);
+
+    const other = await svcRequest.get(`${apiBase}/v1/notifications/broadcasts`, {
+      headers: { "X-Tenant-ID": `${tenant}-other` },
+    });
+    expect((await other.json()).total).toBe(0);
+
+    const missing = await svcRequest.get(`${apiBase}/v1/notifications/broadcasts/broadcast-missing`, { headers });
+    expect(missing.status()).toBe(404);
+  });
+});
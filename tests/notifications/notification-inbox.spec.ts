@@ -0,0 +1,96 @@
+import { expect, type APIRequestContext } from "@playwright/test";
+import { test } from "../fixtures/api-fixtures";
+import {
+  generateTestUser,
+  loginUser,
+  registerAndLoginUser,
+  uniqueSuffix,
+} from "../utils/test-helpers";
+
+test.describe("Notification inboxes", () => {
+  test("recipients see their notifications across tokens; senders keep edit rights", async ({
+    request,
+    playwright,
+    apiBase,
+  }) => {
+    const tenant = `inbox-${uniqueSuffix()}`;
+    const login = async (prefix: string) => {
+      const { user, token } = await registerAndLoginUser(request, generateTestUser(prefix), apiBase);
+      const ctx = await playwright.request.newContext({
+        extraHTTPHeaders: {
+          "content-type": "application/json",
+          Authorization: `Bearer ${token}`,
+          "X-Tenant-ID": tenant,
+        },
+      });
+      return { user, ctx };
+    };
+    const sender = await login("sender");
+    const recipient = await login("recipient");
+    const inbox = async (ctx: APIRequestContext) =>
+      (await ctx.get(`${apiBase}/v1/notifications/me`)).json();
+
+    try {
+      const created = await sender.ctx.post(`${apiBase}/v1/notifications/`, {
+        data: { title: "Build ready", message: "Please review", recipient: recipient.user.email },
+      });
+      expect(created.status()).toBe(201);
+      const n = await created.json();
+      expect(n.recipient_id).toBeTruthy();
+      expect(n.sender).toMatch(/^user:/);
+
+      const box = await inbox(recipient.ctx);
+      expect(box).toMatchObject({ total: 1, unread_count: 1, recipient: recipient.user.email });
+      expect(box.notifications[0].id).toBe(n.id);
+      expect((await inbox(sender.ctx)).total).toBe(0);
+
+      // a fresh token for the same user sees the same inbox
+      const token = await loginUser(
+        request,
+        { email: recipient.user.email, password: recipient.user.password },
+        apiBase
+      );
+      const again = await request.get(`${apiBase}/v1/notifications/me`, {
+        headers: { Authorization: `Bearer ${token}`, "X-Tenant-ID": tenant },
+      });
+      expect((await again.json()).total).toBe(1);
+
+      const url = `${apiBase}/v1/notifications/${n.id}`;
+      expect((await sender.ctx.put(`${url}/read`, { data: {} })).status()).toBe(403);
+      const read = await recipient.ctx.put(`${url}/read`, { data: {} });
+      expect(await read.json()).toMatchObject({ status: "read", read_by: recipient.user.email });
+      expect((await inbox(recipient.ctx)).unread_count).toBe(0);
+
+      expect((await recipient.ctx.put(url, { data: { title: "Edited" } })).status()).toBe(403);
+      expect((await sender.ctx.put(url, { data: { title: "Edited" } })).status()).toBe(200);
+
+      expect((await recipient.ctx.delete(url)).status()).toBe(204);
+      expect((await sender.ctx.get(url)).status()).toBe(404);
+    } finally {
+      await sender.ctx.dispose();
+      await recipient.ctx.dispose();
+    }
+  });
+
+  test("tenants keep notifications apart", async ({ svcRequest, apiBase }) => {
+    const tenant = `tenant-${uniqueSuffix()}`;
+    const created = await svcRequest.post(`${apiBase}/v1/notifications/`, {
+      headers: { "X-Tenant-ID": tenant },
+      data: { title: "Scoped", message: "Only in one tenant", recipient: "qa@example.com" },
+    });
+    const id = (await created.json()).id;
+    expect(
+      (await svcRequest.get(`${apiBase}/v1/notifications/${id}`, { headers: { "X-Tenant-ID": tenant } })).status()
+    ).toBe(200);
+    expect(
+      (await svcRequest.get(`${apiBase}/v1/notifications/${id}`, { headers: { "X-Tenant-ID": `${tenant}-other` } })).status()
+    ).toBe(404);
+    expect(
+      (await svcRequest.get(`${apiBase}/v1/notifications/`, { headers: { "X-Tenant-ID": "not a tenant" } })).status()
+    ).toBe(400);
+  });
+
+  test("the inbox needs a user token", async ({ svcRequest, apiBase }) => {
+    expect((await svcRequest.get(`${apiBase}/v1/notifications/me`)).status()).toBe(400);
+  });
+});
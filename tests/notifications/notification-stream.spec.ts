@@ -0,0 +1,113 @@
+import { expect } from "@playwright/test";
+import { test } from "../fixtures/api-fixtures";
+import { uniqueSuffix } from "../utils/test-helpers";
+
+type StreamEvent = { id?: string; event?: string; data: string; comment?: string };
+
+// openStream reads a server-sent event stream with fetch, since the Playwright
+// request context only returns complete bodies.
+async function openStream(url: string, headers: Record<string, string>) {
+  const abort = new AbortController();
+  const res = await fetch(url, { headers, signal: abort.signal });
+  const events: StreamEvent[] = [];
+  let buffer = "";
+  const pump = (async () => {
+    const decoder = new TextDecoder();
+    try {
+      for await (const chunk of res.body as any) {
+        buffer += decoder.decode(chunk, { stream: true });
+        let end: number;
+        while ((end = buffer.indexOf("\n\n")) >= 0) {
+          const ev: StreamEvent = { data: "" };
+          for (const line of buffer.slice(0, end).split("\n")) {
+            if (line.startsWith(":")) ev.comment = line.slice(1).trim();
+            if (line.startsWith("id:")) ev.id = line.slice(3).trim();
+            if (line.startsWith("event:")) ev.event = line.slice(6).trim();
+            if (line.startsWith("data:")) ev.data += line.slice(5).trim();
+          }
+          events.push(ev);
+          buffer = buffer.slice(end + 2);
+        }
+      }
+    } catch {
+      // aborted
+    }
+  })();
+  return {
+    res,
+    events,
+    close: async () => {
+      abort.abort();
+      await pump;
+    },
+  };
+}
+
+test.describe("Notification stream", () => {
+  test("pushes create, read and delete events and resumes after Last-Event-ID", async ({
+    svcRequest,
+    apiBase,
+    serviceHeaders,
+  }) => {
+    const tenant = `stream-${uniqueSuffix()}`;
+    const headers = { ...serviceHeaders, "X-Tenant-ID": tenant };
+    const stream = await openStream(`${apiBase}/v1/notifications/stream`, headers);
+    try {
+      expect(stream.res.status).toBe(200);
+      expect(stream.res.headers.get("content-type")).toContain("text/event-stream");
+      await expect.poll(() => stream.events.find((e) => e.event === "ready")).toBeTruthy();
+
+      const created = await svcRequest.post(`${apiBase}/v1/notifications/`, {
+        headers: { "X-Tenant-ID": tenant },
+        data: { title: "Deploy", message: "Rolled out", recipient: `ops-${uniqueSuffix()}@example.com` },
+      });
+      expect(created.status()).toBe(201);
+      const n = await created.json();
+      const url = `${apiBase}/v1/notifications/${n.id}`;
+      expect((await svcRequest.put(`${url}/read`, { headers: { "X-Tenant-ID": tenant }, data: {} })).status()).toBe(200);
+      expect((await svcRequest.delete(url, { headers: { "X-Tenant-ID": tenant } })).status()).toBe(204);
+
+      await expect
+        .poll(() => stream.events.filter((e) => e.id).map((e) => e.event))
+        .toEqual(["created", "read", "deleted"]);
+      const first = stream.events.find((e) => e.event === "created")!;
+      expect(JSON.parse(first.data).notification.id).toBe(n.id);
+
+      const resumed = await openStream(`${apiBase}/v1/notifications/stream`, {
+        ...headers,
+        "Last-Event-ID": first.id!,
+      });
+      try {
+        await expect
+          .poll(() => resumed.events.filter((e) => e.id).map((e) => e.event))
+          .toEqual(["read", "deleted"]);
+      } finally {
+        await resumed.close();
+      }
+    } finally {
+      await stream.close();
+    }
+  });
+
+  test("sends heartbeats and asks unknown cursors to resync", async ({ apiBase, serviceHeaders }) => {
+    const headers = { ...serviceHeaders, "X-Tenant-ID": `stream-${uniqueSuffix()}`, "Last-Event-ID": "42" };
+    const stream = await openStream(`${apiBase}/v1/notifications/stream?heartbeat=1`, headers);
+    try {
+      await expect.poll(() => stream.events.some((e) => e.event === "resync")).toBe(true);
+      await expect
+        .poll(() => stream.events.some((e) => e.comment?.startsWith("heartbeat")), { timeout: 5000 })
+        .toBe(true);
+    } finally {
+      await stream.close();
+    }
+  });
+
+  test("rejects malformed cursors and plain requests to the WebSocket", async ({ svcRequest, apiBase }) => {
+    const bad = await svcRequest.get(`${apiBase}/v1/notifications/stream`, {
+      headers: { "Last-Event-ID": "abc" },
+    });
+    expect(bad.status()).toBe(400);
+    const ws = await svcRequest.get(`${apiBase}/v1/notifications/ws`);
+    expect(ws.status()).toBe(400);
+  });
+});
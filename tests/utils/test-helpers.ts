@@ -185,6 +185,131 @@ export function generateWorkflowData() {
   };
 }
 
+/**
+ * Create a workflow with the given steps and return its ID
+ */
+export async function createWorkflow(
+  request: any,
+  steps: unknown[],
+  baseURL: string = API_CONFIG.BASE_URL,
+  name: string = generateResourceName("workflow")
+): Promise<string> {
+  const response = await request.post(`${baseURL}/v1/workflows/`, {
+    data: { name, steps },
+  });
+
+  if (response.status() !== 201) {
+    throw new Error(
+      `Workflow creation failed: ${response.status()} ${await response.text()}`
+    );
+  }
+
+  return (await response.json()).id;
+}
+
+/**
+ * Start an execution of a workflow and return its execution ID
+ */
+export async function executeWorkflow(
+  request: any,
+  workflowId: string,
+  data: Record<string, unknown> = {},
+  baseURL: string = API_CONFIG.BASE_URL
+): Promise<string> {
+  const response = await request.post(
+    `${baseURL}/v1/workflows/${workflowId}/execute`,
+    { data }
+  );
+
+  if (response.status() !== 202) {
+    throw new Error(
+      `Workflow execution failed: ${response.status()} ${await response.text()}`
+    );
+  }
+
+  return (await response.json()).execution_id;
+}
+
+/**
+ * Get the status of a workflow's latest execution
+ */
+export async function getWorkflowStatus(
+  request: any,
+  workflowId: string,
+  baseURL: string = API_CONFIG.BASE_URL
+) {
+  const response = await request.get(
+    `${baseURL}/v1/workflows/${workflowId}/status`
+  );
+
+  if (!response.ok()) {
+    throw new Error(
+      `Workflow status failed: ${response.status()} ${await response.text()}`
+    );
+  }
+
+  return response.json();
+}
+
+/**
+ * Create and execute a workflow, then wait until it waits for a decision;
+ * returns the workflow ID
+ */
+export async function startWorkflow(
+  request: any,
+  steps: unknown[],
+  baseURL: string = API_CONFIG.BASE_URL
+): Promise<string> {
+  const id = await createWorkflow(request, steps, baseURL);
+  await executeWorkflow(request, id, {}, baseURL);
+  await waitFor(
+    async () =>
+      (await getWorkflowStatus(request, id, baseURL)).status ===
+      "waiting_approval"
+  );
+  return id;
+}
+
+/**
+ * Create and execute a workflow, then wait until its execution has finished
+ * (completed, failed or timed out); returns the final status
+ */
+export async function runWorkflowToEnd(
+  request: any,
+  steps: unknown[],
+  baseURL: string = API_CONFIG.BASE_URL,
+  timeout: number = 10000
+) {
+  const id = await createWorkflow(request, steps, baseURL);
+  await executeWorkflow(request, id, {}, baseURL);
+  let status: any;
+  await waitFor(async () => {
+    status = await getWorkflowStatus(request, id, baseURL);
+    return ["completed", "failed", "timeout"].includes(status.status);
+  }, timeout);
+  return status;
+}
+
+/**
+ * Move the scheduler clock (needs a gateway started with ENABLE_TEST_CLOCK=true);
+ * returns the clock's new state
+ */
+export async function setClock(
+  request: any,
+  data: { now?: string; advance_seconds?: number; frozen?: boolean },
+  baseURL: string = API_CONFIG.BASE_URL
+) {
+  const response = await request.put(`${baseURL}/v1/admin/clock`, { data });
+
+  if (response.status() !== 200) {
+    throw new Error(
+      `Clock update failed: ${response.status()} ${await response.text()}`
+    );
+  }
+
+  return response.json();
+}
+
 /**
  * Generate unique audit log data
  */
@@ -0,0 +1,140 @@
+import { expect } from "@playwright/test";
+import { test } from "../fixtures/api-fixtures";
+import { getWorkflowStatus, startWorkflow } from "../utils/test-helpers";
+
+const approve = (approver: string) => ({ approver, decision: "approved" });
+
+test.describe("Workflow approvals", () => {
+  test("waits for a quorum of listed approvers", async ({
+    svcRequest,
+    apiBase,
+  }) => {
+    const id = await startWorkflow(
+      svcRequest,
+      [
+        {
+          id: "review",
+          name: "Review",
+          type: "approval",
+          approvers: ["a@example.com", "b@example.com", "c@example.com"],
+          quorum: 2,
+        },
+      ],
+      apiBase
+    );
+    const url = `${apiBase}/v1/workflows/${id}/approve`;
+
+    const outsider = await svcRequest.post(url, { data: approve("x@example.com") });
+    expect(outsider.status()).toBe(403);
+
+    const first = await svcRequest.post(url, {
+      data: {
+        ...approve("a@example.com"),
+        comments: "lgtm",
+        timestamp: "2026-01-02T03:04:05Z",
+      },
+    });
+    expect(first.status()).toBe(200);
+    const fj = await first.json();
+    expect(fj.updated_status).toBe("waiting_approval");
+    expect(fj.approvals_remaining).toBe(1);
+
+    const dup = await svcRequest.post(url, { data: approve("a@example.com") });
+    expect(dup.status()).toBe(409);
+
+    const second = await svcRequest.post(url, { data: approve("b@example.com") });
+    expect((await second.json()).updated_status).toBe("in_progress");
+
+    await expect
+      .poll(async () => (await getWorkflowStatus(svcRequest, id, apiBase)).status)
+      .toBe("completed");
+    const st = await getWorkflowStatus(svcRequest, id, apiBase);
+    expect(st.steps.review.approvals).toHaveLength(2);
+    const recorded = st.execution_history.find(
+      (e: any) => e.event === "decision_recorded" && e.approver === "a@example.com"
+    );
+    expect(recorded).toMatchObject({
+      decision: "approved",
+      comments: "lgtm",
+      decided_at: "2026-01-02T03:04:05Z",
+    });
+  });
+
+  test("requires_all needs every approver", async ({ svcRequest, apiBase }) => {
+    const id = await startWorkflow(
+      svcRequest,
+      [
+        {
+          id: "review",
+          name: "Review",
+          type: "approval",
+          approvers: ["a@example.com", "b@example.com"],
+          requires_all: true,
+        },
+      ],
+      apiBase
+    );
+    const url = `${apiBase}/v1/workflows/${id}/approve`;
+    await svcRequest.post(url, { data: approve("a@example.com") });
+    expect((await getWorkflowStatus(svcRequest, id, apiBase)).status).toBe(
+      "waiting_approval"
+    );
+    await svcRequest.post(url, { data: approve("b@example.com") });
+    await expect
+      .poll(async () => (await getWorkflowStatus(svcRequest, id, apiBase)).status)
+      .toBe("completed");
+  });
+
+  test("rejection routes to the on_reject branch", async ({
+    svcRequest,
+    apiBase,
+  }) => {
+    const id = await startWorkflow(
+      svcRequest,
+      [
+        { id: "review", name: "Review", type: "approval", on_reject: "rework" },
+        { id: "publish", name: "Publish", type: "delay", depends_on: ["review"] },
+        { id: "rework", name: "Rework", type: "delay", config: { duration_ms: 10 } },
+      ],
+      apiBase
+    );
+    const anonymous = await svcRequest.post(`${apiBase}/v1/workflows/${id}/reject`, {
+      data: { decision: "rejected", reason: "typos" },
+    });
+    expect(anonymous.status()).toBe(400);
+    const res = await svcRequest.post(`${apiBase}/v1/workflows/${id}/reject`, {
+      data: { approver: "a@example.com", decision: "rejected", reason: "typos" },
+    });
+    expect(res.status()).toBe(200);
+    const body = await res.json();
+    expect(body.next_step).toBe("rework");
+    expect(body.workflow_status).toBe("in_progress");
+
+    await expect
+      .poll(async () => (await getWorkflowStatus(svcRequest, id, apiBase)).status)
+      .toBe("completed");
+    const st = await getWorkflowStatus(svcRequest, id, apiBase);
+    expect(st.steps.review.status).toBe("rejected");
+    expect(st.steps.rework.status).toBe("completed");
+    expect(st.steps.publish.status).toBe("skipped");
+  });
+
+  test("validates approver settings", async ({ svcRequest, apiBase }) => {
+    const res = await svcRequest.post(`${apiBase}/v1/workflows/`, {
+      data: {
+        name: "bad-quorum",
+        steps: [
+          { id: "r", name: "R", type: "approval", approvers: ["a@example.com"], quorum: 2 },
+        ],
+      },
+    });
+    expect(res.status()).toBe(400);
+    const branch = await svcRequest.post(`${apiBase}/v1/workflows/`, {
+      data: {
+        name: "bad-branch",
+        steps: [{ id: "r", name: "R", type: "approval", on_reject: "nope" }],
+      },
+    });
+    expect(branch.status()).toBe(400);
+  });
+});
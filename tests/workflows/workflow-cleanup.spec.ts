@@ -0,0 +1,117 @@
+import { expect, type APIRequestContext } from "@playwright/test";
+import { test } from "../fixtures/api-fixtures";
+import { createWorkflow, executeWorkflow } from "../utils/test-helpers";
+
+const gate = [{ id: "gate", name: "Gate", type: "approval", approvers: ["qa@example.com"] }];
+
+async function startExecution(svcRequest: APIRequestContext, apiBase: string, id: string) {
+  const execId = await executeWorkflow(svcRequest, id, {}, apiBase);
+  await expect
+    .poll(async () => (await (await svcRequest.get(`${apiBase}/v1/workflows/${id}/executions/${execId}`)).json()).status)
+    .toBe("waiting_approval");
+  return execId;
+}
+
+// retention is a global setting, so these run one at a time
+test.describe.serial("Workflow deletion and cleanup", () => {
+  test("delete is refused while an execution runs unless forced", async ({
+    svcRequest,
+    apiBase,
+  }) => {
+    const id = await createWorkflow(svcRequest, gate, apiBase, `cleanup-${Date.now()}`);
+    const execId = await startExecution(svcRequest, apiBase, id);
+
+    const refused = await svcRequest.delete(`${apiBase}/v1/workflows/${id}`);
+    expect(refused.status()).toBe(409);
+    expect((await refused.json()).running_executions).toEqual([execId]);
+
+    const forced = await svcRequest.delete(`${apiBase}/v1/workflows/${id}?force=true`);
+    expect(forced.status()).toBe(200);
+    expect(await forced.json()).toMatchObject({
+      workflow_id: id,
+      cancelled_executions: [execId],
+      executions_deleted: 1,
+      executions_retained: 0,
+    });
+
+    expect((await svcRequest.get(`${apiBase}/v1/workflows/${id}`)).status()).toBe(404);
+    expect(
+      (await svcRequest.get(`${apiBase}/v1/workflows/${id}/executions/${execId}`)).status()
+    ).toBe(404);
+    expect((await svcRequest.delete(`${apiBase}/v1/workflows/${id}`)).status()).toBe(404);
+  });
+
+  test("archived workflows are hidden from the list and cannot run", async ({
+    svcRequest,
+    apiBase,
+  }) => {
+    const name = `archive-${Date.now()}`;
+    const id = await createWorkflow(svcRequest, gate, apiBase, name);
+
+    const archived = await svcRequest.post(`${apiBase}/v1/workflows/${id}/archive`);
+    expect(archived.status()).toBe(200);
+    expect((await archived.json()).status).toBe("archived");
+
+    const listed = async (query: string) =>
+      (await (await svcRequest.get(`${apiBase}/v1/workflows/?name=${name}${query}`)).json()).total;
+    expect(await listed("")).toBe(0);
+    expect(await listed("&include_archived=true")).toBe(1);
+    expect(await listed("&status=archived")).toBe(1);
+
+    expect((await svcRequest.post(`${apiBase}/v1/workflows/${id}/execute`, { data: {} })).status()).toBe(409);
+    expect((await svcRequest.put(`${apiBase}/v1/workflows/${id}`, { data: { name } })).status()).toBe(409);
+
+    const restored = await svcRequest.post(`${apiBase}/v1/workflows/${id}/unarchive`);
+    expect((await restored.json()).status).toBe("draft");
+    expect(await listed("")).toBe(1);
+  });
+
+  test("executions are kept for the configured retention", async ({ svcRequest, apiBase }) => {
+    const config = `${apiBase}/v1/admin/system/config`;
+    await svcRequest.put(config, { data: { workflows: { execution_retention: 2 } } });
+    try {
+      const id = await createWorkflow(svcRequest, gate, apiBase, `retention-${Date.now()}`);
+      const execId = await startExecution(svcRequest, apiBase, id);
+      const removed = await (
+        await svcRequest.delete(`${apiBase}/v1/workflows/${id}?force=true`)
+      ).json();
+      expect(removed).toMatchObject({ executions_deleted: 0, executions_retained: 1 });
+
+      const kept = await svcRequest.get(`${apiBase}/v1/workflows/${id}/executions/${execId}`);
+      expect(kept.status()).toBe(200);
+      expect(await kept.json()).toMatchObject({
+        status: "cancelled",
+        retained_until: removed.retained_until,
+      });
+      await expect
+        .poll(async () => (await svcRequest.get(`${apiBase}/v1/workflows/${id}/executions/${execId}`)).status(), {
+          timeout: 10_000,
+        })
+        .toBe(404);
+    } finally {
+      await svcRequest.put(config, { data: { workflows: { execution_retention: 0 } } });
+    }
+  });
+
+  test("purge removes matching workflows for teardown", async ({ svcRequest, apiBase }) => {
+    const prefix = `purge-${Date.now()}`;
+    const idle = await createWorkflow(svcRequest, gate, apiBase, `${prefix}-idle`);
+    const busy = await createWorkflow(svcRequest, gate, apiBase, `${prefix}-busy`);
+    await startExecution(svcRequest, apiBase, busy);
+
+    expect((await svcRequest.post(`${apiBase}/v1/workflows/purge`, { data: {} })).status()).toBe(400);
+
+    const first = await (
+      await svcRequest.post(`${apiBase}/v1/workflows/purge`, { data: { name: prefix } })
+    ).json();
+    expect(first.deleted.map((d: { workflow_id: string }) => d.workflow_id)).toEqual([idle]);
+    expect(first.skipped).toHaveLength(1);
+    expect(first.skipped[0].workflow_id).toBe(busy);
+
+    const forced = await (
+      await svcRequest.post(`${apiBase}/v1/workflows/purge`, { data: { name: prefix, force: true } })
+    ).json();
+    expect(forced).toMatchObject({ total_deleted: 1, executions_deleted: 1, skipped: [] });
+    expect((await svcRequest.get(`${apiBase}/v1/workflows/${busy}`)).status()).toBe(404);
+  });
+});
@@ -0,0 +1,171 @@
+import { expect } from "@playwright/test";
+import { test } from "../fixtures/api-fixtures";
+import {
+  createWorkflow,
+  executeWorkflow,
+  getWorkflowStatus,
+} from "../utils/test-helpers";
+
+test.describe("Workflow execution engine", () => {
+  test("runs independent steps in parallel and dependents after them", async ({
+    svcRequest,
+    apiBase,
+  }) => {
+    const id = await createWorkflow(
+      svcRequest,
+      [
+        { id: "a", name: "A", type: "delay", config: { duration_ms: 1000 } },
+        { id: "b", name: "B", type: "delay", config: { duration_ms: 1000 } },
+        {
+          id: "c",
+          name: "C",
+          type: "ai_completion",
+          depends_on: ["a", "b"],
+          config: { prompt: "summarize", model: "adapter-a" },
+        },
+      ],
+      apiBase
+    );
+    const exec = await svcRequest.post(`${apiBase}/v1/workflows/${id}/execute`, {
+      data: { triggered_by: "engine-test" },
+    });
+    expect(exec.status()).toBe(202);
+    expect((await exec.json()).status).toBe("started");
+
+    await expect
+      .poll(async () => {
+        const { steps } = await getWorkflowStatus(svcRequest, id, apiBase);
+        return [steps.a.status, steps.b.status, steps.c.status];
+      })
+      .toEqual(["running", "running", "pending"]);
+
+    await expect
+      .poll(async () => (await getWorkflowStatus(svcRequest, id, apiBase)).status)
+      .toBe("completed");
+    const done = await getWorkflowStatus(svcRequest, id, apiBase);
+    expect(done.progress).toBe("100%");
+    expect(done.steps.c.output.text).toBeTruthy();
+    expect(done.steps.c.output.model).toBe("adapter-a");
+    const events = done.execution_history.map((e: any) => e.event);
+    expect(events[0]).toBe("execution_started");
+    expect(events[events.length - 1]).toBe("execution_completed");
+    const cStarted = done.execution_history.findIndex(
+      (e: any) => e.event === "step_started" && e.step_id === "c"
+    );
+    const aDone = done.execution_history.findIndex(
+      (e: any) => e.event === "step_completed" && e.step_id === "a"
+    );
+    expect(cStarted).toBeGreaterThan(aDone);
+  });
+
+  test("pauses on approval steps until approved", async ({
+    svcRequest,
+    apiBase,
+  }) => {
+    const id = await createWorkflow(
+      svcRequest,
+      [
+        { id: "review", name: "Review", type: "approval" },
+        {
+          id: "after",
+          name: "After",
+          type: "delay",
+          depends_on: ["review"],
+          config: { duration_ms: 10 },
+        },
+      ],
+      apiBase
+    );
+    await executeWorkflow(svcRequest, id, {}, apiBase);
+    await expect
+      .poll(async () => (await getWorkflowStatus(svcRequest, id, apiBase)).status)
+      .toBe("waiting_approval");
+    const waiting = await getWorkflowStatus(svcRequest, id, apiBase);
+    expect(waiting.current_step).toBe("review");
+    expect(waiting.next_actions).toEqual(["approve", "reject"]);
+
+    const approve = await svcRequest.post(`${apiBase}/v1/workflows/${id}/approve`, {
+      data: { approver: "lead@example.com", decision: "approved" },
+    });
+    expect(approve.status()).toBe(200);
+    expect((await approve.json()).step_id).toBe("review");
+
+    await expect
+      .poll(async () => (await getWorkflowStatus(svcRequest, id, apiBase)).status)
+      .toBe("completed");
+    const again = await svcRequest.post(`${apiBase}/v1/workflows/${id}/approve`, {
+      data: { approver: "lead@example.com", decision: "approved" },
+    });
+    expect(again.status()).toBe(409);
+  });
+
+  test("rejection fails the execution and skips dependents", async ({
+    svcRequest,
+    apiBase,
+  }) => {
+    const id = await createWorkflow(
+      svcRequest,
+      [
+        { id: "review", name: "Review", type: "approval" },
+        { id: "after", name: "After", type: "delay", depends_on: ["review"] },
+      ],
+      apiBase
+    );
+    await executeWorkflow(svcRequest, id, {}, apiBase);
+    await expect
+      .poll(async () => (await getWorkflowStatus(svcRequest, id, apiBase)).status)
+      .toBe("waiting_approval");
+    await svcRequest.post(`${apiBase}/v1/workflows/${id}/reject`, {
+      data: { approver: "lead@example.com", decision: "rejected", reason: "no" },
+    });
+    await expect
+      .poll(async () => (await getWorkflowStatus(svcRequest, id, apiBase)).status)
+      .toBe("failed");
+    const st = await getWorkflowStatus(svcRequest, id, apiBase);
+    expect(st.steps.review.status).toBe("rejected");
+    expect(st.steps.after.status).toBe("skipped");
+  });
+
+  test("enforces per-step timeouts", async ({ svcRequest, apiBase }) => {
+    const id = await createWorkflow(
+      svcRequest,
+      [{ id: "slow", name: "Slow", type: "delay", timeout: 1, config: { seconds: 5 } }],
+      apiBase
+    );
+    await executeWorkflow(svcRequest, id, {}, apiBase);
+    await expect
+      .poll(async () => (await getWorkflowStatus(svcRequest, id, apiBase)).status, {
+        timeout: 5000,
+      })
+      .toBe("timeout");
+    expect((await getWorkflowStatus(svcRequest, id, apiBase)).steps.slow.status).toBe(
+      "timeout"
+    );
+  });
+
+  test("fails steps of unsupported types and rejects unknown dependencies", async ({
+    svcRequest,
+    apiBase,
+  }) => {
+    const id = await createWorkflow(
+      svcRequest,
+      [{ id: "x", name: "X", type: "teleport" }],
+      apiBase
+    );
+    await executeWorkflow(svcRequest, id, {}, apiBase);
+    await expect
+      .poll(async () => (await getWorkflowStatus(svcRequest, id, apiBase)).status)
+      .toBe("failed");
+    expect((await getWorkflowStatus(svcRequest, id, apiBase)).error).toContain(
+      "unsupported step type"
+    );
+
+    const bad = await svcRequest.post(`${apiBase}/v1/workflows/`, {
+      data: {
+        name: "bad-deps",
+        steps: [{ id: "a", name: "A", type: "delay", depends_on: ["missing"] }],
+      },
+    });
+    expect(bad.status()).toBe(400);
+  });
+});
@@ -0,0 +1,122 @@
+import { expect, type APIRequestContext } from "@playwright/test";
+import { test } from "../fixtures/api-fixtures";
+import { createWorkflow } from "../utils/test-helpers";
+
+async function workflowWithTrigger(
+  svcRequest: APIRequestContext,
+  apiBase: string,
+  trigger: object
+) {
+  const id = await createWorkflow(
+    svcRequest,
+    [{ id: "wait", name: "Wait", type: "delay", config: { duration_ms: 10 } }],
+    apiBase
+  );
+  const res = await svcRequest.post(`${apiBase}/v1/workflows/${id}/triggers`, {
+    data: trigger,
+  });
+  expect(res.status()).toBe(201);
+  return { id, trigger: await res.json() };
+}
+
+test.describe("Workflow event triggers", () => {
+  test("matching analytics events start executions with the event as parameters", async ({
+    svcRequest,
+    apiBase,
+  }) => {
+    const eventType = `signup_${Date.now()}`;
+    const { id, trigger } = await workflowWithTrigger(svcRequest, apiBase, {
+      event: { event_type: eventType, filters: { "properties.plan": "pro" } },
+      dedup_key: "properties.email",
+    });
+    expect(trigger).toMatchObject({
+      type: "event",
+      event: { source: "analytics", event_type: eventType },
+    });
+
+    const event = {
+      event_type: eventType,
+      user_id: "u1",
+      properties: { plan: "pro", email: "u1@example.com" },
+    };
+    const first = await (
+      await svcRequest.post(`${apiBase}/v1/analytics/events`, { data: event })
+    ).json();
+    expect(first.triggered_executions).toHaveLength(1);
+    const run = first.triggered_executions[0];
+    expect(run).toMatchObject({
+      workflow_id: id,
+      trigger_id: trigger.id,
+      idempotency_key: "u1@example.com",
+      duplicate: false,
+    });
+
+    const again = await (
+      await svcRequest.post(`${apiBase}/v1/analytics/events`, { data: event })
+    ).json();
+    expect(again.triggered_executions[0]).toMatchObject({
+      execution_id: run.execution_id,
+      duplicate: true,
+    });
+
+    const other = await (
+      await svcRequest.post(`${apiBase}/v1/analytics/events`, {
+        data: { ...event, properties: { plan: "free", email: "u2@example.com" } },
+      })
+    ).json();
+    expect(other.triggered_executions).toBeUndefined();
+
+    const ex = await (
+      await svcRequest.get(`${apiBase}/v1/workflows/${id}/executions/${run.execution_id}`)
+    ).json();
+    expect(ex.triggered_by).toBe(`trigger:${trigger.id}`);
+    expect(ex.parameters).toMatchObject({ event_type: eventType, user_id: "u1" });
+    expect(ex.context).toMatchObject({ event_source: "analytics", trigger_id: trigger.id });
+    const list = await (
+      await svcRequest.get(`${apiBase}/v1/workflows/${id}/executions`)
+    ).json();
+    expect(list.total).toBe(1);
+  });
+
+  test("audit logs trigger workflows and dedup by Idempotency-Key", async ({
+    svcRequest,
+    apiBase,
+  }) => {
+    const action = `delete_user_${Date.now()}`;
+    const { id } = await workflowWithTrigger(svcRequest, apiBase, {
+      event: { action, filters: { resource_type: "user" } },
+    });
+    const log = { user_id: "admin", action, resource_type: "user", resource_id: "u9" };
+    const post = (key?: string) =>
+      svcRequest.post(`${apiBase}/v1/audit/logs`, {
+        data: log,
+        headers: key ? { "Idempotency-Key": key } : {},
+      });
+
+    const first = await (await post("k1")).json();
+    expect(first.triggered_executions[0]).toMatchObject({ workflow_id: id, duplicate: false });
+    const dup = await (await post("k1")).json();
+    expect(dup.triggered_executions[0]).toMatchObject({
+      execution_id: first.triggered_executions[0].execution_id,
+      duplicate: true,
+    });
+    const noKey = await (await post()).json();
+    expect(noKey.triggered_executions[0].duplicate).toBe(false);
+  });
+
+  test("rejects invalid event triggers", async ({ svcRequest, apiBase }) => {
+    const created = await svcRequest.post(`${apiBase}/v1/workflows/`, {
+      data: { name: "bad-event", steps: [{ id: "s", name: "S", type: "delay" }] },
+    });
+    const base = `${apiBase}/v1/workflows/${(await created.json()).id}/triggers`;
+    for (const data of [
+      { event: {} },
+      { event: { source: "logs", event_type: "x" } },
+      { event: { action: "x" }, missed_run_policy: "skip" },
+      { cron: "@daily", dedup_key: "user_id" },
+      { cron: "@daily", event: { event_type: "x" } },
+    ]) {
+      expect((await svcRequest.post(base, { data })).status()).toBe(400);
+    }
+  });
+});
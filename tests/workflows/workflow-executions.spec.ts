@@ -0,0 +1,110 @@
+import { expect } from "@playwright/test";
+import { test } from "../fixtures/api-fixtures";
+import { createWorkflow, executeWorkflow } from "../utils/test-helpers";
+
+const reviewSteps = [{ id: "review", name: "Review", type: "approval" }];
+
+test.describe("Workflow executions", () => {
+  test("keeps every execution with a unique ID", async ({
+    svcRequest,
+    apiBase,
+  }) => {
+    const id = await createWorkflow(svcRequest, reviewSteps, apiBase);
+    const first = await executeWorkflow(svcRequest, id, {}, apiBase);
+    const second = await executeWorkflow(svcRequest, id, {}, apiBase);
+    expect(second).not.toBe(first);
+
+    const list = await svcRequest.get(`${apiBase}/v1/workflows/${id}/executions`);
+    expect(list.status()).toBe(200);
+    const body = await list.json();
+    expect(body.total).toBe(2);
+    expect(body.executions.map((e: any) => e.execution_id)).toEqual([second, first]);
+
+    const page = await svcRequest.get(
+      `${apiBase}/v1/workflows/${id}/executions?limit=1&page=2`
+    );
+    expect((await page.json()).executions[0].execution_id).toBe(first);
+
+    const status = await svcRequest.get(`${apiBase}/v1/workflows/${id}/status`);
+    expect((await status.json()).execution_id).toBe(second);
+
+    const one = await svcRequest.get(
+      `${apiBase}/v1/workflows/${id}/executions/${first}`
+    );
+    expect(one.status()).toBe(200);
+    const ej = await one.json();
+    expect(ej.execution_id).toBe(first);
+    expect(ej.steps.review).toBeTruthy();
+
+    const missing = await svcRequest.get(
+      `${apiBase}/v1/workflows/${id}/executions/exec-missing`
+    );
+    expect(missing.status()).toBe(404);
+  });
+
+  test("approves a specific execution", async ({ svcRequest, apiBase }) => {
+    const id = await createWorkflow(svcRequest, reviewSteps, apiBase);
+    const first = await executeWorkflow(svcRequest, id, {}, apiBase);
+    const second = await executeWorkflow(svcRequest, id, {}, apiBase);
+    await expect
+      .poll(async () => {
+        const res = await svcRequest.get(
+          `${apiBase}/v1/workflows/${id}/executions?status=waiting_approval`
+        );
+        return (await res.json()).total;
+      })
+      .toBe(2);
+
+    const res = await svcRequest.post(`${apiBase}/v1/workflows/${id}/approve`, {
+      data: { execution_id: first, approver: "a@example.com", decision: "approved" },
+    });
+    expect(res.status()).toBe(200);
+    await expect
+      .poll(async () => {
+        const r = await svcRequest.get(`${apiBase}/v1/workflows/${id}/executions/${first}`);
+        return (await r.json()).status;
+      })
+      .toBe("completed");
+    const other = await svcRequest.get(
+      `${apiBase}/v1/workflows/${id}/executions/${second}`
+    );
+    expect((await other.json()).status).toBe("waiting_approval");
+  });
+
+  test("cancels and retries an execution", async ({ svcRequest, apiBase }) => {
+    const id = await createWorkflow(svcRequest, reviewSteps, apiBase);
+    const execId = await executeWorkflow(
+      svcRequest,
+      id,
+      { parameters: { ticket: "QA-1" } },
+      apiBase
+    );
+
+    const retryRunning = await svcRequest.post(
+      `${apiBase}/v1/workflows/${id}/executions/${execId}/retry`
+    );
+    expect(retryRunning.status()).toBe(409);
+
+    const cancel = await svcRequest.post(
+      `${apiBase}/v1/workflows/${id}/executions/${execId}/cancel`
+    );
+    expect(cancel.status()).toBe(200);
+    expect((await cancel.json()).status).toBe("cancelled");
+
+    const again = await svcRequest.post(
+      `${apiBase}/v1/workflows/${id}/executions/${execId}/cancel`
+    );
+    expect(again.status()).toBe(409);
+
+    const retry = await svcRequest.post(
+      `${apiBase}/v1/workflows/${id}/executions/${execId}/retry`
+    );
+    expect(retry.status()).toBe(202);
+    const rj = await retry.json();
+    expect(rj.retry_of).toBe(execId);
+    const fresh = await svcRequest.get(
+      `${apiBase}/v1/workflows/${id}/executions/${rj.execution_id}`
+    );
+    expect((await fresh.json()).parameters).toEqual({ ticket: "QA-1" });
+  });
+});
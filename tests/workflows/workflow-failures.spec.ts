@@ -0,0 +1,85 @@
+import { expect } from "@playwright/test";
+import { test } from "../fixtures/api-fixtures";
+import { runWorkflowToEnd } from "../utils/test-helpers";
+
+const events = (st: any, stepId: string) =>
+  st.execution_history.filter((e: any) => e.step_id === stepId);
+
+test.describe("Workflow step failure handling", () => {
+  test("retries failed attempts with backoff before failing", async ({
+    svcRequest,
+    apiBase,
+  }) => {
+    const st = await runWorkflowToEnd(
+      svcRequest,
+      [
+        {
+          id: "call",
+          name: "Call",
+          type: "http_call",
+          config: { url: "http://127.0.0.1:1/unreachable" },
+          retry: { count: 2, backoff_ms: 100, backoff: "exponential" },
+        },
+      ],
+      apiBase
+    );
+    expect(st.status).toBe("failed");
+    expect(st.steps.call).toMatchObject({ status: "failed", attempts: 3 });
+    const retries = events(st, "call").filter((e: any) => e.event === "step_retry");
+    expect(retries.map((e: any) => [e.attempt, e.retry_in_ms])).toEqual([
+      [1, 100],
+      [2, 200],
+    ]);
+  });
+
+  test("times out each attempt and continues when on_failure is continue", async ({
+    svcRequest,
+    apiBase,
+  }) => {
+    const st = await runWorkflowToEnd(
+      svcRequest,
+      [
+        { id: "check", name: "Check", type: "automated", timeout: 1, on_failure: "continue" },
+        { id: "after", name: "After", type: "delay", depends_on: ["check"] },
+      ],
+      apiBase
+    );
+    expect(st.status).toBe("completed");
+    expect(st.steps.check.status).toBe("timeout");
+    expect(st.steps.after.status).toBe("completed");
+    const timedOut = events(st, "check").find((e: any) => e.event === "step_timeout");
+    expect(timedOut.on_failure).toBe("continue");
+  });
+
+  test("jumps to a compensating step on failure", async ({ svcRequest, apiBase }) => {
+    const st = await runWorkflowToEnd(
+      svcRequest,
+      [
+        { id: "charge", name: "Charge", type: "no_such_type", on_failure: "refund" },
+        { id: "ship", name: "Ship", type: "delay", depends_on: ["charge"] },
+        { id: "refund", name: "Refund", type: "delay" },
+      ],
+      apiBase
+    );
+    expect(st.status).toBe("completed");
+    expect(st.steps.charge.status).toBe("failed");
+    expect(st.steps.ship.status).toBe("skipped");
+    expect(st.steps.refund.status).toBe("completed");
+    const failed = events(st, "charge").find((e: any) => e.event === "step_failed");
+    expect(failed.next_step).toBe("refund");
+  });
+
+  for (const [label, step] of [
+    ["too many retries", { retry: { count: 11 } }],
+    ["unknown backoff", { retry: { count: 1, backoff: "linear" } }],
+    ["negative backoff", { retry: { count: 1, backoff_ms: -1 } }],
+    ["unknown on_failure step", { on_failure: "missing" }],
+  ] as const) {
+    test(`rejects ${label}`, async ({ svcRequest, apiBase }) => {
+      const res = await svcRequest.post(`${apiBase}/v1/workflows/`, {
+        data: { name: "bad", steps: [{ id: "a", name: "A", type: "delay", ...step }] },
+      });
+      expect(res.status()).toBe(400);
+    });
+  }
+});
@@ -0,0 +1,75 @@
+import { expect } from "@playwright/test";
+import { test } from "../fixtures/api-fixtures";
+import { createWorkflow, executeWorkflow } from "../utils/test-helpers";
+
+test.describe("Workflow graph", () => {
+  test("renders steps and edges, colored by execution status", async ({
+    svcRequest,
+    apiBase,
+  }) => {
+    const id = await createWorkflow(
+      svcRequest,
+      [
+        { id: "build", name: "Build", type: "delay", config: { duration_ms: 10 } },
+        {
+          id: "review",
+          name: "Review",
+          type: "approval",
+          depends_on: ["build"],
+          approvers: ["qa@example.com"],
+          on_reject: "fix",
+        },
+        { id: "fix", name: "Fix", type: "task" },
+        { id: "ship", name: "Ship", type: "delay", depends_on: ["review"], config: { duration_ms: 10 } },
+      ],
+      apiBase
+    );
+
+    const graph = await (await svcRequest.get(`${apiBase}/v1/workflows/${id}/graph`)).json();
+    expect(graph.nodes.map((n: { id: string; level: number }) => [n.id, n.level])).toEqual([
+      ["build", 0],
+      ["review", 1],
+      ["fix", 0],
+      ["ship", 2],
+    ]);
+    expect(graph.edges).toEqual([
+      { from: "build", to: "review", kind: "depends_on" },
+      { from: "review", to: "fix", kind: "on_reject" },
+      { from: "review", to: "ship", kind: "depends_on" },
+    ]);
+
+    const execId = await executeWorkflow(svcRequest, id, {}, apiBase);
+    await expect
+      .poll(async () => {
+        const g = await (
+          await svcRequest.get(`${apiBase}/v1/workflows/${id}/graph?execution_id=${execId}`)
+        ).json();
+        return g.nodes.find((n: { id: string }) => n.id === "review").status;
+      })
+      .toBe("waiting");
+
+    const dot = await svcRequest.get(`${apiBase}/v1/workflows/${id}/graph?format=dot&execution_id=latest`);
+    expect(dot.headers()["content-type"]).toContain("text/vnd.graphviz");
+    const dotText = await dot.text();
+    expect(dotText).toContain(`digraph "${id}"`);
+    expect(dotText).toContain(`"build" [label="Build\\ndelay", fillcolor="#86efac", tooltip="completed"]`);
+    expect(dotText).toContain(`"review" -> "fix" [style=dashed, label="on_reject"]`);
+
+    const mermaid = await (
+      await svcRequest.get(`${apiBase}/v1/workflows/${id}/graph?format=mermaid&execution_id=${execId}`)
+    ).text();
+    expect(mermaid.startsWith("flowchart LR\n")).toBe(true);
+    expect(mermaid).toContain("n0 --> n1");
+    expect(mermaid).toContain("n1 -. on_reject .-> n2");
+    expect(mermaid).toContain("class n1 waiting");
+  });
+
+  test("rejects unknown formats and executions", async ({ svcRequest, apiBase }) => {
+    const id = await createWorkflow(svcRequest, [{ id: "a", name: "A", type: "task" }], apiBase);
+    expect((await svcRequest.get(`${apiBase}/v1/workflows/${id}/graph?format=png`)).status()).toBe(400);
+    expect(
+      (await svcRequest.get(`${apiBase}/v1/workflows/${id}/graph?execution_id=exec-missing`)).status()
+    ).toBe(404);
+    expect((await svcRequest.get(`${apiBase}/v1/workflows/wf-missing/graph`)).status()).toBe(404);
+  });
+});
@@ -0,0 +1,116 @@
+import { expect } from "@playwright/test";
+import { test } from "../fixtures/api-fixtures";
+import { createWorkflow } from "../utils/test-helpers";
+
+const document = (steps: object[]) => ({
+  apiVersion: "qa-playground/workflow/v1",
+  kind: "Workflow",
+  name: `imported-${Date.now()}`,
+  steps,
+});
+
+test.describe("Workflow import/export", () => {
+  test("publishes the definition schema", async ({ svcRequest, apiBase }) => {
+    const res = await svcRequest.get(`${apiBase}/v1/workflows/schema`);
+    expect(res.status()).toBe(200);
+    const schema = await res.json();
+    expect(schema.properties.apiVersion.const).toBe("qa-playground/workflow/v1");
+    expect(schema.$defs.step.properties.type.enum).toContain("http_call");
+  });
+
+  test("exported YAML and JSON import back as a new draft", async ({
+    svcRequest,
+    apiBase,
+  }) => {
+    const id = await createWorkflow(
+      svcRequest,
+      [
+        {
+          id: "ask",
+          name: "Ask",
+          type: "ai_completion",
+          config: { prompt: "Summarise {{params.topic}}", max_tokens: 64 },
+          retry: { count: 2, backoff_ms: 10 },
+        },
+        { id: "wait", name: "Wait", type: "delay", depends_on: ["ask"], config: { duration_ms: 10 } },
+      ],
+      apiBase
+    );
+
+    const json = await svcRequest.get(`${apiBase}/v1/workflows/${id}/export`);
+    expect(json.status()).toBe(200);
+    const doc = await json.json();
+    expect(doc).toMatchObject({
+      apiVersion: "qa-playground/workflow/v1",
+      kind: "Workflow",
+      source: { workflow_id: id, version: 1 },
+    });
+    expect(doc.steps.map((s: { id: string }) => s.id)).toEqual(["ask", "wait"]);
+
+    const yaml = await svcRequest.get(`${apiBase}/v1/workflows/${id}/export?format=yaml`);
+    expect(yaml.headers()["content-type"]).toContain("application/yaml");
+    const yamlBody = await yaml.text();
+    expect(yamlBody).toContain("apiVersion: qa-playground/workflow/v1");
+
+    const dryRun = await svcRequest.post(`${apiBase}/v1/workflows/import?dry_run=true`, {
+      headers: { "content-type": "application/yaml" },
+      data: yamlBody,
+    });
+    expect(dryRun.status()).toBe(200);
+    expect(await dryRun.json()).toMatchObject({ valid: true, dry_run: true });
+
+    const imported = await svcRequest.post(`${apiBase}/v1/workflows/import`, { data: doc });
+    expect(imported.status()).toBe(201);
+    const wf = await imported.json();
+    expect(wf.id).not.toBe(id);
+    expect(wf).toMatchObject({ status: "draft", version: 1 });
+    expect(wf.steps[0].retry).toEqual({ count: 2, backoff_ms: 10 });
+
+    const versions = await (
+      await svcRequest.get(`${apiBase}/v1/workflows/${wf.id}/versions`)
+    ).json();
+    expect(versions.versions[0].change).toBe("imported");
+  });
+
+  test("reports every error with its JSON pointer", async ({ svcRequest, apiBase }) => {
+    const res = await svcRequest.post(`${apiBase}/v1/workflows/import`, {
+      data: {
+        ...document([
+          { id: "call", name: "Call", type: "http_call", config: { method: "FETCH" } },
+          { id: "ask", name: "Ask", type: "ai_completion", config: { prompt: "" }, depends_on: ["nope"] },
+          { id: "x", name: "X", type: "teleport", colour: "red" },
+        ]),
+        kind: "Pipeline",
+      },
+    });
+    expect(res.status()).toBe(400);
+    const body = await res.json();
+    expect(body.valid).toBe(false);
+    const pointers = body.errors.map((e: { pointer: string }) => e.pointer);
+    expect(pointers).toEqual(
+      expect.arrayContaining([
+        "/kind",
+        "/steps/0/config",
+        "/steps/0/config/method",
+        "/steps/1/config/prompt",
+        "/steps/1/depends_on/0",
+        "/steps/2/colour",
+        "/steps/2/type",
+      ])
+    );
+  });
+
+  test("rejects cyclic definitions", async ({ svcRequest, apiBase }) => {
+    const res = await svcRequest.post(`${apiBase}/v1/workflows/import?dry_run=true`, {
+      data: document([
+        { id: "a", name: "A", type: "task", depends_on: ["b"] },
+        { id: "b", name: "B", type: "task", depends_on: ["a"] },
+      ]),
+    });
+    expect(res.status()).toBe(400);
+    const body = await res.json();
+    expect(body.errors).toHaveLength(1);
+    expect(body.errors[0].pointer).toBe("/steps");
+    expect(body.errors[0].message).toContain("circular");
+  });
+});
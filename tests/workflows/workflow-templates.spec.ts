@@ -0,0 +1,110 @@
+import { expect } from "@playwright/test";
+import { test } from "../fixtures/api-fixtures";
+import {
+  createWorkflow,
+  executeWorkflow,
+  getWorkflowStatus,
+} from "../utils/test-helpers";
+
+test.describe("Workflow step templates", () => {
+  test("resolves parameters and upstream outputs into step config", async ({
+    svcRequest,
+    apiBase,
+  }) => {
+    const id = await createWorkflow(
+      svcRequest,
+      [
+        {
+          id: "summarize",
+          name: "Summarize",
+          type: "ai_completion",
+          config: { prompt: "Summarize {{parameters.topic}}", model: "adapter-a" },
+        },
+        {
+          id: "notify",
+          name: "Notify",
+          type: "notify",
+          depends_on: ["summarize"],
+          config: {
+            recipient: "{{parameters.owner}}",
+            title: "Summary of {{parameters.topic}}",
+            message: "{{steps.summarize.output.text}}",
+          },
+        },
+      ],
+      apiBase
+    );
+    await executeWorkflow(
+      svcRequest,
+      id,
+      { parameters: { topic: "release notes", owner: "owner@example.com" } },
+      apiBase
+    );
+    await expect
+      .poll(async () => (await getWorkflowStatus(svcRequest, id, apiBase)).status)
+      .toBe("completed");
+
+    const st = await getWorkflowStatus(svcRequest, id, apiBase);
+    expect(st.steps.summarize.input.prompt).toBe("Summarize release notes");
+    const text = st.steps.summarize.output.text;
+    expect(st.steps.notify.input).toMatchObject({
+      recipient: "owner@example.com",
+      title: "Summary of release notes",
+      message: text,
+    });
+    const started = st.execution_history.find(
+      (e: any) => e.event === "step_started" && e.step_id === "notify"
+    );
+    expect(started.input.message).toBe(text);
+    const completed = st.execution_history.find(
+      (e: any) => e.event === "step_completed" && e.step_id === "summarize"
+    );
+    expect(completed.output.text).toBe(text);
+  });
+
+  test("fails the step when a parameter is missing", async ({
+    svcRequest,
+    apiBase,
+  }) => {
+    const id = await createWorkflow(
+      svcRequest,
+      [
+        {
+          id: "wait",
+          name: "Wait",
+          type: "delay",
+          config: { duration_ms: "{{parameters.delay}}" },
+        },
+      ],
+      apiBase
+    );
+    await executeWorkflow(svcRequest, id, {}, apiBase);
+    await expect
+      .poll(async () => (await getWorkflowStatus(svcRequest, id, apiBase)).error)
+      .toContain("parameters.delay");
+  });
+
+  for (const [label, steps] of [
+    [
+      "unknown step",
+      [{ id: "a", name: "A", type: "delay", config: { x: "{{steps.nope.output.text}}" } }],
+    ],
+    [
+      "step that is not a dependency",
+      [
+        { id: "a", name: "A", type: "delay", config: { x: "{{steps.b.output.text}}" } },
+        { id: "b", name: "B", type: "delay" },
+      ],
+    ],
+    ["unknown root", [{ id: "a", name: "A", type: "delay", config: { x: "{{env.HOME}}" } }]],
+    ["unterminated", [{ id: "a", name: "A", type: "delay", config: { x: "{{parameters.x" } }]],
+  ] as const) {
+    test(`rejects templates referencing an ${label}`, async ({ svcRequest, apiBase }) => {
+      const res = await svcRequest.post(`${apiBase}/v1/workflows/`, {
+        data: { name: "bad-template", steps },
+      });
+      expect(res.status()).toBe(400);
+      expect((await res.json()).error).toContain("step a");
+    });
+  }
+});
@@ -0,0 +1,122 @@
+import { expect, type APIRequestContext } from "@playwright/test";
+import { test } from "../fixtures/api-fixtures";
+import { createWorkflow, setClock } from "../utils/test-helpers";
+
+// The scheduler clock is global, so these run one at a time and only in the
+// scheduler-clock project (see playwright.config.ts).
+test.describe.serial("Workflow triggers", () => {
+  const start = "2030-01-01T00:00:00Z";
+  const steps = [{ id: "wait", name: "Wait", type: "delay", config: { duration_ms: 10 } }];
+
+  async function trigger(svcRequest: APIRequestContext, apiBase: string, id: string, tid: string) {
+    const res = await svcRequest.get(`${apiBase}/v1/workflows/${id}/triggers`);
+    return (await res.json()).triggers.find((t: any) => t.id === tid);
+  }
+
+  test.beforeEach(async ({ svcRequest, apiBase }) => {
+    await setClock(svcRequest, { now: start, frozen: true }, apiBase);
+  });
+
+  test.afterAll(async ({ svcRequest, apiBase }) => {
+    await svcRequest.delete(`${apiBase}/v1/admin/clock`);
+  });
+
+  test("only admins move the clock", async ({ userRequest, apiBase }) => {
+    const put = await userRequest.put(`${apiBase}/v1/admin/clock`, {
+      data: { advance_seconds: 3600 },
+    });
+    expect(put.status()).toBe(403);
+    expect((await userRequest.delete(`${apiBase}/v1/admin/clock`)).status()).toBe(403);
+  });
+
+  test("cron and run_at triggers fire as the clock advances", async ({
+    svcRequest,
+    apiBase,
+  }) => {
+    const id = await createWorkflow(svcRequest, steps, apiBase);
+    const base = `${apiBase}/v1/workflows/${id}`;
+    const cron = await svcRequest.post(`${base}/triggers`, {
+      data: { cron: "*/5 * * * *", parameters: { source: "cron" } },
+    });
+    expect(cron.status()).toBe(201);
+    const cj = await cron.json();
+    expect(cj).toMatchObject({ type: "cron", next_fire_at: "2030-01-01T00:05:00Z" });
+    const once = await (
+      await svcRequest.post(`${base}/triggers`, { data: { run_at: "2030-01-01T00:02:00Z" } })
+    ).json();
+
+    const wf = await (await svcRequest.get(base)).json();
+    expect(wf.next_fire_times).toEqual(["2030-01-01T00:02:00Z", "2030-01-01T00:05:00Z"]);
+
+    await setClock(svcRequest, { advance_seconds: 300 }, apiBase);
+    await expect.poll(async () => (await trigger(svcRequest, apiBase, id, cj.id)).fire_count).toBe(1);
+    const fired = await trigger(svcRequest, apiBase, id, cj.id);
+    expect(fired.next_fire_at).toBe("2030-01-01T00:10:00Z");
+    expect(await trigger(svcRequest, apiBase, id, once.id)).toMatchObject({
+      status: "completed",
+      fire_count: 1,
+    });
+
+    const exec = await (
+      await svcRequest.get(`${base}/executions/${fired.last_execution_id}`)
+    ).json();
+    expect(exec.triggered_by).toBe(`trigger:${cj.id}`);
+    expect(exec.parameters).toEqual({ source: "cron" });
+    expect(exec.context).toMatchObject({
+      trigger_id: cj.id,
+      scheduled_for: "2030-01-01T00:05:00Z",
+    });
+    expect((await (await svcRequest.get(base)).json()).next_fire_times).toEqual([
+      "2030-01-01T00:10:00Z",
+    ]);
+  });
+
+  test("missed runs follow the trigger's policy", async ({ svcRequest, apiBase }) => {
+    const id = await createWorkflow(svcRequest, steps, apiBase);
+    const ids: Record<string, string> = {};
+    for (const policy of ["skip", "run_once", "run_all"]) {
+      const res = await svcRequest.post(`${apiBase}/v1/workflows/${id}/triggers`, {
+        data: { cron: "*/5 * * * *", missed_run_policy: policy },
+      });
+      ids[policy] = (await res.json()).id;
+    }
+    // 00:05 through 00:55 are all more than a minute late at 00:59:50
+    await setClock(svcRequest, { advance_seconds: 3590 }, apiBase);
+    await expect
+      .poll(async () => (await trigger(svcRequest, apiBase, id, ids.run_all)).fire_count)
+      .toBe(11);
+    expect(await trigger(svcRequest, apiBase, id, ids.run_once)).toMatchObject({
+      fire_count: 1,
+      missed_count: 10,
+      last_fired_at: "2030-01-01T00:55:00Z",
+    });
+    expect(await trigger(svcRequest, apiBase, id, ids.skip)).toMatchObject({
+      fire_count: 0,
+      missed_count: 11,
+      next_fire_at: "2030-01-01T01:00:00Z",
+    });
+  });
+
+  test("rejects invalid schedules and deletes triggers", async ({ svcRequest, apiBase }) => {
+    const id = await createWorkflow(svcRequest, steps, apiBase);
+    const base = `${apiBase}/v1/workflows/${id}/triggers`;
+    for (const data of [
+      { cron: "61 * * * *" },
+      { cron: "0 0 30 2 *" },
+      { run_at: "2029-12-31T23:00:00Z" },
+      {},
+      { cron: "@daily", run_at: "2031-01-01T00:00:00Z" },
+      { cron: "@hourly", missed_run_policy: "sometimes" },
+    ]) {
+      expect((await svcRequest.post(base, { data })).status()).toBe(400);
+    }
+
+    const t = await (await svcRequest.post(base, { data: { cron: "@daily" } })).json();
+    expect(t.next_fire_at).toBe("2030-01-02T00:00:00Z");
+    expect((await svcRequest.delete(`${base}/${t.id}`)).status()).toBe(200);
+    expect((await svcRequest.delete(`${base}/${t.id}`)).status()).toBe(404);
+    expect((await (await svcRequest.get(base)).json()).total).toBe(0);
+    const wf = await (await svcRequest.get(`${apiBase}/v1/workflows/${id}`)).json();
+    expect(wf.next_fire_times).toBeUndefined();
+  });
+});
@@ -0,0 +1,138 @@
+import { expect } from "@playwright/test";
+import { test } from "../fixtures/api-fixtures";
+import { createWorkflow, executeWorkflow } from "../utils/test-helpers";
+
+test.describe("Workflow versions", () => {
+  test("updates create versions that can be diffed and rolled back", async ({
+    svcRequest,
+    apiBase,
+  }) => {
+    const created = await svcRequest.post(`${apiBase}/v1/workflows/`, {
+      data: {
+        name: `versions-${Date.now()}`,
+        steps: [{ id: "review", name: "Review", type: "approval" }],
+        metadata: { team: "qa" },
+      },
+    });
+    expect(created.status()).toBe(201);
+    const wf = await created.json();
+    expect(wf.version).toBe(1);
+    const base = `${apiBase}/v1/workflows/${wf.id}`;
+
+    const updated = await svcRequest.put(base, {
+      data: {
+        steps: [
+          { id: "review", name: "Peer review", type: "approval" },
+          { id: "wait", name: "Wait", type: "delay" },
+        ],
+        metadata: { team: "ops" },
+      },
+    });
+    expect((await updated.json()).version).toBe(2);
+
+    const versions = await (await svcRequest.get(`${base}/versions`)).json();
+    expect(versions.current_version).toBe(2);
+    expect(versions.versions.map((v: any) => v.change)).toEqual(["created", "updated"]);
+
+    const diff = await svcRequest.get(`${base}/versions/diff?from=1&to=2`);
+    expect(diff.status()).toBe(200);
+    const dj = await diff.json();
+    expect(dj.steps.added).toEqual(["wait"]);
+    expect(dj.steps.changed[0].id).toBe("review");
+    expect(dj.steps.changed[0].fields.changed.name).toEqual({
+      from: "Review",
+      to: "Peer review",
+    });
+    expect(dj.metadata.changed.team).toEqual({ from: "qa", to: "ops" });
+
+    const rollback = await svcRequest.post(`${base}/rollback`, { data: { version: 1 } });
+    expect(rollback.status()).toBe(200);
+    const rj = await rollback.json();
+    expect(rj.version).toBe(3);
+    expect(rj.steps).toHaveLength(1);
+    const v3 = await (await svcRequest.get(`${base}/versions/3`)).json();
+    expect(v3).toMatchObject({ change: "rollback", rolled_back_to: 1 });
+
+    expect((await svcRequest.get(`${base}/versions/42`)).status()).toBe(404);
+    expect(
+      (await svcRequest.post(`${base}/rollback`, { data: { version: 42 } })).status()
+    ).toBe(404);
+  });
+
+  test("executions stay on the version they started on", async ({
+    svcRequest,
+    apiBase,
+  }) => {
+    const id = await createWorkflow(
+      svcRequest,
+      [{ id: "review", name: "Review", type: "approval" }],
+      apiBase
+    );
+    const execId = await executeWorkflow(svcRequest, id, {}, apiBase);
+
+    await svcRequest.put(`${apiBase}/v1/workflows/${id}`, {
+      data: {
+        steps: [
+          { id: "review", name: "Review", type: "approval" },
+          { id: "extra", name: "Extra", type: "delay", depends_on: ["review"] },
+        ],
+      },
+    });
+    await svcRequest.post(`${apiBase}/v1/workflows/${id}/approve`, {
+      data: { execution_id: execId, approver: "a@example.com", decision: "approved" },
+    });
+    await expect
+      .poll(async () => {
+        const r = await svcRequest.get(`${apiBase}/v1/workflows/${id}/executions/${execId}`);
+        return (await r.json()).status;
+      })
+      .toBe("completed");
+    const ex = await (
+      await svcRequest.get(`${apiBase}/v1/workflows/${id}/executions/${execId}`)
+    ).json();
+    expect(ex.workflow_version).toBe(1);
+    expect(Object.keys(ex.steps)).toEqual(["review"]);
+  });
+
+  test("publish moves a draft to active once", async ({ svcRequest, apiBase }) => {
+    const id = await createWorkflow(svcRequest, [{ id: "s", name: "S", type: "delay" }], apiBase);
+    const published = await svcRequest.post(`${apiBase}/v1/workflows/${id}/publish`);
+    expect(published.status()).toBe(200);
+    expect(await published.json()).toMatchObject({ status: "active", published_version: 1 });
+    const again = await svcRequest.post(`${apiBase}/v1/workflows/${id}/publish`);
+    expect(again.status()).toBe(409);
+  });
+
+  test("edits after publish execute only once published", async ({
+    svcRequest,
+    apiBase,
+  }) => {
+    const id = await createWorkflow(svcRequest, [{ id: "s", name: "S", type: "delay" }], apiBase);
+    const base = `${apiBase}/v1/workflows/${id}`;
+    expect((await svcRequest.post(`${base}/publish`)).status()).toBe(200);
+
+    const edited = await svcRequest.put(base, {
+      data: {
+        steps: [
+          { id: "s", name: "S", type: "delay" },
+          { id: "t", name: "T", type: "delay", depends_on: ["s"] },
+        ],
+      },
+    });
+    expect(await edited.json()).toMatchObject({
+      status: "active",
+      version: 2,
+      published_version: 1,
+    });
+
+    const before = await (await svcRequest.post(`${base}/execute`, { data: {} })).json();
+    expect(before.workflow_version).toBe(1);
+
+    const republished = await svcRequest.post(`${base}/publish`);
+    expect(republished.status()).toBe(200);
+    expect(await republished.json()).toMatchObject({ status: "active", published_version: 2 });
+
+    const after = await (await svcRequest.post(`${base}/execute`, { data: {} })).json();
+    expect(after.workflow_version).toBe(2);
+  });
+});